@@ -30,7 +30,11 @@ func SetUpPostgreSQLConnection() *gorm.DB {
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		DSN:                  dsn,
 		PreferSimpleProtocol: true,
-	}), &gorm.Config{})
+	}), &gorm.Config{
+		// Report unique violations as gorm.ErrDuplicatedKey so services can
+		// tell them apart from other failures.
+		TranslateError: true,
+	})
 	if err != nil {
 		panic(fmt.Errorf("failed to connect postgres: %v", err))
 	}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/mferdian/golang_boiller_plate/migrations"
 	"github.com/mferdian/golang_boiller_plate/model"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -14,19 +15,44 @@ import (
 func SetupTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		t.Fatalf("failed to open sqlite db: %v", err)
 	}
 
+	// Every connection to :memory: opens a new, empty database, so keep to
+	// one. Foreign keys are enforced as they are on PostgreSQL.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sqlite connection: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+		t.Fatalf("failed to enable foreign keys: %v", err)
+	}
+
 	// Auto migrate schema
 	err = db.AutoMigrate(
 		&model.User{},
+		&model.DataExport{},
+		&model.Address{},
+		&model.UserPreference{},
+		&model.Invitation{},
+		&model.CustomField{},
+		&model.UserCustomFieldValue{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate db: %v", err)
 	}
 
+	if err := migrations.CreateUserSearchIndexes(db); err != nil {
+		t.Fatalf("failed to create search indexes: %v", err)
+	}
+
+	if err := migrations.CreateUserUniqueIndexes(db); err != nil {
+		t.Fatalf("failed to create unique indexes: %v", err)
+	}
+
 	return db
 }
 
@@ -4,9 +4,105 @@ const (
 	ENUM_ROLE_ADMIN = "admin"
 	ENUM_ROLE_USER  = "user"
 
+	ENUM_USER_STATUS_ACTIVE    = "active"
+	ENUM_USER_STATUS_SUSPENDED = "suspended"
+	ENUM_USER_STATUS_BANNED    = "banned"
+
+	ENUM_SUSPENSION_EXPIRY_INTERVAL_MINUTE = 5
+
+	ENUM_PHONE_DEFAULT_REGION = "ID"
+
+	ENUM_LAST_SEEN_THROTTLE_MINUTE = 5
+	ENUM_LAST_SEEN_CACHE_SIZE      = 10000
+
+	ENUM_USER_STATUS_CACHE_SECOND = 30
+	ENUM_USER_STATUS_CACHE_SIZE   = 10000
+
+	ENUM_ADDRESS_TYPE_HOME     = "home"
+	ENUM_ADDRESS_TYPE_BILLING  = "billing"
+	ENUM_ADDRESS_TYPE_SHIPPING = "shipping"
+
+	ENUM_THEME_LIGHT  = "light"
+	ENUM_THEME_DARK   = "dark"
+	ENUM_THEME_SYSTEM = "system"
+
+	ENUM_INVITATION_STATUS_PENDING  = "pending"
+	ENUM_INVITATION_STATUS_ACCEPTED = "accepted"
+	ENUM_INVITATION_STATUS_REVOKED  = "revoked"
+	ENUM_INVITATION_STATUS_EXPIRED  = "expired"
+	ENUM_INVITATION_TTL_HOURS       = 72
+
+	ENUM_CUSTOM_FIELD_TYPE_STRING  = "string"
+	ENUM_CUSTOM_FIELD_TYPE_NUMBER  = "number"
+	ENUM_CUSTOM_FIELD_TYPE_BOOLEAN = "boolean"
+	ENUM_CUSTOM_FIELD_TYPE_DATE    = "date"
+	ENUM_CUSTOM_FIELD_TYPE_ENUM    = "enum"
+	ENUM_CUSTOM_FIELD_DATE_FORMAT  = "2006-01-02"
+
+	ENUM_DEFAULT_LOCALE   = "en"
+	ENUM_DEFAULT_TIMEZONE = "UTC"
+
+	ENUM_APP_URL                = "http://localhost:8000"
+	ENUM_EMAIL_CHANGE_TTL_HOURS = 24
+
 	ENUM_RUN_PRODUCTION = "production"
 	ENUM_RUN_TESTING    = "testing"
 
-	ENUM_PAGINATION_LIMIT = 10
-	ENUM_PAGINATION_PAGE  = 1
-)
\ No newline at end of file
+	ENUM_API_VERSION_1       = "v1"
+	ENUM_API_VERSION_2       = "v2"
+	ENUM_API_VERSION_DEFAULT = ENUM_API_VERSION_1
+
+	ENUM_PAGINATION_LIMIT        = 10
+	ENUM_PAGINATION_PAGE         = 1
+	ENUM_PAGINATION_MAX_PER_PAGE = 100
+
+	ENUM_FILTER_OPERATOR_EQ       = "eq"
+	ENUM_FILTER_OPERATOR_NE       = "ne"
+	ENUM_FILTER_OPERATOR_GT       = "gt"
+	ENUM_FILTER_OPERATOR_GTE      = "gte"
+	ENUM_FILTER_OPERATOR_LT       = "lt"
+	ENUM_FILTER_OPERATOR_LTE      = "lte"
+	ENUM_FILTER_OPERATOR_IN       = "in"
+	ENUM_FILTER_OPERATOR_NIN      = "nin"
+	ENUM_FILTER_OPERATOR_CONTAINS = "contains"
+	ENUM_FILTER_OPERATOR_NULL     = "null"
+	ENUM_FILTER_DATE_FORMAT       = "2006-01-02"
+
+	ENUM_ACCOUNT_DELETION_GRACE_DAYS     = 30
+	ENUM_ACCOUNT_ERASURE_INTERVAL_MINUTE = 60
+	ENUM_ACCOUNT_ERASURE_BATCH_SIZE      = 100
+	ENUM_ANONYMIZED_NAME                 = "Deleted User"
+	ENUM_ANONYMIZED_EMAIL_DOMAIN         = "anonymized.invalid"
+
+	ENUM_DATA_EXPORT_STATUS_PENDING = "pending"
+	ENUM_DATA_EXPORT_STATUS_READY   = "ready"
+	ENUM_DATA_EXPORT_STATUS_FAILED  = "failed"
+	ENUM_DATA_EXPORT_TTL_HOURS      = 48
+	ENUM_DATA_EXPORT_DIR            = "storage/exports"
+
+	ENUM_DATA_EXPORT_CLEANUP_INTERVAL_MINUTE = 60
+	ENUM_DATA_EXPORT_CLEANUP_BATCH_SIZE      = 100
+	ENUM_DATA_EXPORT_PENDING_TIMEOUT_MINUTE  = 30
+
+	ENUM_IMPORT_FORMAT_CSV        = "csv"
+	ENUM_IMPORT_FORMAT_JSON       = "json"
+	ENUM_IMPORT_MODE_ATOMIC       = "all_or_nothing"
+	ENUM_IMPORT_MODE_BEST_EFFORT  = "best_effort"
+	ENUM_IMPORT_STATUS_CREATED    = "created"
+	ENUM_IMPORT_STATUS_SKIPPED    = "skipped"
+	ENUM_IMPORT_STATUS_FAILED     = "failed"
+	ENUM_IMPORT_MAX_ROWS          = 1000
+	ENUM_IMPORT_MAX_FILE_SIZE_MiB = 5
+
+	ENUM_EXPORT_FORMAT_CSV    = "csv"
+	ENUM_EXPORT_FORMAT_NDJSON = "ndjson"
+	ENUM_EXPORT_FORMAT_XLSX   = "xlsx"
+	ENUM_EXPORT_BATCH_SIZE    = 500
+
+	ENUM_BULK_MAX_ITEMS        = 500
+	ENUM_BULK_STATUS_UPDATED   = "updated"
+	ENUM_BULK_STATUS_DELETED   = "deleted"
+	ENUM_BULK_STATUS_NOT_FOUND = "not_found"
+	ENUM_BULK_STATUS_SKIPPED   = "skipped"
+	ENUM_BULK_STATUS_FAILED    = "failed"
+)
@@ -0,0 +1,43 @@
+package constants
+
+import "errors"
+
+// ErrorKind sorts application errors into the classes a client can act on;
+// the HTTP status of a failed request follows from it.
+type ErrorKind string
+
+const (
+	ENUM_ERROR_KIND_VALIDATION   ErrorKind = "validation"
+	ENUM_ERROR_KIND_NOT_FOUND    ErrorKind = "not_found"
+	ENUM_ERROR_KIND_CONFLICT     ErrorKind = "conflict"
+	ENUM_ERROR_KIND_UNAUTHORIZED ErrorKind = "unauthorized"
+	ENUM_ERROR_KIND_FORBIDDEN    ErrorKind = "forbidden"
+	ENUM_ERROR_KIND_GONE         ErrorKind = "gone"
+	ENUM_ERROR_KIND_INTERNAL     ErrorKind = "internal"
+)
+
+// Error is an application error. Code is stable for clients to branch on,
+// and Message is safe to show them.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorOf finds the application error in err's chain. Errors that carry
+// none, such as a database driver error returned as is, are internal.
+func ErrorOf(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return ErrInternal
+}
@@ -1,63 +1,233 @@
 package constants
 
-import "errors"
-
 const (
-	MESSAGE_FAILED_PROSES_REQUEST      = "failed proses request"
-	MESSAGE_FAILED_ACCESS_DENIED       = "failed access denied"
-	MESSAGE_FAILED_TOKEN_NOT_FOUND     = "failed token not found"
-	MESSAGE_FAILED_TOKEN_NOT_VALID     = "failed token not valid"
-	MESSAGE_FAILED_TOKEN_DENIED_ACCESS = "failed token denied access"
-	MESSAGE_FAILED_GET_DATA_FROM_BODY  = "failed get data from body"
-	MESSAGE_FAILED_CREATE_USER         = "failed create user"
-	MESSAGE_FAILED_GET_DETAIL_USER     = "failed get detail user"
-	MESSAGE_FAILED_GET_LIST_USER       = "failed get list user"
-	MESSAGE_FAILED_UPDATE_USER         = "failed update user"
-	MESSAGE_FAILED_DELETE_USER         = "failed delete user"
-	MESSAGE_FAILED_LOGIN_USER          = "failed login user"
-	MESSAGE_FAILED_UUID_FORMAT         = "failed uuid format"
-	MESSAGE_FAILED_REGISTER            = "failed register"
-	MESSAGE_SUCCESS_REGISTER           = "success register"
-	MESSAGE_FAILED_CREATE_PROPOSAL     = "failed create proposal"
+	MESSAGE_FAILED_PROSES_REQUEST        = "failed proses request"
+	MESSAGE_FAILED_ACCESS_DENIED         = "failed access denied"
+	MESSAGE_FAILED_TOKEN_NOT_FOUND       = "failed token not found"
+	MESSAGE_FAILED_TOKEN_NOT_VALID       = "failed token not valid"
+	MESSAGE_FAILED_TOKEN_DENIED_ACCESS   = "failed token denied access"
+	MESSAGE_FAILED_GET_DATA_FROM_BODY    = "failed get data from body"
+	MESSAGE_FAILED_VALIDATE_REQUEST      = "failed validate request"
+	MESSAGE_FAILED_CREATE_USER           = "failed create user"
+	MESSAGE_FAILED_GET_DETAIL_USER       = "failed get detail user"
+	MESSAGE_FAILED_GET_PROFILE           = "failed get profile"
+	MESSAGE_FAILED_RECORD_ACTIVITY       = "failed record user activity"
+	MESSAGE_FAILED_GET_LIST_USER         = "failed get list user"
+	MESSAGE_FAILED_UPDATE_USER           = "failed update user"
+	MESSAGE_FAILED_DELETE_USER           = "failed delete user"
+	MESSAGE_FAILED_LOGIN_USER            = "failed login user"
+	MESSAGE_FAILED_UUID_FORMAT           = "failed uuid format"
+	MESSAGE_FAILED_REGISTER              = "failed register"
+	MESSAGE_SUCCESS_REGISTER             = "success register"
+	MESSAGE_FAILED_CREATE_PROPOSAL       = "failed create proposal"
+	MESSAGE_FAILED_GET_LIST_TRASH_USER   = "failed get list trashed user"
+	MESSAGE_FAILED_RESTORE_USER          = "failed restore user"
+	MESSAGE_FAILED_PURGE_USER            = "failed purge user"
+	MESSAGE_FAILED_REQUEST_DELETION      = "failed request account deletion"
+	MESSAGE_FAILED_ERASE_ACCOUNT         = "failed erase account"
+	MESSAGE_FAILED_REQUEST_DATA_EXPORT   = "failed request data export"
+	MESSAGE_FAILED_BUILD_DATA_EXPORT     = "failed build data export"
+	MESSAGE_FAILED_GET_DATA_EXPORT       = "failed get data export"
+	MESSAGE_FAILED_REMOVE_DATA_EXPORT    = "failed remove data export archive"
+	MESSAGE_FAILED_CLEAN_UP_DATA_EXPORT  = "failed clean up data export"
+	MESSAGE_FAILED_IMPORT_USER           = "failed import user"
+	MESSAGE_FAILED_EXPORT_USER           = "failed export user"
+	MESSAGE_FAILED_BULK_UPDATE_USER      = "failed bulk update user"
+	MESSAGE_FAILED_BULK_DELETE_USER      = "failed bulk delete user"
+	MESSAGE_FAILED_UPDATE_USER_STATUS    = "failed update user status"
+	MESSAGE_FAILED_ACCOUNT_INACTIVE      = "failed account not active"
+	MESSAGE_FAILED_SEND_EMAIL            = "failed send email"
+	MESSAGE_FAILED_CONFIRM_EMAIL         = "failed confirm email change"
+	MESSAGE_FAILED_GET_LIST_ADDRESS      = "failed get list address"
+	MESSAGE_FAILED_CREATE_ADDRESS        = "failed create address"
+	MESSAGE_FAILED_UPDATE_ADDRESS        = "failed update address"
+	MESSAGE_FAILED_DELETE_ADDRESS        = "failed delete address"
+	MESSAGE_FAILED_GET_PREFERENCES       = "failed get preferences"
+	MESSAGE_FAILED_UPDATE_PREFERENCES    = "failed update preferences"
+	MESSAGE_FAILED_CREATE_INVITATION     = "failed create invitation"
+	MESSAGE_FAILED_GET_LIST_INVITATION   = "failed get list invitation"
+	MESSAGE_FAILED_RESEND_INVITATION     = "failed resend invitation"
+	MESSAGE_FAILED_REVOKE_INVITATION     = "failed revoke invitation"
+	MESSAGE_FAILED_ACCEPT_INVITATION     = "failed accept invitation"
+	MESSAGE_FAILED_CREATE_CUSTOM_FIELD   = "failed create custom field"
+	MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD = "failed get list custom field"
+	MESSAGE_FAILED_UPDATE_CUSTOM_FIELD   = "failed update custom field"
+	MESSAGE_FAILED_DELETE_CUSTOM_FIELD   = "failed delete custom field"
 
 	MESSAGE_SUCCESS_CREATE_USER     = "success create user"
 	MESSAGE_SUCCESS_GET_DETAIL_USER = "success get detail user"
+	MESSAGE_SUCCESS_GET_PROFILE     = "success get profile"
 	MESSAGE_SUCCESS_GET_LIST_USER   = "success get list user"
 	MESSAGE_SUCCESS_UPDATE_USER     = "success update user"
 	MESSAGE_SUCCESS_DELETE_USER     = "success delete user"
 	MESSAGE_SUCCESS_LOGIN_USER      = "success login user"
+
+	MESSAGE_SUCCESS_GET_LIST_TRASH_USER   = "success get list trashed user"
+	MESSAGE_SUCCESS_RESTORE_USER          = "success restore user"
+	MESSAGE_SUCCESS_PURGE_USER            = "success purge user"
+	MESSAGE_SUCCESS_REQUEST_DELETION      = "success request account deletion"
+	MESSAGE_SUCCESS_CANCEL_DELETION       = "success cancel account deletion"
+	MESSAGE_SUCCESS_ERASE_ACCOUNT         = "success erase account"
+	MESSAGE_SUCCESS_REQUEST_DATA_EXPORT   = "success request data export"
+	MESSAGE_SUCCESS_BUILD_DATA_EXPORT     = "success build data export"
+	MESSAGE_SUCCESS_GET_DATA_EXPORT       = "success get data export"
+	MESSAGE_SUCCESS_CLEAN_UP_DATA_EXPORT  = "success clean up data export"
+	MESSAGE_DATA_EXPORT_NOT_READY         = "data export not ready yet"
+	MESSAGE_SUCCESS_IMPORT_USER           = "success import user"
+	MESSAGE_SUCCESS_EXPORT_USER           = "success export user"
+	MESSAGE_SUCCESS_BULK_UPDATE_USER      = "success bulk update user"
+	MESSAGE_SUCCESS_BULK_DELETE_USER      = "success bulk delete user"
+	MESSAGE_SUCCESS_UPDATE_USER_STATUS    = "success update user status"
+	MESSAGE_SUCCESS_REACTIVATE_USER       = "success reactivate user"
+	MESSAGE_SUCCESS_REQUEST_EMAIL         = "success request email change, check the new address to confirm it"
+	MESSAGE_SUCCESS_CONFIRM_EMAIL         = "success confirm email change"
+	MESSAGE_SUCCESS_GET_LIST_ADDRESS      = "success get list address"
+	MESSAGE_SUCCESS_CREATE_ADDRESS        = "success create address"
+	MESSAGE_SUCCESS_UPDATE_ADDRESS        = "success update address"
+	MESSAGE_SUCCESS_DELETE_ADDRESS        = "success delete address"
+	MESSAGE_SUCCESS_GET_PREFERENCES       = "success get preferences"
+	MESSAGE_SUCCESS_UPDATE_PREFERENCES    = "success update preferences"
+	MESSAGE_SUCCESS_CREATE_INVITATION     = "success create invitation"
+	MESSAGE_SUCCESS_GET_LIST_INVITATION   = "success get list invitation"
+	MESSAGE_SUCCESS_RESEND_INVITATION     = "success resend invitation"
+	MESSAGE_SUCCESS_REVOKE_INVITATION     = "success revoke invitation"
+	MESSAGE_SUCCESS_ACCEPT_INVITATION     = "success accept invitation"
+	MESSAGE_SUCCESS_CREATE_CUSTOM_FIELD   = "success create custom field"
+	MESSAGE_SUCCESS_GET_LIST_CUSTOM_FIELD = "success get list custom field"
+	MESSAGE_SUCCESS_UPDATE_CUSTOM_FIELD   = "success update custom field"
+	MESSAGE_SUCCESS_DELETE_CUSTOM_FIELD   = "success delete custom field"
 )
 
 var (
-	ErrGenerateAccessToken      = errors.New("failed to generate access token")
-	ErrGenerateRefreshToken     = errors.New("failed to generate refresh token")
-	ErrUnexpectedSigningMethod  = errors.New("unexpected signing method")
-	ErrDecryptToken             = errors.New("failed to decrypt token")
-	ErrTokenInvalid             = errors.New("token invalid")
-	ErrValidateToken            = errors.New("failed to validate token")
-	ErrInvalidName              = errors.New("failed invalid name")
-	ErrInvalidEmail             = errors.New("failed invalid email")
-	ErrInvalidPassword          = errors.New("failed invalid password")
-	ErrEmailAlreadyExists       = errors.New("email already exists")
-	ErrRegisterUser             = errors.New("failed to register user")
-	ErrGetAllUserWithPagination = errors.New("failed get list user with pagination")
-	ErrGetUserByID              = errors.New("failed get user by id")
-	ErrUpdateUser               = errors.New("failed to update user")
-	ErrPasswordSame             = errors.New("failed new password same as old password")
-	ErrHashPassword             = errors.New("failed hash password")
-	ErrDeleteUserByID           = errors.New("failed delete user by id")
-	ErrEmailNotFound            = errors.New("email not found")
-	ErrPasswordNotMatch         = errors.New("password not match")
-	ErrDeniedAccess             = errors.New("denied access")
-	ErrGetPermissionsByRoleID   = errors.New("failed get all permission by role id")
-	ErrInvalidPhoneNumber       = errors.New("invalid phone number")
-	ErrInvalidLoginCredential   = errors.New("invalid login credential")
-	ErrCreateUser               = errors.New("failed to create user")
-	ErrInvalidUUID              = errors.New("invalid uuid")
-	ErrGetIDFromToken           = errors.New("failed to get id from token")
-	ErrContext                  = errors.New("context error")
-	ErrInvalidProposalName      = errors.New("invalid proposal name")
-	ErrCreateProposal           = errors.New("failed to create proposal")
-	ErrGetAllUser               = errors.New("failed get all users")
-	ErrInternal                 = errors.New("error internal server error")
+	ErrGenerateAccessToken      = NewError(ENUM_ERROR_KIND_INTERNAL, "generate_access_token", "failed to generate access token")
+	ErrGenerateRefreshToken     = NewError(ENUM_ERROR_KIND_INTERNAL, "generate_refresh_token", "failed to generate refresh token")
+	ErrUnexpectedSigningMethod  = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "unexpected_signing_method", "unexpected signing method")
+	ErrDecryptToken             = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "decrypt_token", "failed to decrypt token")
+	ErrTokenInvalid             = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "token_invalid", "token invalid")
+	ErrValidateToken            = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "validate_token", "failed to validate token")
+	ErrInvalidName              = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_name", "failed invalid name")
+	ErrInvalidEmail             = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_email", "failed invalid email")
+	ErrInvalidPassword          = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_password", "failed invalid password")
+	ErrEmailAlreadyExists       = NewError(ENUM_ERROR_KIND_CONFLICT, "email_already_exists", "email already exists")
+	ErrRegisterUser             = NewError(ENUM_ERROR_KIND_INTERNAL, "register_user", "failed to register user")
+	ErrGetAllUserWithPagination = NewError(ENUM_ERROR_KIND_INTERNAL, "get_all_user_with_pagination", "failed get list user with pagination")
+	ErrGetUserByID              = NewError(ENUM_ERROR_KIND_INTERNAL, "get_user_by_id", "failed get user by id")
+	ErrUserNotFound             = NewError(ENUM_ERROR_KIND_NOT_FOUND, "user_not_found", "user not found")
+	ErrUpdateUser               = NewError(ENUM_ERROR_KIND_INTERNAL, "update_user", "failed to update user")
+	ErrPasswordSame             = NewError(ENUM_ERROR_KIND_VALIDATION, "password_same", "failed new password same as old password")
+	ErrHashPassword             = NewError(ENUM_ERROR_KIND_INTERNAL, "hash_password", "failed hash password")
+	ErrDeleteUserByID           = NewError(ENUM_ERROR_KIND_INTERNAL, "delete_user_by_id", "failed delete user by id")
+	ErrEmailNotFound            = NewError(ENUM_ERROR_KIND_NOT_FOUND, "email_not_found", "email not found")
+	ErrPasswordNotMatch         = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "password_not_match", "password not match")
+	ErrDeniedAccess             = NewError(ENUM_ERROR_KIND_FORBIDDEN, "denied_access", "denied access")
+	ErrGetPermissionsByRoleID   = NewError(ENUM_ERROR_KIND_INTERNAL, "get_permissions_by_role_id", "failed get all permission by role id")
+	ErrInvalidPhoneNumber       = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_phone_number", "invalid phone number")
+	ErrPhoneNumberAlreadyExists = NewError(ENUM_ERROR_KIND_CONFLICT, "phone_number_already_exists", "phone number already exists")
+	ErrInvalidLoginCredential   = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "invalid_login_credential", "invalid login credential")
+	ErrCreateUser               = NewError(ENUM_ERROR_KIND_INTERNAL, "create_user", "failed to create user")
+	ErrInvalidUUID              = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_uuid", "invalid uuid")
+	ErrInvalidInactiveDays      = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_inactive_days", "inactive_days must not be negative")
+	ErrInvalidSortField         = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_sort_field", "invalid sort field")
+	ErrInvalidCursor            = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_cursor", "invalid cursor")
+	ErrInvalidPagination        = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_pagination", "invalid pagination")
+	ErrUnsupportedAPIVersion    = NewError(ENUM_ERROR_KIND_VALIDATION, "unsupported_api_version", "unsupported api version, use 1 or 2")
+	ErrInvalidFilter            = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_filter", "invalid filter")
+	ErrInvalidField             = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_field", "invalid field")
+	ErrInvalidExpand            = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_expand", "invalid expand")
+	ErrGetIDFromToken           = NewError(ENUM_ERROR_KIND_UNAUTHORIZED, "get_id_from_token", "failed to get id from token")
+	ErrContext                  = NewError(ENUM_ERROR_KIND_INTERNAL, "context", "context error")
+	ErrInvalidProposalName      = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_proposal_name", "invalid proposal name")
+	ErrCreateProposal           = NewError(ENUM_ERROR_KIND_INTERNAL, "create_proposal", "failed to create proposal")
+	ErrGetAllUser               = NewError(ENUM_ERROR_KIND_INTERNAL, "get_all_user", "failed get all users")
+	ErrInternal                 = NewError(ENUM_ERROR_KIND_INTERNAL, "internal", "error internal server error")
+	ErrValidationFailed         = NewError(ENUM_ERROR_KIND_VALIDATION, "validation_failed", "request failed validation")
+	ErrMalformedRequest         = NewError(ENUM_ERROR_KIND_VALIDATION, "malformed_request", "request body or query could not be read")
+	ErrGetAllTrashedUser        = NewError(ENUM_ERROR_KIND_INTERNAL, "get_all_trashed_user", "failed get list trashed user")
+	ErrUserNotInTrash           = NewError(ENUM_ERROR_KIND_NOT_FOUND, "user_not_in_trash", "user not found in trash")
+	ErrRestoreUser              = NewError(ENUM_ERROR_KIND_INTERNAL, "restore_user", "failed to restore user")
+	ErrRestoreEmailConflict     = NewError(ENUM_ERROR_KIND_CONFLICT, "restore_email_conflict", "email already used by an active user")
+	ErrPurgeUser                = NewError(ENUM_ERROR_KIND_INTERNAL, "purge_user", "failed to purge user")
+	ErrRestoreAnonymizedUser    = NewError(ENUM_ERROR_KIND_CONFLICT, "restore_anonymized_user", "anonymized user cannot be restored")
+	ErrRequestAccountDeletion   = NewError(ENUM_ERROR_KIND_INTERNAL, "request_account_deletion", "failed to request account deletion")
+	ErrCancelAccountDeletion    = NewError(ENUM_ERROR_KIND_INTERNAL, "cancel_account_deletion", "failed to cancel account deletion")
+	ErrGetUsersDueForErasure    = NewError(ENUM_ERROR_KIND_INTERNAL, "get_users_due_for_erasure", "failed get users due for erasure")
+	ErrCreateDataExport         = NewError(ENUM_ERROR_KIND_INTERNAL, "create_data_export", "failed to create data export")
+	ErrDataExportNotFound       = NewError(ENUM_ERROR_KIND_NOT_FOUND, "data_export_not_found", "data export not found")
+	ErrDataExportExpired        = NewError(ENUM_ERROR_KIND_GONE, "data_export_expired", "data export expired")
+	ErrDataExportFailed         = NewError(ENUM_ERROR_KIND_INTERNAL, "data_export_failed", "data export failed")
+	ErrCleanUpDataExport        = NewError(ENUM_ERROR_KIND_INTERNAL, "clean_up_data_export", "failed to clean up data exports")
+	ErrInvalidImportFormat      = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_import_format", "invalid import format, use csv or json")
+	ErrInvalidImportMode        = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_import_mode", "invalid import mode, use all_or_nothing or best_effort")
+	ErrParseImportFile          = NewError(ENUM_ERROR_KIND_VALIDATION, "parse_import_file", "failed to parse import file")
+	ErrEmptyImportFile          = NewError(ENUM_ERROR_KIND_VALIDATION, "empty_import_file", "import file has no rows")
+	ErrImportTooManyRows        = NewError(ENUM_ERROR_KIND_VALIDATION, "import_too_many_rows", "import file has too many rows")
+	ErrImportUser               = NewError(ENUM_ERROR_KIND_INTERNAL, "import_user", "failed to import user")
+	ErrInvalidRole              = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_role", "invalid role")
+	ErrDuplicateEmailInImport   = NewError(ENUM_ERROR_KIND_VALIDATION, "duplicate_email_in_import", "duplicate email in import file")
+	ErrInvalidExportFormat      = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_export_format", "invalid export format, use csv, ndjson or xlsx")
+	ErrExportUser               = NewError(ENUM_ERROR_KIND_INTERNAL, "export_user", "failed to export user")
+	ErrBulkInvalidSelection     = NewError(ENUM_ERROR_KIND_VALIDATION, "bulk_invalid_selection", "provide either ids or a non-empty filter")
+	ErrBulkTooManyItems         = NewError(ENUM_ERROR_KIND_VALIDATION, "bulk_too_many_items", "bulk operation exceeds the maximum batch size")
+	ErrBulkNoFields             = NewError(ENUM_ERROR_KIND_VALIDATION, "bulk_no_fields", "no fields to update")
+	ErrBulkSelf                 = NewError(ENUM_ERROR_KIND_FORBIDDEN, "bulk_self", "cannot bulk delete your own account")
+	ErrBulkUpdateUser           = NewError(ENUM_ERROR_KIND_INTERNAL, "bulk_update_user", "failed to bulk update user")
+	ErrBulkDeleteUser           = NewError(ENUM_ERROR_KIND_INTERNAL, "bulk_delete_user", "failed to bulk delete user")
+	ErrInvalidUserStatus        = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_user_status", "invalid user status")
+	ErrInvalidStatusTransition  = NewError(ENUM_ERROR_KIND_CONFLICT, "invalid_status_transition", "status transition not allowed")
+	ErrStatusReasonRequired     = NewError(ENUM_ERROR_KIND_VALIDATION, "status_reason_required", "reason is required for this status")
+	ErrInvalidSuspendedUntil    = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_suspended_until", "suspended_until must be in the future")
+	ErrChangeOwnStatus          = NewError(ENUM_ERROR_KIND_FORBIDDEN, "change_own_status", "cannot change your own status")
+	ErrUpdateUserStatus         = NewError(ENUM_ERROR_KIND_INTERNAL, "update_user_status", "failed to update user status")
+	ErrAccountSuspended         = NewError(ENUM_ERROR_KIND_FORBIDDEN, "account_suspended", "account suspended")
+	ErrAccountBanned            = NewError(ENUM_ERROR_KIND_FORBIDDEN, "account_banned", "account banned")
+	ErrReactivateUser           = NewError(ENUM_ERROR_KIND_INTERNAL, "reactivate_user", "failed to reactivate user")
+	ErrMailNotConfigured        = NewError(ENUM_ERROR_KIND_INTERNAL, "mail_not_configured", "smtp is not configured")
+	ErrSendEmailConfirmation    = NewError(ENUM_ERROR_KIND_INTERNAL, "send_email_confirmation", "failed to send email confirmation")
+	ErrGenerateToken            = NewError(ENUM_ERROR_KIND_INTERNAL, "generate_token", "failed to generate token")
+	ErrInvalidEmailChangeToken  = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_email_change_token", "invalid email confirmation token")
+	ErrEmailChangeTokenExpired  = NewError(ENUM_ERROR_KIND_VALIDATION, "email_change_token_expired", "email confirmation token expired")
+	ErrConfirmEmailChange       = NewError(ENUM_ERROR_KIND_INTERNAL, "confirm_email_change", "failed to confirm email change")
+	ErrInvalidAddressType       = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_address_type", "invalid address type, use home, billing or shipping")
+	ErrInvalidCountryCode       = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_country_code", "invalid country code, use ISO 3166-1 alpha-2")
+	ErrAddressRequired          = NewError(ENUM_ERROR_KIND_VALIDATION, "address_required", "street and city are required")
+	ErrAddressNotFound          = NewError(ENUM_ERROR_KIND_NOT_FOUND, "address_not_found", "address not found")
+	ErrGetAllAddress            = NewError(ENUM_ERROR_KIND_INTERNAL, "get_all_address", "failed get list address")
+	ErrCreateAddress            = NewError(ENUM_ERROR_KIND_INTERNAL, "create_address", "failed to create address")
+	ErrUpdateAddress            = NewError(ENUM_ERROR_KIND_INTERNAL, "update_address", "failed to update address")
+	ErrDeleteAddress            = NewError(ENUM_ERROR_KIND_INTERNAL, "delete_address", "failed to delete address")
+	ErrInvalidPreferences       = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_preferences", "invalid preferences")
+	ErrInvalidLocale            = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_locale", "invalid locale, use a BCP 47 tag such as en or id-ID")
+	ErrInvalidTimezone          = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_timezone", "invalid timezone, use an IANA name such as Asia/Jakarta")
+	ErrInvalidTheme             = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_theme", "invalid theme, use light, dark or system")
+	ErrGetPreferences           = NewError(ENUM_ERROR_KIND_INTERNAL, "get_preferences", "failed get preferences")
+	ErrUpdatePreferences        = NewError(ENUM_ERROR_KIND_INTERNAL, "update_preferences", "failed to update preferences")
+	ErrInvitationAlreadyPending = NewError(ENUM_ERROR_KIND_CONFLICT, "invitation_already_pending", "a pending invitation already exists for this email")
+	ErrInvitationNotFound       = NewError(ENUM_ERROR_KIND_NOT_FOUND, "invitation_not_found", "invitation not found")
+	ErrInvalidInvitationToken   = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_invitation_token", "invalid invitation token")
+	ErrInvitationExpired        = NewError(ENUM_ERROR_KIND_GONE, "invitation_expired", "invitation expired")
+	ErrInvitationNotPending     = NewError(ENUM_ERROR_KIND_CONFLICT, "invitation_not_pending", "invitation was already accepted or revoked")
+	ErrInvalidInvitationStatus  = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_invitation_status", "invalid invitation status, use pending, accepted, revoked or expired")
+	ErrCreateInvitation         = NewError(ENUM_ERROR_KIND_INTERNAL, "create_invitation", "failed to create invitation")
+	ErrGetAllInvitation         = NewError(ENUM_ERROR_KIND_INTERNAL, "get_all_invitation", "failed get list invitation")
+	ErrResendInvitation         = NewError(ENUM_ERROR_KIND_INTERNAL, "resend_invitation", "failed to resend invitation")
+	ErrRevokeInvitation         = NewError(ENUM_ERROR_KIND_INTERNAL, "revoke_invitation", "failed to revoke invitation")
+	ErrAcceptInvitation         = NewError(ENUM_ERROR_KIND_INTERNAL, "accept_invitation", "failed to accept invitation")
+	ErrSendInvitation           = NewError(ENUM_ERROR_KIND_INTERNAL, "send_invitation", "failed to send invitation email")
+
+	ErrCustomFieldNotFound       = NewError(ENUM_ERROR_KIND_NOT_FOUND, "custom_field_not_found", "custom field not found")
+	ErrCustomFieldKeyExists      = NewError(ENUM_ERROR_KIND_CONFLICT, "custom_field_key_exists", "custom field key already exists")
+	ErrInvalidCustomFieldKey     = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_custom_field_key", "invalid custom field key, use lowercase letters, digits and underscores starting with a letter")
+	ErrCustomFieldLabelRequired  = NewError(ENUM_ERROR_KIND_VALIDATION, "custom_field_label_required", "custom field label is required")
+	ErrInvalidCustomFieldType    = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_custom_field_type", "invalid custom field type, use string, number, boolean, date or enum")
+	ErrInvalidCustomFieldPattern = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_custom_field_pattern", "invalid custom field validation regex")
+	ErrInvalidCustomFieldOptions = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_custom_field_options", "enum custom fields need distinct options, other types take none")
+	ErrUnknownCustomField        = NewError(ENUM_ERROR_KIND_VALIDATION, "unknown_custom_field", "unknown custom field")
+	ErrCustomFieldRequired       = NewError(ENUM_ERROR_KIND_VALIDATION, "custom_field_required", "custom field is required")
+	ErrInvalidCustomFieldValue   = NewError(ENUM_ERROR_KIND_VALIDATION, "invalid_custom_field_value", "invalid custom field value")
+	ErrCreateCustomField         = NewError(ENUM_ERROR_KIND_INTERNAL, "create_custom_field", "failed to create custom field")
+	ErrGetAllCustomField         = NewError(ENUM_ERROR_KIND_INTERNAL, "get_all_custom_field", "failed get list custom field")
+	ErrUpdateCustomField         = NewError(ENUM_ERROR_KIND_INTERNAL, "update_custom_field", "failed to update custom field")
+	ErrDeleteCustomField         = NewError(ENUM_ERROR_KIND_INTERNAL, "delete_custom_field", "failed to delete custom field")
+	ErrGetCustomFieldValues      = NewError(ENUM_ERROR_KIND_INTERNAL, "get_custom_field_values", "failed get custom field values")
+	ErrSaveCustomFieldValues     = NewError(ENUM_ERROR_KIND_INTERNAL, "save_custom_field_values", "failed to save custom field values")
 )
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+type (
+	IAddressController interface {
+		GetAddresses(ctx *gin.Context)
+		CreateAddress(ctx *gin.Context)
+		UpdateAddress(ctx *gin.Context)
+		DeleteAddress(ctx *gin.Context)
+	}
+
+	AddressController struct {
+		addressService service.IAddressService
+	}
+)
+
+func NewAddressController(addressService service.IAddressService) *AddressController {
+	return &AddressController{
+		addressService: addressService,
+	}
+}
+
+// authorizeAddressOwner checks the :id param and lets users manage only
+// their own addresses. It writes the error response and returns false when
+// the request must stop.
+func authorizeAddressOwner(ctx *gin.Context) (string, bool) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return "", false
+	}
+
+	userID := ctx.GetString("id")
+	role := ctx.GetString("role")
+
+	if role == constants.ENUM_ROLE_USER && userID != idParam {
+		logging.Log.Warn("unauthorized address access attempt by user")
+		respondError(ctx, constants.MESSAGE_FAILED_PROSES_REQUEST, fmt.Errorf("%w: you can only manage your own addresses", constants.ErrDeniedAccess))
+		return "", false
+	}
+
+	return idParam, true
+}
+
+func addressIDParam(ctx *gin.Context) (string, bool) {
+	addressIDParam := ctx.Param("addressId")
+	if _, err := uuid.Parse(addressIDParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return "", false
+	}
+
+	return addressIDParam, true
+}
+
+func (ac *AddressController) GetAddresses(ctx *gin.Context) {
+	userID, ok := authorizeAddressOwner(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ac.addressService.GetAddresses(ctx.Request.Context(), dto.GetAddressesRequest{UserID: userID})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_ADDRESS, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_ADDRESS+": %d addresses", len(result))
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_LIST_ADDRESS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (ac *AddressController) CreateAddress(ctx *gin.Context) {
+	userID, ok := authorizeAddressOwner(ctx)
+	if !ok {
+		return
+	}
+
+	var payload dto.CreateAddressRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+	payload.UserID = userID
+
+	result, err := ac.addressService.CreateAddress(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_CREATE_ADDRESS, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_ADDRESS+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_CREATE_ADDRESS, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (ac *AddressController) UpdateAddress(ctx *gin.Context) {
+	userID, ok := authorizeAddressOwner(ctx)
+	if !ok {
+		return
+	}
+
+	addressID, ok := addressIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var payload dto.UpdateAddressRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+	payload.UserID = userID
+	payload.AddressID = addressID
+
+	result, err := ac.addressService.UpdateAddress(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_ADDRESS, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_ADDRESS+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_UPDATE_ADDRESS, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (ac *AddressController) DeleteAddress(ctx *gin.Context) {
+	userID, ok := authorizeAddressOwner(ctx)
+	if !ok {
+		return
+	}
+
+	addressID, ok := addressIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ac.addressService.DeleteAddress(ctx.Request.Context(), dto.DeleteAddressRequest{
+		UserID:    userID,
+		AddressID: addressID,
+	})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_DELETE_ADDRESS, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_DELETE_ADDRESS+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_DELETE_ADDRESS, result)
+	ctx.JSON(http.StatusOK, res)
+}
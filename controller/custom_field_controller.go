@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+type (
+	ICustomFieldController interface {
+		GetAllCustomField(ctx *gin.Context)
+		CreateCustomField(ctx *gin.Context)
+		UpdateCustomField(ctx *gin.Context)
+		DeleteCustomField(ctx *gin.Context)
+	}
+
+	CustomFieldController struct {
+		customFieldService service.ICustomFieldService
+	}
+)
+
+func NewCustomFieldController(customFieldService service.ICustomFieldService) *CustomFieldController {
+	return &CustomFieldController{
+		customFieldService: customFieldService,
+	}
+}
+
+func customFieldIDParam(ctx *gin.Context) (string, bool) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return "", false
+	}
+
+	return idParam, true
+}
+
+func (cc *CustomFieldController) GetAllCustomField(ctx *gin.Context) {
+	result, err := cc.customFieldService.GetAllCustomField(ctx.Request.Context())
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_CUSTOM_FIELD+": %d fields", len(result))
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_LIST_CUSTOM_FIELD, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (cc *CustomFieldController) CreateCustomField(ctx *gin.Context) {
+	var payload dto.CreateCustomFieldRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	result, err := cc.customFieldService.CreateCustomField(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_CREATE_CUSTOM_FIELD, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_CUSTOM_FIELD+": %s", result.Key)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_CREATE_CUSTOM_FIELD, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (cc *CustomFieldController) UpdateCustomField(ctx *gin.Context) {
+	fieldID, ok := customFieldIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var payload dto.UpdateCustomFieldRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+	payload.FieldID = fieldID
+
+	result, err := cc.customFieldService.UpdateCustomField(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_CUSTOM_FIELD, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_CUSTOM_FIELD+": %s", result.Key)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_UPDATE_CUSTOM_FIELD, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (cc *CustomFieldController) DeleteCustomField(ctx *gin.Context) {
+	fieldID, ok := customFieldIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := cc.customFieldService.DeleteCustomField(ctx.Request.Context(), dto.DeleteCustomFieldRequest{FieldID: fieldID})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_DELETE_CUSTOM_FIELD, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_DELETE_CUSTOM_FIELD+": %s", result.Key)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_DELETE_CUSTOM_FIELD, result)
+	ctx.JSON(http.StatusOK, res)
+}
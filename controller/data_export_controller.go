@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+type (
+	IDataExportController interface {
+		RequestDataExport(ctx *gin.Context)
+		DownloadDataExport(ctx *gin.Context)
+	}
+
+	DataExportController struct {
+		dataExportService service.IDataExportService
+	}
+)
+
+func NewDataExportController(dataExportService service.IDataExportService) *DataExportController {
+	return &DataExportController{
+		dataExportService: dataExportService,
+	}
+}
+
+func (dc *DataExportController) RequestDataExport(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	userID := ctx.GetString("id")
+	role := ctx.GetString("role")
+
+	if role == constants.ENUM_ROLE_USER && userID != idParam {
+		logging.Log.Warn("unauthorized data export attempt by user")
+		respondError(ctx, constants.MESSAGE_FAILED_REQUEST_DATA_EXPORT, fmt.Errorf("%w: you can only export your own data", constants.ErrDeniedAccess))
+		return
+	}
+
+	payload := dto.DataExportRequest{
+		UserID:      idParam,
+		RequestedBy: userID,
+	}
+
+	result, err := dc.dataExportService.RequestDataExport(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_REQUEST_DATA_EXPORT, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_REQUEST_DATA_EXPORT+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_REQUEST_DATA_EXPORT, result)
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+func (dc *DataExportController) DownloadDataExport(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	exportIDParam := ctx.Param("exportId")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	if _, err := uuid.Parse(exportIDParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	userID := ctx.GetString("id")
+	role := ctx.GetString("role")
+
+	if role == constants.ENUM_ROLE_USER && userID != idParam {
+		logging.Log.Warn("unauthorized data export download attempt by user")
+		respondError(ctx, constants.MESSAGE_FAILED_GET_DATA_EXPORT, fmt.Errorf("%w: you can only download your own data", constants.ErrDeniedAccess))
+		return
+	}
+
+	payload := dto.GetDataExportRequest{
+		UserID:   idParam,
+		ExportID: exportIDParam,
+	}
+
+	result, err := dc.dataExportService.GetDataExport(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_DATA_EXPORT, err)
+		return
+	}
+
+	if result.Status != constants.ENUM_DATA_EXPORT_STATUS_READY {
+		res := utils.BuildResponseSuccess(constants.MESSAGE_DATA_EXPORT_NOT_READY, result)
+		ctx.JSON(http.StatusAccepted, res)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_DATA_EXPORT+": %s", result.ID)
+	ctx.FileAttachment(result.FilePath, fmt.Sprintf("data-export-%s.zip", result.ID))
+}
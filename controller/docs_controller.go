@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/docs"
+)
+
+type (
+	IDocsController interface {
+		GetOpenAPI(ctx *gin.Context)
+		GetDocsUI(ctx *gin.Context)
+	}
+
+	DocsController struct{}
+)
+
+func NewDocsController() *DocsController {
+	return &DocsController{}
+}
+
+// GetOpenAPI serves the OpenAPI document of the version named by ?version=,
+// such as 2 or v2, or of the default version.
+func (dc *DocsController) GetOpenAPI(ctx *gin.Context) {
+	version := constants.ENUM_API_VERSION_DEFAULT
+	if requested := ctx.Query("version"); requested != "" {
+		version = "v" + strings.TrimPrefix(strings.ToLower(requested), "v")
+	}
+
+	switch version {
+	case constants.ENUM_API_VERSION_1, constants.ENUM_API_VERSION_2:
+	default:
+		err := fmt.Errorf("%w: %q", constants.ErrUnsupportedAPIVersion, ctx.Query("version"))
+		respondError(ctx, constants.MESSAGE_FAILED_PROSES_REQUEST, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, docs.OpenAPI(version))
+}
+
+func (dc *DocsController) GetDocsUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", docs.UI)
+}
@@ -0,0 +1,11 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+// respondError hands a failed call over to middleware.ErrorHandler, which
+// answers with the status and code of err's kind. message says what the
+// request was trying to do.
+func respondError(ctx *gin.Context, message string, err error) {
+	ctx.Error(err).SetMeta(message)
+	ctx.Abort()
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/middleware"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+// serveAs answers a request from a signed-in user with role, the way the
+// router would, without reaching the services.
+func serveAs(role, method, path string) (int, utils.Response) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(), func(ctx *gin.Context) {
+		ctx.Set("id", "8d9c2c6e-3f4b-4c1a-9a55-0f4f3c1f1b2a")
+		ctx.Set("role", role)
+	})
+
+	preferences := NewUserPreferenceController(nil)
+	exports := NewDataExportController(nil)
+	r.GET("/users/:id/preferences", preferences.GetUserPreferences)
+	r.POST("/users/:id/exports", exports.RequestDataExport)
+	r.GET("/users", func(ctx *gin.Context) {
+		var filters []dto.FilterCondition
+		if bindFilterQuery(ctx, &filters) {
+			ctx.Status(http.StatusNoContent)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+	var res utils.Response
+	json.Unmarshal(w.Body.Bytes(), &res)
+	return w.Code, res
+}
+
+func TestControllers_ReportTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{"bad uuid", constants.ENUM_ROLE_ADMIN, http.MethodGet, "/users/42/preferences", http.StatusUnprocessableEntity, constants.ErrInvalidUUID.Code},
+		{"another user's data", constants.ENUM_ROLE_USER, http.MethodPost, "/users/0f4f3c1f-1b2a-4c1a-9a55-8d9c2c6e3f4b/exports", http.StatusForbidden, constants.ErrDeniedAccess.Code},
+		{"malformed filter", constants.ENUM_ROLE_ADMIN, http.MethodGet, "/users?filter[role][eq][x]=admin", http.StatusUnprocessableEntity, constants.ErrInvalidFilter.Code},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, res := serveAs(tt.role, tt.method, tt.path)
+			if status != tt.status || res.Code != tt.code {
+				t.Fatalf("expected %d %s, got %d %s", tt.status, tt.code, status, res.Code)
+			}
+		})
+	}
+}
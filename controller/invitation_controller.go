@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+type (
+	IInvitationController interface {
+		CreateInvitation(ctx *gin.Context)
+		GetAllInvitation(ctx *gin.Context)
+		ResendInvitation(ctx *gin.Context)
+		RevokeInvitation(ctx *gin.Context)
+		AcceptInvitation(ctx *gin.Context)
+	}
+
+	InvitationController struct {
+		invitationService service.IInvitationService
+	}
+)
+
+func NewInvitationController(invitationService service.IInvitationService) *InvitationController {
+	return &InvitationController{
+		invitationService: invitationService,
+	}
+}
+
+func invitationIDParam(ctx *gin.Context) (string, bool) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return "", false
+	}
+
+	return idParam, true
+}
+
+func (ic *InvitationController) CreateInvitation(ctx *gin.Context) {
+	var payload dto.CreateInvitationRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+	payload.InvitedBy = ctx.GetString("id")
+
+	result, err := ic.invitationService.CreateInvitation(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_CREATE_INVITATION, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_INVITATION+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_CREATE_INVITATION, result)
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (ic *InvitationController) GetAllInvitation(ctx *gin.Context) {
+	var query dto.InvitationPaginationRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	if !bindFilterQuery(ctx, &query.Filters) {
+		return
+	}
+
+	result, err := ic.invitationService.GetAllInvitationWithPagination(ctx.Request.Context(), query)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_INVITATION, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_INVITATION+": page %d", query.Page)
+	respondPage(ctx, constants.MESSAGE_SUCCESS_GET_LIST_INVITATION, query.PaginationRequest, result.Data, result.PaginationResponse)
+}
+
+func (ic *InvitationController) ResendInvitation(ctx *gin.Context) {
+	invitationID, ok := invitationIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ic.invitationService.ResendInvitation(ctx.Request.Context(), dto.ResendInvitationRequest{InvitationID: invitationID})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_RESEND_INVITATION, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_RESEND_INVITATION+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_RESEND_INVITATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (ic *InvitationController) RevokeInvitation(ctx *gin.Context) {
+	invitationID, ok := invitationIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ic.invitationService.RevokeInvitation(ctx.Request.Context(), dto.RevokeInvitationRequest{InvitationID: invitationID})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_REVOKE_INVITATION, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_REVOKE_INVITATION+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_REVOKE_INVITATION, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (ic *InvitationController) AcceptInvitation(ctx *gin.Context) {
+	var payload dto.AcceptInvitationRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	result, err := ic.invitationService.AcceptInvitation(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_ACCEPT_INVITATION, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_ACCEPT_INVITATION+": %s", result.Email)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_ACCEPT_INVITATION, presentUser(ctx, result))
+	ctx.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+// respondPage writes one page of a list. The pagination goes out in meta, as
+// an RFC 8288 Link header to the first, previous, next and last pages, and
+// as the total row count in X-Total-Count.
+func respondPage(ctx *gin.Context, message string, req dto.PaginationRequest, data any, meta dto.PaginationResponse) {
+	meta.Links = paginationLinks(ctx.Request.URL, req, meta)
+
+	ctx.Header("X-Total-Count", strconv.FormatInt(meta.Count, 10))
+	if link := linkHeader(meta.Links); link != "" {
+		ctx.Header("Link", link)
+	}
+
+	ctx.JSON(http.StatusOK, utils.Response{
+		Status:   true,
+		Messsage: message,
+		Data:     data,
+		Meta:     meta,
+	})
+}
+
+// paginationLinks builds the page URLs from the request URL, keeping every
+// other parameter, such as search and filters, as it is. The page size is
+// the one actually served, after capping.
+func paginationLinks(requestURL *url.URL, req dto.PaginationRequest, meta dto.PaginationResponse) *dto.PaginationLinks {
+	pageURL := func(set func(query url.Values)) string {
+		query := requestURL.Query()
+		set(query)
+		return (&url.URL{Path: requestURL.Path, RawQuery: query.Encode()}).String()
+	}
+
+	links := &dto.PaginationLinks{}
+
+	if req.IsCursor() {
+		limit := strconv.Itoa(meta.PerPage)
+		cursorURL := func(cursor string) string {
+			return pageURL(func(query url.Values) {
+				query.Set("limit", limit)
+				query.Del("cursor")
+				if cursor != "" {
+					query.Set("cursor", cursor)
+				}
+			})
+		}
+
+		// A cursor brings its own sort, which the first page has to name
+		// when the request did not.
+		links.First = pageURL(func(query url.Values) {
+			query.Set("limit", limit)
+			query.Del("cursor")
+			if cursor, err := helpers.DecodeCursor(req.Cursor); err == nil && req.Sort == "" {
+				query.Set("sort", cursor.Sort)
+			}
+		})
+		if meta.PrevCursor != "" {
+			links.Prev = cursorURL(meta.PrevCursor)
+		}
+		if meta.NextCursor != "" {
+			links.Next = cursorURL(meta.NextCursor)
+		}
+		return links
+	}
+
+	perPage := strconv.Itoa(meta.PerPage)
+	pageNumberURL := func(page int64) string {
+		return pageURL(func(query url.Values) {
+			query.Set("page", strconv.FormatInt(page, 10))
+			query.Set("per_page", perPage)
+		})
+	}
+
+	page := int64(meta.Page)
+	lastPage := max(meta.MaxPage, 1)
+
+	links.First = pageNumberURL(1)
+	if page > 1 {
+		links.Prev = pageNumberURL(min(page-1, lastPage))
+	}
+	if page < lastPage {
+		links.Next = pageNumberURL(page + 1)
+	}
+	links.Last = pageNumberURL(lastPage)
+	return links
+}
+
+func linkHeader(links *dto.PaginationLinks) string {
+	var parts []string
+	for _, link := range []struct{ rel, url string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.url != "" {
+			parts = append(parts, "<"+link.url+`>; rel="`+link.rel+`"`)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
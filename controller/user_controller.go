@@ -1,12 +1,17 @@
 package controller
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/mferdian/golang_boiller_plate/constants"
 	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
 	"github.com/mferdian/golang_boiller_plate/logging"
 	"github.com/mferdian/golang_boiller_plate/service"
 	"github.com/mferdian/golang_boiller_plate/utils"
@@ -21,7 +26,24 @@ type (
 		GetAllUser(ctx *gin.Context)
 		GetUserByID(ctx *gin.Context)
 		UpdateUser(ctx *gin.Context)
+		ConfirmEmailChange(ctx *gin.Context)
 		DeleteUser(ctx *gin.Context)
+
+		GetMe(ctx *gin.Context)
+		UpdateMe(ctx *gin.Context)
+		DeleteMe(ctx *gin.Context)
+
+		GetAllTrashedUser(ctx *gin.Context)
+		RestoreUser(ctx *gin.Context)
+		PurgeUser(ctx *gin.Context)
+
+		ImportUsers(ctx *gin.Context)
+		ExportUsers(ctx *gin.Context)
+
+		BulkUpdateUsers(ctx *gin.Context)
+		BulkDeleteUsers(ctx *gin.Context)
+
+		UpdateUserStatus(ctx *gin.Context)
 	}
 
 	UserController struct {
@@ -35,20 +57,30 @@ func NewUserController(userService service.IUserService) *UserController {
 	}
 }
 
+// bindFilterQuery reads the filter[field][operator] parameters of a list
+// request, which ShouldBindQuery cannot bind.
+func bindFilterQuery(ctx *gin.Context, dst *[]dto.FilterCondition) bool {
+	filters, err := dto.ParseFilterQuery(ctx.Request.URL.Query())
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
+		respondError(ctx, constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err)
+		return false
+	}
+
+	*dst = filters
+	return true
+}
+
 func (uc *UserController) Register(ctx *gin.Context) {
 	var payload dto.RegisterUserRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondBindError(ctx, err)
 		return
 	}
 
 	result, err := uc.userService.Register(ctx.Request.Context(), payload)
 	if err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_REGISTER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_REGISTER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_REGISTER, err)
 		return
 	}
 
@@ -60,17 +92,15 @@ func (uc *UserController) Register(ctx *gin.Context) {
 func (uc *UserController) Login(ctx *gin.Context) {
 	var payload dto.LoginUserRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondBindError(ctx, err)
 		return
 	}
 
+	payload.IP = ctx.ClientIP()
+
 	result, err := uc.userService.Login(ctx.Request.Context(), payload)
 	if err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_LOGIN_USER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_LOGIN_USER, err.Error(), nil)
-		ctx.JSON(http.StatusUnauthorized, res)
+		respondError(ctx, constants.MESSAGE_FAILED_LOGIN_USER, err)
 		return
 	}
 
@@ -82,22 +112,18 @@ func (uc *UserController) Login(ctx *gin.Context) {
 func (uc *UserController) CreateUser(ctx *gin.Context) {
 	var payload dto.CreateUserRequest
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondBindError(ctx, err)
 		return
 	}
 
 	result, err := uc.userService.CreateUser(ctx.Request.Context(), payload)
 	if err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CREATE_USER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_CREATE_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_CREATE_USER, err)
 		return
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_USER+": %s", result.Email)
-	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_CREATE_USER, result)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_CREATE_USER, presentUser(ctx, result))
 	ctx.JSON(http.StatusCreated, res)
 }
 
@@ -111,39 +137,40 @@ func (uc *UserController) GetAllUser(ctx *gin.Context) {
 		// Tanpa pagination
 		result, err := uc.userService.GetAllUser(ctx, search)
 		if err != nil {
-			res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_LIST_USER, err.Error(), nil)
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_USER, err)
 			return
 		}
-		res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_LIST_USER, result)
+		res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_LIST_USER, presentUsers(ctx, result))
 		ctx.AbortWithStatusJSON(http.StatusOK, res)
 		return
 	}
 
 	var query dto.UserPaginationRequest
 	if err := ctx.ShouldBindQuery(&query); err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondBindError(ctx, err)
+		return
+	}
+
+	if !bindFilterQuery(ctx, &query.Filters) {
 		return
 	}
 
+	query.CustomFields = ctx.QueryMap("custom_fields")
+
 	result, err := uc.userService.GetAllUserWithPagination(ctx.Request.Context(), query)
 	if err != nil {
-		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_USER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_LIST_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_USER, err)
 		return
 	}
 
-	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_USER+": page %d", query.Page)
-	res := utils.Response{
-		Status:   true,
-		Messsage: constants.MESSAGE_SUCCESS_GET_LIST_USER,
-		Data:     result.Data,
-		Meta:     result.PaginationResponse,
+	data, err := helpers.PickFields(presentUsers(ctx, result.Data), query.ResponseFields())
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_USER, err)
+		return
 	}
-	ctx.JSON(http.StatusOK, res)
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_USER+": page %d", query.Page)
+	respondPage(ctx, constants.MESSAGE_SUCCESS_GET_LIST_USER, query.PaginationRequest, data, result.PaginationResponse)
 }
 
 func (uc *UserController) GetUserByID(ctx *gin.Context) {
@@ -154,28 +181,24 @@ func (uc *UserController) GetUserByID(ctx *gin.Context) {
 
 	if role == constants.ENUM_ROLE_USER && userID != idStr {
 		logging.Log.Warn("unauthorized access: user trying to access another user's data")
-		res := utils.BuildResponseFailed("unauthorized", "you can only get your own account", nil)
-		ctx.AbortWithStatusJSON(http.StatusForbidden, res)
+		respondError(ctx, constants.MESSAGE_FAILED_GET_DETAIL_USER, fmt.Errorf("%w: you can only get your own account", constants.ErrDeniedAccess))
 		return
 	}
 
 	if _, err := uuid.Parse(idStr); err != nil {
 		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_UUID_FORMAT, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
 		return
 	}
 
 	result, err := uc.userService.GetuserByID(ctx.Request.Context(), idStr)
 	if err != nil {
-		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_DETAIL_USER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DETAIL_USER, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+		respondError(ctx, constants.MESSAGE_FAILED_GET_DETAIL_USER, err)
 		return
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_DETAIL_USER+": %s", idStr)
-	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_DETAIL_USER, result)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_DETAIL_USER, presentUser(ctx, result))
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -183,8 +206,7 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	if _, err := uuid.Parse(idParam); err != nil {
 		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_UUID_FORMAT, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
 		return
 	}
 
@@ -193,8 +215,7 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 
 	if role == constants.ENUM_ROLE_USER && userID != idParam {
 		logging.Log.Warn("unauthorized update attempt by user")
-		res := utils.BuildResponseFailed("unauthorized", "you can only update your own account", nil)
-		ctx.JSON(http.StatusForbidden, res)
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_USER, fmt.Errorf("%w: you can only update your own account", constants.ErrDeniedAccess))
 		return
 	}
 
@@ -202,22 +223,41 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 	payload.ID = idParam
 
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
-		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondBindError(ctx, err)
 		return
 	}
 
 	result, err := uc.userService.UpdateUser(ctx.Request.Context(), payload)
 	if err != nil {
-		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_USER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_UPDATE_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_USER, err)
 		return
 	}
 
+	message := constants.MESSAGE_SUCCESS_UPDATE_USER
+	if payload.Email != nil && *payload.Email == result.PendingEmail {
+		message = constants.MESSAGE_SUCCESS_REQUEST_EMAIL
+	}
+
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_USER+": %s", result.ID)
-	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_UPDATE_USER, result)
+	res := utils.BuildResponseSuccess(message, presentUser(ctx, result))
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (uc *UserController) ConfirmEmailChange(ctx *gin.Context) {
+	var payload dto.ConfirmEmailChangeRequest
+	if err := ctx.ShouldBindQuery(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	result, err := uc.userService.ConfirmEmailChange(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_CONFIRM_EMAIL, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CONFIRM_EMAIL+": %s", result.ID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_CONFIRM_EMAIL, presentUser(ctx, result))
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -225,8 +265,7 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 	idParam := ctx.Param("id")
 	if _, err := uuid.Parse(idParam); err != nil {
 		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_UUID_FORMAT, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
 		return
 	}
 
@@ -235,8 +274,22 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 
 	if role == constants.ENUM_ROLE_USER && userID != idParam {
 		logging.Log.Warn("unauthorized delete attempt by user")
-		res := utils.BuildResponseFailed("unauthorized", "you can only delete your own account", nil)
-		ctx.JSON(http.StatusForbidden, res)
+		respondError(ctx, constants.MESSAGE_FAILED_DELETE_USER, fmt.Errorf("%w: you can only delete your own account", constants.ErrDeniedAccess))
+		return
+	}
+
+	// Deleting your own account only schedules the erasure, logging in
+	// again before the grace period ends cancels it.
+	if userID == idParam {
+		result, err := uc.userService.RequestAccountDeletion(ctx.Request.Context(), dto.AccountDeletionRequest{UserID: idParam})
+		if err != nil {
+			respondError(ctx, constants.MESSAGE_FAILED_REQUEST_DELETION, err)
+			return
+		}
+
+		logging.Log.Infof(constants.MESSAGE_SUCCESS_REQUEST_DELETION+": %s", idParam)
+		res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_REQUEST_DELETION, result)
+		ctx.JSON(http.StatusAccepted, res)
 		return
 	}
 
@@ -244,13 +297,344 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 
 	result, err := uc.userService.DeleteUser(ctx.Request.Context(), payload)
 	if err != nil {
-		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_DELETE_USER)
-		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_DELETE_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		respondError(ctx, constants.MESSAGE_FAILED_DELETE_USER, err)
 		return
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_DELETE_USER+": %s", idParam)
-	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_DELETE_USER, result)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_DELETE_USER, presentUser(ctx, result))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetMe, UpdateMe and DeleteMe act on the authenticated user, so clients
+// do not need to read their own ID out of the token.
+func (uc *UserController) GetMe(ctx *gin.Context) {
+	userID := ctx.GetString("id")
+
+	result, err := uc.userService.GetProfile(ctx.Request.Context(), userID)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_PROFILE, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_PROFILE+": %s", userID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_PROFILE, presentProfile(ctx, result))
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (uc *UserController) UpdateMe(ctx *gin.Context) {
+	var payload dto.UpdateUserRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+	payload.ID = ctx.GetString("id")
+
+	updated, err := uc.userService.UpdateUser(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_USER, err)
+		return
+	}
+
+	result, err := uc.userService.GetProfile(ctx.Request.Context(), payload.ID)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_PROFILE, err)
+		return
+	}
+
+	message := constants.MESSAGE_SUCCESS_UPDATE_USER
+	if payload.Email != nil && *payload.Email == updated.PendingEmail {
+		message = constants.MESSAGE_SUCCESS_REQUEST_EMAIL
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_USER+": %s", result.ID)
+	res := utils.BuildResponseSuccess(message, presentProfile(ctx, result))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// DeleteMe schedules the erasure of the account, like deleting your own
+// account through /api/users/:id does.
+func (uc *UserController) DeleteMe(ctx *gin.Context) {
+	userID := ctx.GetString("id")
+
+	result, err := uc.userService.RequestAccountDeletion(ctx.Request.Context(), dto.AccountDeletionRequest{UserID: userID})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_REQUEST_DELETION, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_REQUEST_DELETION+": %s", userID)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_REQUEST_DELETION, result)
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+func (uc *UserController) GetAllTrashedUser(ctx *gin.Context) {
+	var query dto.UserPaginationRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	if !bindFilterQuery(ctx, &query.Filters) {
+		return
+	}
+
+	result, err := uc.userService.GetAllTrashedUserWithPagination(ctx.Request.Context(), query)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_TRASH_USER, err)
+		return
+	}
+
+	data, err := helpers.PickFields(presentTrashedUsers(ctx, result.Data), query.ResponseFields())
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_LIST_TRASH_USER, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_TRASH_USER+": page %d", query.Page)
+	respondPage(ctx, constants.MESSAGE_SUCCESS_GET_LIST_TRASH_USER, query.PaginationRequest, data, result.PaginationResponse)
+}
+
+func (uc *UserController) RestoreUser(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	payload := dto.RestoreUserRequest{UserID: idParam}
+
+	result, err := uc.userService.RestoreUser(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_RESTORE_USER, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_RESTORE_USER+": %s", idParam)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_RESTORE_USER, presentUser(ctx, result))
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (uc *UserController) PurgeUser(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	payload := dto.PurgeUserRequest{UserID: idParam}
+
+	result, err := uc.userService.PurgeUser(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_PURGE_USER, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_PURGE_USER+": %s", idParam)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_PURGE_USER, presentUser(ctx, result))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ImportUsers accepts either a multipart upload in the "file" field or the
+// raw file as the request body. The format comes from the "format" query
+// parameter, falling back to the file extension and then the Content-Type.
+func (uc *UserController) ImportUsers(ctx *gin.Context) {
+	var payload dto.ImportUserRequest
+	if err := ctx.ShouldBindQuery(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, constants.ENUM_IMPORT_MAX_FILE_SIZE_MiB<<20)
+
+	var (
+		content []byte
+		err     error
+	)
+
+	contentType := ctx.ContentType()
+	if contentType == "multipart/form-data" {
+		fileHeader, ferr := ctx.FormFile("file")
+		if ferr != nil {
+			logging.Log.WithError(ferr).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
+			respondBindError(ctx, ferr)
+			return
+		}
+
+		if payload.Format == "" {
+			payload.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+		}
+
+		file, ferr := fileHeader.Open()
+		if ferr != nil {
+			logging.Log.WithError(ferr).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
+			respondBindError(ctx, ferr)
+			return
+		}
+		defer file.Close()
+
+		content, err = io.ReadAll(file)
+	} else {
+		if payload.Format == "" {
+			switch contentType {
+			case "text/csv":
+				payload.Format = constants.ENUM_IMPORT_FORMAT_CSV
+			case "application/json":
+				payload.Format = constants.ENUM_IMPORT_FORMAT_JSON
+			}
+		}
+
+		content, err = io.ReadAll(ctx.Request.Body)
+	}
+
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
+		respondBindError(ctx, err)
+		return
+	}
+
+	payload.Content = content
+
+	result, err := uc.userService.ImportUsers(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_IMPORT_USER, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Committed && result.Created > 0 {
+		status = http.StatusCreated
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_IMPORT_USER+": %d row(s)", result.Total)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_IMPORT_USER, result)
+	ctx.JSON(status, res)
+}
+
+func (uc *UserController) ExportUsers(ctx *gin.Context) {
+	var query dto.ExportUserRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	if !bindFilterQuery(ctx, &query.Filters) {
+		return
+	}
+
+	query.CustomFields = ctx.QueryMap("custom_fields")
+
+	if query.Format == "" {
+		query.Format = constants.ENUM_EXPORT_FORMAT_CSV
+	}
+
+	contentType, ext, err := helpers.TabularContentType(query.Format)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_EXPORT_USER, err)
+		return
+	}
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="users.%s"`, ext))
+	ctx.Status(http.StatusOK)
+
+	// Rows are already on the wire once streaming starts, so a failure
+	// halfway can only be logged and the response cut short.
+	total, err := uc.userService.ExportUsers(ctx.Request.Context(), query, ctx.Writer)
+	if err != nil && !ctx.Writer.Written() {
+		// Filters are checked before anything is written, so the request
+		// can still fail as a whole.
+		ctx.Header("Content-Type", "")
+		ctx.Header("Content-Disposition", "")
+		respondError(ctx, constants.MESSAGE_FAILED_EXPORT_USER, err)
+		return
+	}
+	if err != nil {
+		logging.Log.WithError(err).Errorf(constants.MESSAGE_FAILED_EXPORT_USER+": after %d row(s)", total)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_EXPORT_USER+": %d row(s)", total)
+}
+
+func (uc *UserController) BulkUpdateUsers(ctx *gin.Context) {
+	var payload dto.BulkUpdateUserRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	payload.RequestedBy = ctx.GetString("id")
+
+	result, err := uc.userService.BulkUpdateUsers(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_BULK_UPDATE_USER, err)
+		return
+	}
+
+	if !result.Committed {
+		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_BULK_UPDATE_USER, constants.ErrBulkUpdateUser.Error(), result)
+		ctx.JSON(http.StatusUnprocessableEntity, res)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_BULK_UPDATE_USER+": %d item(s)", result.Total)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_BULK_UPDATE_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (uc *UserController) BulkDeleteUsers(ctx *gin.Context) {
+	var payload dto.BulkDeleteUserRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	payload.RequestedBy = ctx.GetString("id")
+
+	result, err := uc.userService.BulkDeleteUsers(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_BULK_DELETE_USER, err)
+		return
+	}
+
+	if !result.Committed {
+		res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_BULK_DELETE_USER, constants.ErrBulkDeleteUser.Error(), result)
+		ctx.JSON(http.StatusUnprocessableEntity, res)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_BULK_DELETE_USER+": %d item(s)", result.Total)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_BULK_DELETE_USER, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (uc *UserController) UpdateUserStatus(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	var payload dto.UpdateUserStatusRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	payload.UserID = idParam
+	payload.ChangedBy = ctx.GetString("id")
+
+	result, err := uc.userService.UpdateUserStatus(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_USER_STATUS, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_USER_STATUS+": %s", idParam)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_UPDATE_USER_STATUS, result)
 	ctx.JSON(http.StatusOK, res)
 }
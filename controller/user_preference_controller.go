@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+type (
+	IUserPreferenceController interface {
+		GetUserPreferences(ctx *gin.Context)
+		UpdateUserPreferences(ctx *gin.Context)
+	}
+
+	UserPreferenceController struct {
+		preferenceService service.IUserPreferenceService
+	}
+)
+
+func NewUserPreferenceController(preferenceService service.IUserPreferenceService) *UserPreferenceController {
+	return &UserPreferenceController{
+		preferenceService: preferenceService,
+	}
+}
+
+func (pc *UserPreferenceController) GetUserPreferences(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	userID := ctx.GetString("id")
+	role := ctx.GetString("role")
+
+	if role == constants.ENUM_ROLE_USER && userID != idParam {
+		logging.Log.Warn("unauthorized preferences access attempt by user")
+		respondError(ctx, constants.MESSAGE_FAILED_GET_PREFERENCES, fmt.Errorf("%w: you can only view your own preferences", constants.ErrDeniedAccess))
+		return
+	}
+
+	result, err := pc.preferenceService.GetUserPreferences(ctx.Request.Context(), dto.GetUserPreferencesRequest{UserID: idParam})
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_GET_PREFERENCES, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_PREFERENCES+": %s", idParam)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_PREFERENCES, result)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (pc *UserPreferenceController) UpdateUserPreferences(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+	if _, err := uuid.Parse(idParam); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UUID_FORMAT)
+		respondError(ctx, constants.MESSAGE_FAILED_UUID_FORMAT, constants.ErrInvalidUUID)
+		return
+	}
+
+	userID := ctx.GetString("id")
+	role := ctx.GetString("role")
+
+	if role == constants.ENUM_ROLE_USER && userID != idParam {
+		logging.Log.Warn("unauthorized preferences update attempt by user")
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_PREFERENCES, fmt.Errorf("%w: you can only update your own preferences", constants.ErrDeniedAccess))
+		return
+	}
+
+	payload := dto.UpdateUserPreferencesRequest{UserID: idParam}
+	if err := ctx.ShouldBindJSON(&payload.Patch); err != nil {
+		respondBindError(ctx, err)
+		return
+	}
+
+	result, err := pc.preferenceService.UpdateUserPreferences(ctx.Request.Context(), payload)
+	if err != nil {
+		respondError(ctx, constants.MESSAGE_FAILED_UPDATE_PREFERENCES, err)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_PREFERENCES+": %s", idParam)
+	res := utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_UPDATE_PREFERENCES, result)
+	ctx.JSON(http.StatusOK, res)
+}
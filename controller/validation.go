@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+// respondBindError answers a request that could not be bound. Fields that
+// failed validation are listed one by one in errors, with 422; anything
+// else, such as malformed JSON, is a plain 400.
+func respondBindError(ctx *gin.Context, err error) {
+	if fields := validationErrors(err); len(fields) > 0 {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_VALIDATE_REQUEST)
+		res := utils.BuildResponseValidationFailed(constants.MESSAGE_FAILED_VALIDATE_REQUEST, fields)
+		res.Code = constants.ErrValidationFailed.Code
+		ctx.JSON(http.StatusUnprocessableEntity, res)
+		return
+	}
+
+	logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY)
+	res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
+	res.Code = constants.ErrMalformedRequest.Code
+	ctx.JSON(http.StatusBadRequest, res)
+}
+
+// validationErrors lists the failing fields of a bind error, or nil when the
+// error is not about particular fields, such as malformed JSON.
+func validationErrors(err error) []dto.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]dto.FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			code := fieldErr.Tag()
+			if code == "required_if" {
+				code = "required"
+			}
+
+			field := fieldPath(fieldErr.Namespace())
+			fields = append(fields, dto.FieldError{
+				Field:   field,
+				Code:    code,
+				Message: field + " " + fieldErrorMessage(fieldErr),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []dto.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, jsonTypeName(typeErr.Type)),
+		}}
+	}
+
+	return nil
+}
+
+// fieldPath turns a validator namespace such as
+// BulkUpdateUserRequest.<embedded>.ids[0] into the path the client sent,
+// ids[0].
+func fieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")[1:]
+
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment != helpers.EmbeddedField {
+			path = append(path, segment)
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+func fieldErrorMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+
+	switch fieldErr.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "min", "max":
+		bound := "at least"
+		if fieldErr.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters", bound, param)
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("must have %s %s items", bound, param)
+		default:
+			return fmt.Sprintf("must be %s %s", bound, param)
+		}
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a valid UUID"
+	case "unique":
+		return "must not contain duplicates"
+	case "phone":
+		return "must be a valid phone number"
+	case "country_code":
+		return "must be an ISO 3166-1 alpha-2 country code"
+	case "regexp":
+		return "must be a valid regular expression"
+	case "custom_field_key":
+		return "must start with a lowercase letter and contain only lowercase letters, digits and underscores"
+	default:
+		return "is invalid"
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return "an RFC 3339 timestamp"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+func bindJSON[T any](body string) (int, utils.Response) {
+	helpers.SetUpValidator()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.POST("/", func(ctx *gin.Context) {
+		var payload T
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			respondBindError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	var res utils.Response
+	json.Unmarshal(w.Body.Bytes(), &res)
+	return w.Code, res
+}
+
+func TestRespondBindError_ListsEveryField(t *testing.T) {
+	code, res := bindJSON[dto.RegisterUserRequest](`{"name":"abc","email":"a@b","password":""}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", code)
+	}
+
+	want := []dto.FieldError{
+		{Field: "name", Code: "min", Message: "name must be at least 5 characters"},
+		{Field: "email", Code: "email", Message: "email must be a valid email address"},
+		{Field: "password", Code: "required", Message: "password is required"},
+	}
+	if !reflect.DeepEqual(res.Errors, want) {
+		t.Fatalf("unexpected errors: %+v", res.Errors)
+	}
+	if res.Error != "name must be at least 5 characters; email must be a valid email address; password is required" {
+		t.Fatalf("unexpected error summary: %v", res.Error)
+	}
+}
+
+func TestRespondBindError_NestedFieldPaths(t *testing.T) {
+	code, res := bindJSON[dto.BulkUpdateUserRequest](`{"ids":["1","8d9c2c6e-3f4b-4c1a-9a55-0f4f3c1f1b2a","x"],"fields":{"role":"owner"}}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", code)
+	}
+
+	var fields []string
+	for _, fieldErr := range res.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	if want := []string{"ids[0]", "ids[2]", "fields.role"}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("expected fields %v, got %v", want, fields)
+	}
+}
+
+func TestRespondBindError_ConditionalRequired(t *testing.T) {
+	code, res := bindJSON[dto.UpdateUserStatusRequest](`{"status":"suspended"}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", code)
+	}
+	if len(res.Errors) != 2 || res.Errors[0].Code != "required" || res.Errors[1].Field != "suspended_until" {
+		t.Fatalf("unexpected errors: %+v", res.Errors)
+	}
+
+	if code, _ := bindJSON[dto.UpdateUserStatusRequest](`{"status":"active"}`); code != http.StatusNoContent {
+		t.Fatalf("expected active without a reason to pass, got %d", code)
+	}
+}
+
+func TestRespondBindError_WrongType(t *testing.T) {
+	code, res := bindJSON[dto.CreateAddressRequest](`{"type":"home","street":"Jl. Merdeka 1","city":"Jakarta","country":"ID","is_default":"yes"}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", code)
+	}
+
+	want := []dto.FieldError{{Field: "is_default", Code: "type", Message: "is_default must be a boolean"}}
+	if !reflect.DeepEqual(res.Errors, want) {
+		t.Fatalf("unexpected errors: %+v", res.Errors)
+	}
+}
+
+func TestRespondBindError_MalformedJSON(t *testing.T) {
+	code, res := bindJSON[dto.RegisterUserRequest](`{"name":`)
+	if code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", code)
+	}
+	if len(res.Errors) != 0 {
+		t.Fatalf("expected no field errors, got %+v", res.Errors)
+	}
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+)
+
+// Services return the v1 DTOs. These map them to the shape of the API
+// version the request was routed to.
+
+func isAPIVersion2(ctx *gin.Context) bool {
+	return ctx.GetString("api_version") == constants.ENUM_API_VERSION_2
+}
+
+func presentUser(ctx *gin.Context, user dto.UserResponse) any {
+	if isAPIVersion2(ctx) {
+		return user.V2()
+	}
+	return user
+}
+
+func presentUsers(ctx *gin.Context, users []dto.UserResponse) any {
+	if !isAPIVersion2(ctx) || users == nil {
+		return users
+	}
+
+	mapped := make([]dto.UserResponseV2, 0, len(users))
+	for _, user := range users {
+		mapped = append(mapped, user.V2())
+	}
+	return mapped
+}
+
+func presentTrashedUsers(ctx *gin.Context, users []dto.TrashedUserResponse) any {
+	if !isAPIVersion2(ctx) || users == nil {
+		return users
+	}
+
+	mapped := make([]dto.TrashedUserResponseV2, 0, len(users))
+	for _, user := range users {
+		mapped = append(mapped, user.V2())
+	}
+	return mapped
+}
+
+func presentProfile(ctx *gin.Context, profile dto.ProfileResponse) any {
+	if isAPIVersion2(ctx) {
+		return profile.V2()
+	}
+	return profile
+}
@@ -0,0 +1,305 @@
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+// UI is the interactive API reference, a single page that renders the
+// document served at /openapi.json.
+//
+//go:embed ui/index.html
+var UI []byte
+
+type (
+	Document struct {
+		OpenAPI    string                          `json:"openapi"`
+		Info       Info                            `json:"info"`
+		Servers    []Server                        `json:"servers"`
+		Tags       []Tag                           `json:"tags"`
+		Paths      map[string]map[string]*PathItem `json:"paths"`
+		Components Components                      `json:"components"`
+	}
+
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	Server struct {
+		URL         string `json:"url"`
+		Description string `json:"description,omitempty"`
+	}
+
+	Tag struct {
+		Name string `json:"name"`
+	}
+
+	// PathItem is one operation of a path, keyed by its lowercase method.
+	PathItem struct {
+		OperationID string                `json:"operationId"`
+		Summary     string                `json:"summary"`
+		Description string                `json:"description,omitempty"`
+		Tags        []string              `json:"tags"`
+		Security    []map[string][]string `json:"security,omitempty"`
+		Parameters  []Parameter           `json:"parameters,omitempty"`
+		RequestBody *RequestBody          `json:"requestBody,omitempty"`
+		Responses   map[string]Response   `json:"responses"`
+	}
+
+	Parameter struct {
+		Name        string `json:"name"`
+		In          string `json:"in"`
+		Description string `json:"description,omitempty"`
+		Required    bool   `json:"required,omitempty"`
+		Style       string `json:"style,omitempty"`
+		Schema      Schema `json:"schema"`
+	}
+
+	RequestBody struct {
+		Required bool                 `json:"required"`
+		Content  map[string]MediaType `json:"content"`
+	}
+
+	MediaType struct {
+		Schema Schema `json:"schema"`
+	}
+
+	Response struct {
+		Description string               `json:"description,omitempty"`
+		Headers     map[string]Header    `json:"headers,omitempty"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+		Ref         string               `json:"$ref,omitempty"`
+	}
+
+	Header struct {
+		Description string `json:"description"`
+		Schema      Schema `json:"schema"`
+	}
+
+	Components struct {
+		Schemas         map[string]Schema         `json:"schemas"`
+		Responses       map[string]Response       `json:"responses"`
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+	}
+
+	SecurityScheme struct {
+		Type         string `json:"type"`
+		Scheme       string `json:"scheme"`
+		BearerFormat string `json:"bearerFormat,omitempty"`
+	}
+)
+
+// versionSubstitutes are the DTOs a version serves in place of the v1 ones
+// the operations are declared with.
+var versionSubstitutes = map[string]map[reflect.Type]reflect.Type{
+	constants.ENUM_API_VERSION_2: {
+		reflect.TypeOf(dto.UserResponse{}):        reflect.TypeOf(dto.UserResponseV2{}),
+		reflect.TypeOf(dto.TrashedUserResponse{}): reflect.TypeOf(dto.TrashedUserResponseV2{}),
+		reflect.TypeOf(dto.ProfileResponse{}):     reflect.TypeOf(dto.ProfileResponseV2{}),
+	},
+}
+
+var (
+	documents   = make(map[string]*Document)
+	documentsMu sync.Mutex
+)
+
+// OpenAPI returns the OpenAPI 3.1 document of an API version, built from
+// Operations on first use.
+func OpenAPI(version string) *Document {
+	documentsMu.Lock()
+	defer documentsMu.Unlock()
+
+	if document, ok := documents[version]; ok {
+		return document
+	}
+
+	document := build(version)
+	documents[version] = document
+	return document
+}
+
+var pathParameter = regexp.MustCompile(`:(\w+)`)
+
+// PathOf turns a gin route path such as /users/:id into its OpenAPI form,
+// /users/{id}.
+func PathOf(route string) string {
+	return pathParameter.ReplaceAllString(route, "{$1}")
+}
+
+func build(version string) *Document {
+	g := newSchemaGenerator(versionSubstitutes[version])
+
+	servers := []Server{{URL: "/api/" + version}}
+	if version == constants.ENUM_API_VERSION_DEFAULT {
+		servers = append(servers, Server{
+			URL:         "/api",
+			Description: "Unversioned, serves the version named by the Accept header, such as application/json; version=2",
+		})
+	}
+
+	document := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:       "Go Boilerplate API",
+			Version:     version,
+			Description: "Every response is wrapped in the same envelope: status, message and timestamp, with data on success and error on failure.",
+		},
+		Servers: servers,
+		Paths:   make(map[string]map[string]*PathItem),
+		Components: Components{
+			Responses: map[string]Response{
+				"Error": {
+					Description: "The request failed; error says why and code names the failure, such as user_not_found",
+					Content:     jsonContent(g.schemaOf(utils.Response{})),
+				},
+				"InternalError": {
+					Description: "The server failed; error only carries a safe message and code names the failed step",
+					Content:     jsonContent(g.schemaOf(utils.Response{})),
+				},
+				"ValidationError": {
+					Description: "Fields of the request failed validation; errors lists each of them",
+					Content:     jsonContent(g.schemaOf(utils.Response{})),
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	seenTags := make(map[string]bool)
+	for _, operation := range Operations {
+		if !seenTags[operation.Tag] {
+			seenTags[operation.Tag] = true
+			document.Tags = append(document.Tags, Tag{Name: operation.Tag})
+		}
+
+		path := PathOf(operation.Path)
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(map[string]*PathItem)
+		}
+		document.Paths[path][strings.ToLower(operation.Method)] = g.pathItem(operation)
+	}
+
+	document.Components.Schemas = g.components
+	return document
+}
+
+func (g *schemaGenerator) pathItem(operation Operation) *PathItem {
+	item := &PathItem{
+		OperationID: operation.ID,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Tags:        []string{operation.Tag},
+		Responses:   make(map[string]Response),
+	}
+
+	for _, match := range pathParameter.FindAllStringSubmatch(operation.Path, -1) {
+		item.Parameters = append(item.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   Schema{"type": "string", "format": "uuid"},
+		})
+	}
+	item.Parameters = append(item.Parameters, g.queryParameters(operation.Query)...)
+	item.Parameters = append(item.Parameters, operation.Parameters...)
+
+	if operation.Body != nil {
+		item.RequestBody = &RequestBody{Required: true, Content: jsonContent(g.schemaOf(operation.Body))}
+	}
+	if len(operation.Upload) > 0 {
+		content := fileContent(operation.Upload)
+		content["multipart/form-data"] = MediaType{Schema: Schema{
+			"type":       "object",
+			"properties": map[string]Schema{"file": {"type": "string", "format": "binary"}},
+		}}
+		item.RequestBody = &RequestBody{Required: true, Content: content}
+	}
+
+	status := operation.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	item.Responses[strconv.Itoa(status)] = g.successResponse(operation)
+
+	item.Responses["400"] = Response{Ref: "#/components/responses/Error"}
+	if operation.Body != nil || operation.Query != nil {
+		item.Responses["422"] = Response{Ref: "#/components/responses/ValidationError"}
+	}
+	if operation.Auth != AuthNone {
+		item.Security = []map[string][]string{{"bearerAuth": {}}}
+		item.Responses["401"] = Response{Ref: "#/components/responses/Error"}
+		item.Responses["403"] = Response{Ref: "#/components/responses/Error"}
+		if operation.Auth == AuthAdmin {
+			item.Description = strings.TrimSpace("Admins only. " + item.Description)
+		}
+	}
+	if strings.Contains(operation.Path, ":") {
+		item.Responses["404"] = Response{Ref: "#/components/responses/Error"}
+	}
+	if operation.Method == http.MethodPost || operation.Method == http.MethodPatch {
+		item.Responses["409"] = Response{Ref: "#/components/responses/Error"}
+	}
+	item.Responses["500"] = Response{Ref: "#/components/responses/InternalError"}
+
+	return item
+}
+
+func (g *schemaGenerator) successResponse(operation Operation) Response {
+	if len(operation.File) > 0 {
+		return Response{Description: "The file", Content: fileContent(operation.File)}
+	}
+
+	properties := map[string]Schema{"data": g.schemaOf(operation.Response)}
+	if operation.Paginated {
+		properties["meta"] = g.schemaOf(dto.PaginationResponse{})
+	}
+
+	response := Response{
+		Description: "Success",
+		Content: jsonContent(Schema{"allOf": []Schema{
+			g.schemaOf(utils.Response{}),
+			{"type": "object", "properties": properties},
+		}}),
+	}
+
+	if operation.Paginated {
+		response.Headers = map[string]Header{
+			"Link": {
+				Description: "RFC 8288 links to the first, prev, next and last pages",
+				Schema:      Schema{"type": "string"},
+			},
+			"X-Total-Count": {
+				Description: "Number of rows matching the query",
+				Schema:      Schema{"type": "integer"},
+			},
+		}
+	}
+
+	return response
+}
+
+func jsonContent(schema Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func fileContent(mediaTypes []string) map[string]MediaType {
+	content := make(map[string]MediaType, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = MediaType{Schema: Schema{"type": "string", "format": "binary"}}
+	}
+	return content
+}
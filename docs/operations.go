@@ -0,0 +1,287 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/mferdian/golang_boiller_plate/dto"
+)
+
+// Who may call an operation.
+const (
+	AuthNone  = ""
+	AuthUser  = "user"
+	AuthAdmin = "admin"
+)
+
+// Operation documents one route of the API. Paths are gin paths relative to
+// the version prefix, and DTOs are given as zero values of the v1 types;
+// newer versions swap in their own.
+type Operation struct {
+	Method      string
+	Path        string
+	ID          string
+	Tag         string
+	Summary     string
+	Description string
+	Auth        string
+
+	// Query is a DTO whose form tags are the query parameters, and
+	// Parameters lists the ones it cannot describe.
+	Query      any
+	Parameters []Parameter
+
+	// Body is the JSON request body. Upload lists the raw media types of an
+	// operation that takes a file instead, also accepted as the file part of
+	// a multipart form.
+	Body   any
+	Upload []string
+
+	// Response is the data member of the success envelope, sent with
+	// Status, or 200. File lists the media types of an operation that
+	// answers with a file instead.
+	Response  any
+	Status    int
+	Paginated bool
+	File      []string
+}
+
+var (
+	filterParameter = Parameter{
+		Name:        "filter",
+		In:          "query",
+		Description: "Filters as filter[field]=value or filter[field][op]=value, with op one of eq, ne, gt, gte, lt, lte, in, nin, contains and null. A date without a time stands for the whole day",
+		Style:       "deepObject",
+		Schema:      Schema{"type": "object", "additionalProperties": Schema{"type": "string"}},
+	}
+
+	customFieldsParameter = Parameter{
+		Name:        "custom_fields",
+		In:          "query",
+		Description: "Custom field filters as custom_fields[key]=value",
+		Style:       "deepObject",
+		Schema:      Schema{"type": "object", "additionalProperties": Schema{"type": "string"}},
+	}
+
+	unpaginatedParameter = Parameter{
+		Name:        "pagination",
+		In:          "query",
+		Description: "false returns every matching user in one unpaginated list",
+		Schema:      Schema{"type": "boolean", "default": true},
+	}
+)
+
+// Operations lists every route the API serves. A test checks it against the
+// route table, so a route cannot be added without documenting it here.
+var Operations = []Operation{
+	// Auth
+	{
+		Method: http.MethodPost, Path: "/register", ID: "register", Tag: "Auth",
+		Summary: "Register an account",
+		Body:    dto.RegisterUserRequest{}, Response: dto.RegisterUserResponse{}, Status: http.StatusCreated,
+	},
+	{
+		Method: http.MethodPost, Path: "/login", ID: "login", Tag: "Auth",
+		Summary: "Log in with email and password",
+		Body:    dto.LoginUserRequest{}, Response: dto.LoginResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/confirm-email", ID: "confirmEmailChange", Tag: "Auth",
+		Summary: "Confirm an email change with the token that was mailed",
+		Query:   dto.ConfirmEmailChangeRequest{}, Response: dto.UserResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/auth/accept-invite", ID: "acceptInvitation", Tag: "Auth",
+		Summary: "Create an account from an invitation",
+		Body:    dto.AcceptInvitationRequest{}, Response: dto.UserResponse{}, Status: http.StatusCreated,
+	},
+
+	// Current user
+	{
+		Method: http.MethodGet, Path: "/me", ID: "getMe", Tag: "Me", Auth: AuthUser,
+		Summary:  "Get the signed-in user's profile",
+		Response: dto.ProfileResponse{},
+	},
+	{
+		Method: http.MethodPatch, Path: "/me", ID: "updateMe", Tag: "Me", Auth: AuthUser,
+		Summary: "Update the signed-in user's profile",
+		Body:    dto.UpdateUserRequest{}, Response: dto.ProfileResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/me", ID: "deleteMe", Tag: "Me", Auth: AuthUser,
+		Summary:     "Schedule the signed-in user's account for erasure",
+		Description: "Logging in again before the grace period ends cancels the erasure.",
+		Response:    dto.AccountDeletionResponse{}, Status: http.StatusAccepted,
+	},
+
+	// Users
+	{
+		Method: http.MethodPost, Path: "/users", ID: "createUser", Tag: "Users", Auth: AuthAdmin,
+		Summary: "Create a user",
+		Body:    dto.CreateUserRequest{}, Response: dto.UserResponse{}, Status: http.StatusCreated,
+	},
+	{
+		Method: http.MethodGet, Path: "/users", ID: "listUsers", Tag: "Users", Auth: AuthAdmin,
+		Summary:    "List users",
+		Query:      dto.UserPaginationRequest{},
+		Parameters: []Parameter{filterParameter, customFieldsParameter, unpaginatedParameter},
+		Response:   []dto.UserResponse{}, Paginated: true,
+	},
+	{
+		Method: http.MethodPost, Path: "/users/import", ID: "importUsers", Tag: "Users", Auth: AuthAdmin,
+		Summary:     "Import users from a CSV or JSON file",
+		Description: "Rows are checked like a created user, custom fields included; CSV files carry those in custom_fields.<key> columns. Answers 201 when users were created and 200 for a dry run or when none were.",
+		Query:       dto.ImportUserRequest{}, Upload: []string{"text/csv", "application/json"}, Response: dto.ImportUserResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/users/export", ID: "exportUsers", Tag: "Users", Auth: AuthAdmin,
+		Summary:    "Export users as CSV, NDJSON or XLSX",
+		Parameters: []Parameter{filterParameter, customFieldsParameter},
+		Query:      dto.ExportUserRequest{}, File: []string{"text/csv", "application/x-ndjson", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	},
+	{
+		Method: http.MethodPatch, Path: "/users/bulk", ID: "bulkUpdateUsers", Tag: "Users", Auth: AuthAdmin,
+		Summary: "Update many users at once",
+		Body:    dto.BulkUpdateUserRequest{}, Response: dto.BulkUserResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/users/bulk", ID: "bulkDeleteUsers", Tag: "Users", Auth: AuthAdmin,
+		Summary: "Delete many users at once",
+		Body:    dto.BulkDeleteUserRequest{}, Response: dto.BulkUserResponse{},
+	},
+	{
+		Method: http.MethodPatch, Path: "/users/:id/status", ID: "updateUserStatus", Tag: "Users", Auth: AuthAdmin,
+		Summary: "Activate, suspend or ban a user",
+		Body:    dto.UpdateUserStatusRequest{}, Response: dto.UserStatusResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/users/:id", ID: "getUser", Tag: "Users", Auth: AuthUser,
+		Summary:     "Get a user",
+		Description: "Users can only get their own account.",
+		Response:    dto.UserResponse{},
+	},
+	{
+		Method: http.MethodPatch, Path: "/users/:id", ID: "updateUser", Tag: "Users", Auth: AuthUser,
+		Summary:     "Update a user",
+		Description: "Users can only update their own account.",
+		Body:        dto.UpdateUserRequest{}, Response: dto.UserResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/users/:id", ID: "deleteUser", Tag: "Users", Auth: AuthUser,
+		Summary:     "Delete a user",
+		Description: "Deleting your own account schedules its erasure instead and answers 202 with the schedule.",
+		Response:    dto.UserResponse{},
+	},
+
+	// Trash
+	{
+		Method: http.MethodGet, Path: "/users/trash", ID: "listTrashedUsers", Tag: "Trash", Auth: AuthAdmin,
+		Summary:    "List soft-deleted users",
+		Query:      dto.UserPaginationRequest{},
+		Parameters: []Parameter{filterParameter},
+		Response:   []dto.TrashedUserResponse{}, Paginated: true,
+	},
+	{
+		Method: http.MethodPost, Path: "/users/:id/restore", ID: "restoreUser", Tag: "Trash", Auth: AuthAdmin,
+		Summary:  "Restore a soft-deleted user",
+		Response: dto.UserResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/users/:id/purge", ID: "purgeUser", Tag: "Trash", Auth: AuthAdmin,
+		Summary:  "Delete a soft-deleted user for good",
+		Response: dto.UserResponse{},
+	},
+
+	// Data exports
+	{
+		Method: http.MethodPost, Path: "/users/:id/export", ID: "requestDataExport", Tag: "Data exports", Auth: AuthUser,
+		Summary:  "Request an export of a user's personal data",
+		Response: dto.DataExportResponse{}, Status: http.StatusAccepted,
+	},
+	{
+		Method: http.MethodGet, Path: "/users/:id/export/:exportId", ID: "downloadDataExport", Tag: "Data exports", Auth: AuthUser,
+		Summary:     "Download a personal data export",
+		Description: "Answers 202 with the export while it is still being prepared.",
+		File:        []string{"application/zip"},
+	},
+
+	// Addresses
+	{
+		Method: http.MethodGet, Path: "/users/:id/addresses", ID: "listAddresses", Tag: "Addresses", Auth: AuthUser,
+		Summary:  "List a user's addresses",
+		Response: []dto.AddressResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/:id/addresses", ID: "createAddress", Tag: "Addresses", Auth: AuthUser,
+		Summary: "Add an address",
+		Body:    dto.CreateAddressRequest{}, Response: dto.AddressResponse{}, Status: http.StatusCreated,
+	},
+	{
+		Method: http.MethodPatch, Path: "/users/:id/addresses/:addressId", ID: "updateAddress", Tag: "Addresses", Auth: AuthUser,
+		Summary: "Update an address",
+		Body:    dto.UpdateAddressRequest{}, Response: dto.AddressResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/users/:id/addresses/:addressId", ID: "deleteAddress", Tag: "Addresses", Auth: AuthUser,
+		Summary:  "Delete an address",
+		Response: dto.AddressResponse{},
+	},
+
+	// Preferences
+	{
+		Method: http.MethodGet, Path: "/users/:id/preferences", ID: "getPreferences", Tag: "Preferences", Auth: AuthUser,
+		Summary:  "Get a user's preferences, defaults applied",
+		Response: dto.UserPreferences{},
+	},
+	{
+		Method: http.MethodPatch, Path: "/users/:id/preferences", ID: "updatePreferences", Tag: "Preferences", Auth: AuthUser,
+		Summary:     "Update a user's preferences",
+		Description: "The body is a JSON Merge Patch: members left out stay as they are and null resets one to its default.",
+		Body:        dto.UserPreferences{}, Response: dto.UserPreferences{},
+	},
+
+	// Invitations
+	{
+		Method: http.MethodPost, Path: "/invitations", ID: "createInvitation", Tag: "Invitations", Auth: AuthAdmin,
+		Summary: "Invite someone by email",
+		Body:    dto.CreateInvitationRequest{}, Response: dto.InvitationResponse{}, Status: http.StatusCreated,
+	},
+	{
+		Method: http.MethodGet, Path: "/invitations", ID: "listInvitations", Tag: "Invitations", Auth: AuthAdmin,
+		Summary:    "List invitations",
+		Query:      dto.InvitationPaginationRequest{},
+		Parameters: []Parameter{filterParameter},
+		Response:   []dto.InvitationResponse{}, Paginated: true,
+	},
+	{
+		Method: http.MethodPost, Path: "/invitations/:id/resend", ID: "resendInvitation", Tag: "Invitations", Auth: AuthAdmin,
+		Summary:  "Send an invitation again with a fresh token",
+		Response: dto.InvitationResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/invitations/:id", ID: "revokeInvitation", Tag: "Invitations", Auth: AuthAdmin,
+		Summary:  "Revoke an invitation",
+		Response: dto.InvitationResponse{},
+	},
+
+	// Custom fields
+	{
+		Method: http.MethodGet, Path: "/custom-fields", ID: "listCustomFields", Tag: "Custom fields", Auth: AuthUser,
+		Summary:  "List the custom profile fields",
+		Response: []dto.CustomFieldResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/custom-fields", ID: "createCustomField", Tag: "Custom fields", Auth: AuthAdmin,
+		Summary: "Define a custom profile field",
+		Body:    dto.CreateCustomFieldRequest{}, Response: dto.CustomFieldResponse{}, Status: http.StatusCreated,
+	},
+	{
+		Method: http.MethodPatch, Path: "/custom-fields/:id", ID: "updateCustomField", Tag: "Custom fields", Auth: AuthAdmin,
+		Summary: "Update a custom profile field",
+		Body:    dto.UpdateCustomFieldRequest{}, Response: dto.CustomFieldResponse{},
+	},
+	{
+		Method: http.MethodDelete, Path: "/custom-fields/:id", ID: "deleteCustomField", Tag: "Custom fields", Auth: AuthAdmin,
+		Summary:  "Delete a custom profile field and its values",
+		Response: dto.CustomFieldResponse{},
+	},
+}
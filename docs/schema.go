@@ -0,0 +1,226 @@
+package docs
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Schema is a JSON Schema object as OpenAPI 3.1 uses it.
+type Schema map[string]any
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+)
+
+// schemaGenerator turns DTO types into schemas. Named structs become
+// components referenced by $ref, so each is described once per document.
+type schemaGenerator struct {
+	components map[string]Schema
+	// substitutes swaps a type for the one a version serves in its place
+	substitutes map[reflect.Type]reflect.Type
+}
+
+func newSchemaGenerator(substitutes map[reflect.Type]reflect.Type) *schemaGenerator {
+	return &schemaGenerator{
+		components:  make(map[string]Schema),
+		substitutes: substitutes,
+	}
+}
+
+// schemaOf describes the type of v, which may be a value or a nil pointer to
+// one.
+func (g *schemaGenerator) schemaOf(v any) Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) Schema {
+	if t == nil {
+		return Schema{}
+	}
+	if substitute, ok := g.substitutes[t]; ok {
+		t = substitute
+	}
+
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case uuidType:
+		return Schema{"type": "string", "format": "uuid"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "format": "byte"}
+		}
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.components[t.Name()]; !ok {
+			// Reserve the name first, so a type that refers to itself ends
+			// in a $ref instead of recursing forever.
+			g.components[t.Name()] = Schema{}
+			g.components[t.Name()] = g.object(t)
+		}
+		return Schema{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return Schema{}
+	}
+}
+
+// object lists the JSON members of a struct, with embedded structs flattened
+// the way encoding/json does, and the rules their binding tags enforce.
+func (g *schemaGenerator) object(t reflect.Type) Schema {
+	properties := make(map[string]Schema)
+	var required []string
+
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+
+			name, _, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" {
+				embedded := field.Type
+				if embedded.Kind() == reflect.Pointer {
+					embedded = embedded.Elem()
+				}
+				if substitute, ok := g.substitutes[embedded]; ok {
+					embedded = substitute
+				}
+				if embedded.Kind() == reflect.Struct {
+					collect(embedded)
+					continue
+				}
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			schema, isRequired := constraints(g.schema(field.Type), field)
+			properties[name] = schema
+			if isRequired {
+				required = append(required, name)
+			}
+		}
+	}
+	collect(t)
+
+	object := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// constraints adds to schema the rules of a field's binding tag that JSON
+// Schema can express, and reports whether the field is required. Rules after
+// dive apply to the elements and are left out.
+func constraints(schema Schema, field reflect.StructField) (Schema, bool) {
+	tag, _, _ := strings.Cut(field.Tag.Get("binding"), "dive")
+	if tag == "" || schema["$ref"] != nil {
+		return schema, strings.Contains(","+tag+",", ",required,")
+	}
+
+	constrained := make(Schema, len(schema))
+	for key, value := range schema {
+		constrained[key] = value
+	}
+
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			constrained["enum"] = strings.Fields(param)
+		case "email":
+			constrained["format"] = "email"
+		case "uuid":
+			constrained["format"] = "uuid"
+		case "unique":
+			constrained["uniqueItems"] = true
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			switch constrained["type"] {
+			case "string":
+				constrained[name+"Length"] = n
+			case "array":
+				constrained[name+"Items"] = n
+			case "integer", "number":
+				if name == "min" {
+					constrained["minimum"] = n
+				} else {
+					constrained["maximum"] = n
+				}
+			}
+		}
+	}
+
+	return constrained, required
+}
+
+// queryParameters lists the form-tagged fields of a query DTO, embedded
+// structs included. Fields bound some other way are tagged form:"-".
+func (g *schemaGenerator) queryParameters(v any) []Parameter {
+	if v == nil {
+		return nil
+	}
+
+	var parameters []Parameter
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				collect(field.Type)
+				continue
+			}
+
+			name := field.Tag.Get("form")
+			if name == "" || name == "-" {
+				continue
+			}
+			schema, required := constraints(g.schema(field.Type), field)
+			parameters = append(parameters, Parameter{
+				Name:     name,
+				In:       "query",
+				Required: required,
+				Schema:   schema,
+			})
+		}
+	}
+	collect(reflect.TypeOf(v))
+	return parameters
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	AddressResponse struct {
+		ID         uuid.UUID `json:"id"`
+		Type       string    `json:"type"`
+		IsDefault  bool      `json:"is_default"`
+		Street     string    `json:"street"`
+		City       string    `json:"city"`
+		Region     string    `json:"region"`
+		PostalCode string    `json:"postal_code"`
+		Country    string    `json:"country"`
+		CreatedAt  time.Time `json:"created_at"`
+		UpdatedAt  time.Time `json:"updated_at"`
+	}
+
+	GetAddressesRequest struct {
+		UserID string `json:"-"`
+	}
+
+	CreateAddressRequest struct {
+		UserID     string `json:"-"`
+		Type       string `json:"type" binding:"required,oneof=home billing shipping"`
+		IsDefault  bool   `json:"is_default"`
+		Street     string `json:"street" binding:"required"`
+		City       string `json:"city" binding:"required"`
+		Region     string `json:"region"`
+		PostalCode string `json:"postal_code"`
+		Country    string `json:"country" binding:"required,country_code"`
+	}
+
+	UpdateAddressRequest struct {
+		UserID     string  `json:"-"`
+		AddressID  string  `json:"-"`
+		Type       *string `json:"type,omitempty" binding:"omitnil,oneof=home billing shipping"`
+		IsDefault  *bool   `json:"is_default,omitempty"`
+		Street     *string `json:"street,omitempty" binding:"omitnil,min=1"`
+		City       *string `json:"city,omitempty" binding:"omitnil,min=1"`
+		Region     *string `json:"region,omitempty"`
+		PostalCode *string `json:"postal_code,omitempty"`
+		Country    *string `json:"country,omitempty" binding:"omitnil,country_code"`
+	}
+
+	DeleteAddressRequest struct {
+		UserID    string `json:"-"`
+		AddressID string `json:"-"`
+	}
+)
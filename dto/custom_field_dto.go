@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	CustomFieldResponse struct {
+		ID        uuid.UUID `json:"id"`
+		Key       string    `json:"key"`
+		Label     string    `json:"label"`
+		Type      string    `json:"type"`
+		Required  bool      `json:"required"`
+		Pattern   string    `json:"pattern,omitempty"`
+		Options   []string  `json:"options,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	CreateCustomFieldRequest struct {
+		Key      string   `json:"key" binding:"required,custom_field_key"`
+		Label    string   `json:"label" binding:"required"`
+		Type     string   `json:"type" binding:"required,oneof=string number boolean date enum"`
+		Required bool     `json:"required"`
+		Pattern  string   `json:"pattern" binding:"omitempty,regexp"`
+		Options  []string `json:"options" binding:"omitempty,unique,dive,required"`
+	}
+
+	// UpdateCustomFieldRequest cannot change the key or the type, values
+	// already stored for users would no longer match them.
+	UpdateCustomFieldRequest struct {
+		FieldID  string    `json:"-"`
+		Label    *string   `json:"label,omitempty" binding:"omitnil,min=1"`
+		Required *bool     `json:"required,omitempty"`
+		Pattern  *string   `json:"pattern,omitempty" binding:"omitnil,regexp"`
+		Options  *[]string `json:"options,omitempty" binding:"omitnil,unique,dive,required"`
+	}
+
+	DeleteCustomFieldRequest struct {
+		FieldID string `json:"-"`
+	}
+)
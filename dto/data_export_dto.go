@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	DataExportRequest struct {
+		UserID      string `json:"-"`
+		RequestedBy string `json:"-"`
+	}
+
+	GetDataExportRequest struct {
+		UserID   string `json:"-"`
+		ExportID string `json:"-"`
+	}
+
+	DataExportResponse struct {
+		ID          uuid.UUID  `json:"id"`
+		UserID      uuid.UUID  `json:"user_id"`
+		Status      string     `json:"status"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+		ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+		FilePath    string     `json:"-"`
+	}
+
+	DataExportManifest struct {
+		UserID      uuid.UUID `json:"user_id"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Files       []string  `json:"files"`
+	}
+
+	DataExportProfile struct {
+		ID                  uuid.UUID  `json:"id"`
+		Name                string     `json:"name"`
+		Email               string     `json:"email"`
+		PendingEmail        string     `json:"pending_email,omitempty"`
+		EmailVerifiedAt     *time.Time `json:"email_verified_at"`
+		PhoneNumber         string     `json:"phone_number"`
+		Address             string     `json:"address"`
+		Role                string     `json:"role"`
+		LastLoginAt         *time.Time `json:"last_login_at"`
+		LastLoginIP         string     `json:"last_login_ip"`
+		DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+		DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+		CreatedAt           time.Time  `json:"created_at"`
+		UpdatedAt           time.Time  `json:"updated_at"`
+	}
+)
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/model"
+)
+
+type (
+	InvitationResponse struct {
+		ID         uuid.UUID  `json:"id"`
+		Email      string     `json:"email"`
+		Role       string     `json:"role"`
+		Status     string     `json:"status"`
+		InvitedBy  uuid.UUID  `json:"invited_by"`
+		ExpiresAt  time.Time  `json:"expires_at"`
+		AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+		RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+		UserID     *uuid.UUID `json:"user_id,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+	}
+
+	CreateInvitationRequest struct {
+		Email     string `json:"email" binding:"required,email"`
+		Role      string `json:"role" binding:"omitempty,oneof=user admin"`
+		InvitedBy string `json:"-"`
+	}
+
+	ResendInvitationRequest struct {
+		InvitationID string `json:"-"`
+	}
+
+	RevokeInvitationRequest struct {
+		InvitationID string `json:"-"`
+	}
+
+	AcceptInvitationRequest struct {
+		Token    string `json:"token" binding:"required"`
+		Name     string `json:"name" binding:"required,min=5"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+
+	InvitationPaginationRequest struct {
+		PaginationRequest
+		Status string `form:"status" binding:"omitempty,oneof=pending accepted revoked expired"`
+	}
+
+	InvitationPaginationResponse struct {
+		PaginationResponse
+		Data []InvitationResponse `json:"data"`
+	}
+
+	InvitationPaginationRepositoryResponse struct {
+		PaginationResponse
+		Invitations []model.Invitation
+	}
+)
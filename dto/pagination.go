@@ -1,21 +1,117 @@
 package dto
 
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+)
 
 type (
 	PaginationRequest struct {
-		Search  string `form:"search"`
-		Page    int    `form:"page"`
-		PerPage int    `form:"per_page"`
+		Search  string            `form:"search"`
+		Page    int               `form:"page" binding:"min=0"`
+		PerPage int               `form:"per_page" binding:"min=0"`
+		Sort    string            `form:"sort"`
+		Filters []FilterCondition `form:"-"`
+
+		// Cursor and Limit select keyset pagination instead of pages
+		Cursor string `form:"cursor"`
+		Limit  int    `form:"limit" binding:"min=0"`
 	}
 
 	PaginationResponse struct {
-		Page    int   `json:"page"`
-		PerPage int   `json:"per_page"`
-		MaxPage int64 `json:"max_page"`
-		Count   int64 `json:"count"`
+		Page       int    `json:"page"`
+		PerPage    int    `json:"per_page"`
+		MaxPage    int64  `json:"max_page"`
+		Count      int64  `json:"count"`
+		NextCursor string `json:"next_cursor,omitempty"`
+		PrevCursor string `json:"prev_cursor,omitempty"`
+
+		Links *PaginationLinks `json:"links,omitempty"`
+	}
+
+	// PaginationLinks are the URLs of the pages around the current one, the
+	// same as the list's Link header. Pages that do not exist are left out,
+	// and cursor pages have no last.
+	PaginationLinks struct {
+		First string `json:"first,omitempty"`
+		Prev  string `json:"prev,omitempty"`
+		Next  string `json:"next,omitempty"`
+		Last  string `json:"last,omitempty"`
+	}
+
+	// FilterCondition is one filter[field][operator]=value query parameter,
+	// or one entry of the filters of a JSON body, not yet checked against
+	// the fields a list allows.
+	FilterCondition struct {
+		Field    string `json:"field"`
+		Operator string `json:"operator"`
+		Value    string `json:"value"`
 	}
 )
 
+// ParseFilterQuery collects the filter parameters of a query string, such as
+// filter[role]=admin or filter[created_at][gte]=2026-01-01. A filter without
+// an operator compares for equality.
+func ParseFilterQuery(query url.Values) ([]FilterCondition, error) {
+	var conditions []FilterCondition
+	for key, values := range query {
+		rest, ok := strings.CutPrefix(key, "filter[")
+		if !ok {
+			continue
+		}
+
+		field, rest, ok := strings.Cut(rest, "]")
+		if !ok || field == "" {
+			return nil, fmt.Errorf("%w: malformed parameter %q", constants.ErrInvalidFilter, key)
+		}
+
+		operator := constants.ENUM_FILTER_OPERATOR_EQ
+		if rest != "" {
+			if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") || len(rest) < 3 || strings.ContainsAny(rest[1:len(rest)-1], "[]") {
+				return nil, fmt.Errorf("%w: malformed parameter %q", constants.ErrInvalidFilter, key)
+			}
+			operator = rest[1 : len(rest)-1]
+		}
+
+		for _, value := range values {
+			conditions = append(conditions, FilterCondition{Field: field, Operator: operator, Value: value})
+		}
+	}
+
+	sort.Slice(conditions, func(i, j int) bool {
+		if conditions[i].Field != conditions[j].Field {
+			return conditions[i].Field < conditions[j].Field
+		}
+		return conditions[i].Operator < conditions[j].Operator
+	})
+
+	return conditions, nil
+}
+
+// SplitList reads a comma separated query parameter such as
+// fields=id,name, dropping blanks and repeats.
+func SplitList(value string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		item := strings.TrimSpace(part)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+func (p *PaginationRequest) IsCursor() bool {
+	return p.Cursor != "" || p.Limit > 0
+}
+
 func (p *PaginationRequest) GetOffset() int {
 	return (p.Page - 1) * p.PerPage
 }
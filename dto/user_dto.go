@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/mferdian/golang_boiller_plate/model"
 )
@@ -12,12 +14,56 @@ type (
 		Email       string    `json:"email"`
 		PhoneNumber string    `json:"phone_number"`
 		Address     string    `json:"address"`
+		Status      string    `json:"status"`
+
+		PendingEmail string `json:"pending_email,omitempty"`
+
+		LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+		LastLoginIP string     `json:"last_login_ip,omitempty"`
+		LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+
+		CustomFields map[string]any `json:"custom_fields,omitempty"`
+
+		// Highlights is the name and email with the fragments that matched
+		// the search wrapped in <mark> tags, in searched lists only
+		Highlights map[string]string `json:"highlights,omitempty"`
+
+		// Addresses is filled in by expand=addresses. Users without any
+		// leave it out.
+		Addresses []AddressResponse `json:"addresses,omitempty"`
+	}
+
+	// ProfileResponse is what the signed-in user sees about themselves on
+	// /api/me, a superset of UserResponse.
+	ProfileResponse struct {
+		ID              uuid.UUID  `json:"id"`
+		Name            string     `json:"name"`
+		Email           string     `json:"email"`
+		PendingEmail    string     `json:"pending_email,omitempty"`
+		EmailVerified   bool       `json:"email_verified"`
+		EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+		PhoneNumber     string     `json:"phone_number"`
+		Address         string     `json:"address"`
+		Role            string     `json:"role"`
+
+		Status         string     `json:"status"`
+		StatusReason   string     `json:"status_reason,omitempty"`
+		SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+
+		DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+
+		LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+
+		CustomFields map[string]any `json:"custom_fields,omitempty"`
+
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
 	}
 
 	RegisterUserRequest struct {
-		Name     string `json:"name"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Name     string `json:"name" binding:"required,min=5"`
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"required,min=8"`
 	}
 
 	RegisterUserResponse struct {
@@ -27,8 +73,9 @@ type (
 	}
 
 	LoginUserRequest struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email    string `json:"email" binding:"required"`
+		Password string `json:"password" binding:"required"`
+		IP       string `json:"-"`
 	}
 
 	LoginResponse struct {
@@ -37,29 +84,185 @@ type (
 	}
 
 	CreateUserRequest struct {
-		Name        string `json:"name"`
-		Email       string `json:"email"`
-		Password    string `json:"password"`
-		PhoneNumber string `json:"phone_number"`
+		Name        string `json:"name" binding:"required,min=5"`
+		Email       string `json:"email" binding:"required,email"`
+		Password    string `json:"password" binding:"required,min=8"`
+		PhoneNumber string `json:"phone_number" binding:"omitempty,phone"`
 		Address     string `json:"address"`
+
+		CustomFields map[string]any `json:"custom_fields,omitempty"`
 	}
 
 	UpdateUserRequest struct {
 		ID          string  `json:"-"`
-		Name        *string `json:"name,omitempty"`
-		Email       *string `json:"email,omitempty"`
-		Password    *string `json:"password,omitempty"`
-		PhoneNumber *string `json:"phone_number,omitempty"`
+		Name        *string `json:"name,omitempty" binding:"omitnil,min=5"`
+		Email       *string `json:"email,omitempty" binding:"omitnil,email"`
+		Password    *string `json:"password,omitempty" binding:"omitnil,min=8"`
+		PhoneNumber *string `json:"phone_number,omitempty" binding:"omitempty,phone"`
 		Address     *string `json:"address,omitempty"`
+
+		// CustomFields only changes the fields it names, null clears one.
+		CustomFields map[string]any `json:"custom_fields,omitempty"`
+	}
+
+	ConfirmEmailChangeRequest struct {
+		Token string `form:"token" binding:"required"`
 	}
 
 	DeleteUserRequest struct {
 		UserID string `json:"-"`
 	}
 
+	AccountDeletionRequest struct {
+		UserID string `json:"-"`
+	}
+
+	AccountDeletionResponse struct {
+		ID                  uuid.UUID `json:"id"`
+		DeletionRequestedAt time.Time `json:"deletion_requested_at"`
+		DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+	}
+
+	UpdateUserStatusRequest struct {
+		UserID         string     `json:"-"`
+		ChangedBy      string     `json:"-"`
+		Status         string     `json:"status" binding:"required,oneof=active suspended banned"`
+		Reason         string     `json:"reason" binding:"required_if=Status suspended,required_if=Status banned"`
+		SuspendedUntil *time.Time `json:"suspended_until" binding:"required_if=Status suspended"`
+	}
+
+	UserStatusResponse struct {
+		ID              uuid.UUID  `json:"id"`
+		Status          string     `json:"status"`
+		StatusReason    string     `json:"status_reason,omitempty"`
+		SuspendedUntil  *time.Time `json:"suspended_until,omitempty"`
+		StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	}
+
+	RestoreUserRequest struct {
+		UserID string `json:"-"`
+	}
+
+	PurgeUserRequest struct {
+		UserID string `json:"-"`
+	}
+
+	TrashedUserResponse struct {
+		UserResponse
+		DeletedAt time.Time `json:"deleted_at"`
+	}
+
+	ImportUserRequest struct {
+		Format  string `form:"format" binding:"omitempty,oneof=csv json"`
+		Mode    string `form:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+		DryRun  bool   `form:"dry_run"`
+		Content []byte `form:"-"`
+	}
+
+	ImportUserRow struct {
+		Name        string `json:"name"`
+		Email       string `json:"email"`
+		Password    string `json:"password"`
+		PhoneNumber string `json:"phone_number"`
+		Address     string `json:"address"`
+		Role        string `json:"role"`
+
+		// CustomFields holds values keyed by custom field key. CSV files
+		// carry them in custom_fields.<key> columns.
+		CustomFields map[string]any `json:"custom_fields"`
+	}
+
+	ImportUserRowResult struct {
+		Row    int        `json:"row"`
+		Email  string     `json:"email"`
+		Status string     `json:"status"`
+		ID     *uuid.UUID `json:"id,omitempty"`
+		Error  string     `json:"error,omitempty"`
+	}
+
+	ImportUserResponse struct {
+		Mode      string                `json:"mode"`
+		DryRun    bool                  `json:"dry_run"`
+		Committed bool                  `json:"committed"`
+		Total     int                   `json:"total"`
+		Created   int                   `json:"created"`
+		Skipped   int                   `json:"skipped"`
+		Failed    int                   `json:"failed"`
+		Rows      []ImportUserRowResult `json:"rows"`
+	}
+
+	// ExportUserRequest takes the same filters as the user list.
+	ExportUserRequest struct {
+		Format       string            `form:"format" binding:"omitempty,oneof=csv ndjson xlsx"`
+		Search       string            `form:"search"`
+		UserID       string            `form:"id"`
+		InactiveDays int               `form:"inactive_days" binding:"min=0"`
+		Filters      []FilterCondition `form:"-"`
+		CustomFields map[string]string `form:"-"`
+	}
+
+	// BulkUserFilter selects users the way the user list filters them, so a
+	// bulk action hits the users an admin sees listed. Filters take the same
+	// fields and operators as filter[field][operator] on the list, with eq
+	// when the operator is left out.
+	BulkUserFilter struct {
+		Search       string            `json:"search"`
+		Role         string            `json:"role" binding:"omitempty,oneof=user admin"`
+		InactiveDays int               `json:"inactive_days" binding:"min=0"`
+		CustomFields map[string]string `json:"custom_fields"`
+		Filters      []FilterCondition `json:"filters"`
+	}
+
+	BulkUserSelector struct {
+		IDs    []string        `json:"ids" binding:"omitempty,dive,uuid"`
+		Filter *BulkUserFilter `json:"filter"`
+	}
+
+	BulkUpdateUserFields struct {
+		Role        *string `json:"role,omitempty" binding:"omitnil,oneof=user admin"`
+		PhoneNumber *string `json:"phone_number,omitempty" binding:"omitempty,phone"`
+		Address     *string `json:"address,omitempty"`
+	}
+
+	BulkUpdateUserRequest struct {
+		BulkUserSelector
+		Fields      BulkUpdateUserFields `json:"fields"`
+		RequestedBy string               `json:"-"`
+	}
+
+	BulkDeleteUserRequest struct {
+		BulkUserSelector
+		RequestedBy string `json:"-"`
+	}
+
+	BulkUserItemResult struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	BulkUserResponse struct {
+		Committed bool                 `json:"committed"`
+		Total     int                  `json:"total"`
+		Succeeded int                  `json:"succeeded"`
+		Skipped   int                  `json:"skipped"`
+		Failed    int                  `json:"failed"`
+		Items     []BulkUserItemResult `json:"items"`
+	}
+
 	UserPaginationRequest struct {
 		PaginationRequest
-		UserID string `form:"id"`
+		UserID       string `form:"id"`
+		InactiveDays int    `form:"inactive_days" binding:"min=0"`
+
+		// CustomFields filters on custom field values, read from
+		// custom_fields[key]=value query parameters.
+		CustomFields map[string]string `form:"-"`
+
+		// Fields picks the attributes returned for each user and Expand the
+		// related resources embedded in them, both comma separated.
+		Fields string `form:"fields"`
+		Expand string `form:"expand"`
 	}
 
 	UserPaginationResponse struct {
@@ -67,8 +270,40 @@ type (
 		Data []UserResponse `json:"data"`
 	}
 
+	TrashedUserPaginationResponse struct {
+		PaginationResponse
+		Data []TrashedUserResponse `json:"data"`
+	}
+
 	UserPaginationRepositoryResponse struct {
 		PaginationResponse
 		Users []model.User
+
+		// Highlights holds the search matches of each user, by user ID
+		Highlights map[string]map[string]string
 	}
 )
+
+// IsEmpty reports whether the filter would select every user.
+func (f *BulkUserFilter) IsEmpty() bool {
+	return f.Search == "" && f.Role == "" && f.InactiveDays == 0 && len(f.CustomFields) == 0 && len(f.Filters) == 0
+}
+
+func (r *UserPaginationRequest) FieldList() []string {
+	return SplitList(r.Fields)
+}
+
+func (r *UserPaginationRequest) ExpandList() []string {
+	return SplitList(r.Expand)
+}
+
+// ResponseFields lists the members to keep in each user of the response, or
+// nil to keep them all. Expanded resources are kept even when fields= does
+// not name them.
+func (r *UserPaginationRequest) ResponseFields() []string {
+	fields := r.FieldList()
+	if len(fields) == 0 {
+		return nil
+	}
+	return append(fields, r.ExpandList()...)
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The v2 API serves users without the free-text address, which the
+// structured addresses resource replaces. Services keep returning the v1
+// types, and controllers map them for v2 requests.
+type (
+	UserResponseV2 struct {
+		ID          uuid.UUID `json:"id"`
+		Name        string    `json:"name"`
+		Email       string    `json:"email"`
+		PhoneNumber string    `json:"phone_number"`
+		Status      string    `json:"status"`
+
+		PendingEmail string `json:"pending_email,omitempty"`
+
+		LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+		LastLoginIP string     `json:"last_login_ip,omitempty"`
+		LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+
+		CustomFields map[string]any    `json:"custom_fields,omitempty"`
+		Highlights   map[string]string `json:"highlights,omitempty"`
+		Addresses    []AddressResponse `json:"addresses,omitempty"`
+	}
+
+	TrashedUserResponseV2 struct {
+		UserResponseV2
+		DeletedAt time.Time `json:"deleted_at"`
+	}
+
+	ProfileResponseV2 struct {
+		ID              uuid.UUID  `json:"id"`
+		Name            string     `json:"name"`
+		Email           string     `json:"email"`
+		PendingEmail    string     `json:"pending_email,omitempty"`
+		EmailVerified   bool       `json:"email_verified"`
+		EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+		PhoneNumber     string     `json:"phone_number"`
+		Role            string     `json:"role"`
+
+		Status         string     `json:"status"`
+		StatusReason   string     `json:"status_reason,omitempty"`
+		SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+
+		DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+
+		LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+
+		CustomFields map[string]any `json:"custom_fields,omitempty"`
+
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+func (u UserResponse) V2() UserResponseV2 {
+	return UserResponseV2{
+		ID:           u.ID,
+		Name:         u.Name,
+		Email:        u.Email,
+		PhoneNumber:  u.PhoneNumber,
+		Status:       u.Status,
+		PendingEmail: u.PendingEmail,
+		LastLoginAt:  u.LastLoginAt,
+		LastLoginIP:  u.LastLoginIP,
+		LastSeenAt:   u.LastSeenAt,
+		CustomFields: u.CustomFields,
+		Highlights:   u.Highlights,
+		Addresses:    u.Addresses,
+	}
+}
+
+func (u TrashedUserResponse) V2() TrashedUserResponseV2 {
+	return TrashedUserResponseV2{
+		UserResponseV2: u.UserResponse.V2(),
+		DeletedAt:      u.DeletedAt,
+	}
+}
+
+func (p ProfileResponse) V2() ProfileResponseV2 {
+	return ProfileResponseV2{
+		ID:                  p.ID,
+		Name:                p.Name,
+		Email:               p.Email,
+		PendingEmail:        p.PendingEmail,
+		EmailVerified:       p.EmailVerified,
+		EmailVerifiedAt:     p.EmailVerifiedAt,
+		PhoneNumber:         p.PhoneNumber,
+		Role:                p.Role,
+		Status:              p.Status,
+		StatusReason:        p.StatusReason,
+		SuspendedUntil:      p.SuspendedUntil,
+		DeletionScheduledAt: p.DeletionScheduledAt,
+		LastLoginAt:         p.LastLoginAt,
+		CustomFields:        p.CustomFields,
+		CreatedAt:           p.CreatedAt,
+		UpdatedAt:           p.UpdatedAt,
+	}
+}
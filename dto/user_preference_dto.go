@@ -0,0 +1,27 @@
+package dto
+
+type (
+	UserPreferences struct {
+		Locale        string                  `json:"locale"`
+		Timezone      string                  `json:"timezone"`
+		Theme         string                  `json:"theme"`
+		Notifications NotificationPreferences `json:"notifications"`
+	}
+
+	NotificationPreferences struct {
+		Email          bool `json:"email"`
+		SecurityAlerts bool `json:"security_alerts"`
+		Marketing      bool `json:"marketing"`
+	}
+
+	GetUserPreferencesRequest struct {
+		UserID string `json:"-"`
+	}
+
+	// UpdateUserPreferencesRequest carries a JSON Merge Patch: members that
+	// are left out stay as they are and null resets a member to its default.
+	UpdateUserPreferencesRequest struct {
+		UserID string         `json:"-"`
+		Patch  map[string]any `json:"-"`
+	}
+)
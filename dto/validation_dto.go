@@ -0,0 +1,10 @@
+package dto
+
+// FieldError is one field of a request that failed validation. Field is the
+// name the client sent it under, a dotted path for nested members, and Code
+// the rule it broke, such as required, min or email.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,27 @@
+package helpers
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// GetAPIVersionSchedule returns when an API version was deprecated and when
+// it will be removed, read from API_<VERSION>_DEPRECATED_AT and
+// API_<VERSION>_SUNSET_AT as dates such as 2026-12-31 or RFC 3339 times.
+// Unset or unreadable values come back as the zero time.
+func GetAPIVersionSchedule(version string) (deprecatedAt, sunsetAt time.Time) {
+	prefix := "API_" + strings.ToUpper(version) + "_"
+	return parseScheduleTime(os.Getenv(prefix + "DEPRECATED_AT")), parseScheduleTime(os.Getenv(prefix + "SUNSET_AT"))
+}
+
+func parseScheduleTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
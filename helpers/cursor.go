@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+)
+
+// Cursor marks a position in a sorted list: the sort it was issued for and
+// the sort values of the row it points at. Before asks for the rows ahead of
+// that row instead of after it.
+type Cursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+	Before bool   `json:"b,omitempty"`
+}
+
+var cursorKey []byte
+
+// SetUpCursorKey loads the key cursors are signed with. It is CURSOR_SECRET,
+// or else a key derived from JWT_SECRET, so a leaked cursor key cannot sign
+// tokens. It fails when neither is set.
+func SetUpCursorKey() error {
+	if secret := os.Getenv("CURSOR_SECRET"); secret != "" {
+		cursorKey = []byte(secret)
+		return nil
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return errors.New("CURSOR_SECRET or JWT_SECRET must be set to sign pagination cursors")
+	}
+
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, "pagination cursor", sha256.Size)
+	if err != nil {
+		return err
+	}
+
+	cursorKey = key
+	return nil
+}
+
+func signCursor(payload string) string {
+	mac := hmac.New(sha256.New, cursorKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// EncodeCursor returns the cursor as an opaque token signed with
+// CURSOR_SECRET, so clients cannot craft positions of their own.
+func EncodeCursor(cursor Cursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signCursor(payload), nil
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signCursor(payload))) {
+		return Cursor{}, constants.ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Cursor{}, constants.ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return Cursor{}, constants.ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
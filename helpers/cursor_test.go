@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetUpCursorKey(t *testing.T) {
+	t.Cleanup(func() { cursorKey = nil })
+
+	t.Setenv("CURSOR_SECRET", "")
+	t.Setenv("JWT_SECRET", "")
+	if err := SetUpCursorKey(); err == nil {
+		t.Fatalf("expected an error when no secret is set")
+	}
+
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	if err := SetUpCursorKey(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cursorKey) == 0 || bytes.Equal(cursorKey, []byte("jwt-secret")) {
+		t.Fatalf("expected a key derived from JWT_SECRET, got %q", cursorKey)
+	}
+
+	t.Setenv("CURSOR_SECRET", "cursor-secret")
+	if err := SetUpCursorKey(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(cursorKey, []byte("cursor-secret")) {
+		t.Fatalf("expected CURSOR_SECRET to be used, got %q", cursorKey)
+	}
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"encoding/json"
+)
+
+// PickFields keeps only the listed top-level JSON members of data, which is
+// an object or a list of objects. With no fields, data is returned as is.
+func PickFields(data any, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	pick := func(object map[string]json.RawMessage) {
+		for member := range object {
+			if !keep[member] {
+				delete(object, member)
+			}
+		}
+	}
+
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &objects); err == nil {
+		for _, object := range objects {
+			pick(object)
+		}
+		return objects, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	pick(object)
+	return object, nil
+}
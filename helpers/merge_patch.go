@@ -0,0 +1,29 @@
+package helpers
+
+// MergePatch applies patch to target following JSON Merge Patch (RFC 7386):
+// objects are merged recursively, null removes a member and any other value
+// replaces it. target is not modified.
+func MergePatch(target, patch map[string]any) map[string]any {
+	merged := make(map[string]any, len(target)+len(patch))
+	for key, value := range target {
+		merged[key] = value
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+
+		patchObject, ok := value.(map[string]any)
+		if !ok {
+			merged[key] = value
+			continue
+		}
+
+		targetObject, _ := merged[key].(map[string]any)
+		merged[key] = MergePatch(targetObject, patchObject)
+	}
+
+	return merged
+}
@@ -0,0 +1,18 @@
+package helpers
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+)
+
+// GetPaginationMaxPerPage returns the largest page a list serves. Larger
+// per_page and limit values are lowered to it.
+func GetPaginationMaxPerPage() int {
+	maxPerPage, err := strconv.Atoi(os.Getenv("PAGINATION_MAX_PER_PAGE"))
+	if err != nil || maxPerPage <= 0 {
+		return constants.ENUM_PAGINATION_MAX_PER_PAGE
+	}
+	return maxPerPage
+}
@@ -0,0 +1,50 @@
+package helpers
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// GetPhoneDefaultRegion returns the region used for numbers written without
+// a country code, as an ISO 3166-1 alpha-2 code.
+func GetPhoneDefaultRegion() string {
+	region := strings.ToUpper(strings.TrimSpace(os.Getenv("PHONE_DEFAULT_REGION")))
+	if region == "" {
+		region = constants.ENUM_PHONE_DEFAULT_REGION
+	}
+	return region
+}
+
+// GetPhoneNumberUnique reports whether two users may not share a phone
+// number.
+func GetPhoneNumberUnique() bool {
+	unique, _ := strconv.ParseBool(os.Getenv("PHONE_NUMBER_UNIQUE"))
+	return unique
+}
+
+// NormalizePhoneNumber parses phone in any common format and returns it in
+// E.164 form (+6281234567890). An empty phone stays empty.
+func NormalizePhoneNumber(phone, region string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", nil
+	}
+
+	number, err := phonenumbers.Parse(phone, region)
+	if err != nil || !phonenumbers.IsValidNumber(number) {
+		return "", constants.ErrInvalidPhoneNumber
+	}
+
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
+
+// IsValidCountryCode reports whether code is an ISO 3166-1 alpha-2 country
+// code. The phone number metadata already lists every country, so it is
+// reused here instead of keeping a second list.
+func IsValidCountryCode(code string) bool {
+	return len(code) == 2 && phonenumbers.GetSupportedRegions()[code]
+}
@@ -0,0 +1,281 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+)
+
+// TabularWriter streams rows of string cells in a file format. WriteHeader
+// must be called once before any WriteRow, and Close must always be called
+// to finish the file.
+type TabularWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []string) error
+	Flush() error
+	Close() error
+}
+
+type flusher interface {
+	Flush()
+}
+
+func flushUnderlying(w io.Writer) {
+	if f, ok := w.(flusher); ok {
+		f.Flush()
+	}
+}
+
+// TabularContentType returns the content type and file extension to use
+// when serving a file written in format.
+func TabularContentType(format string) (string, string, error) {
+	switch format {
+	case constants.ENUM_EXPORT_FORMAT_CSV:
+		return "text/csv; charset=utf-8", "csv", nil
+	case constants.ENUM_EXPORT_FORMAT_NDJSON:
+		return "application/x-ndjson", "ndjson", nil
+	case constants.ENUM_EXPORT_FORMAT_XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx", nil
+	default:
+		return "", "", fmt.Errorf("%w: %q", constants.ErrInvalidExportFormat, format)
+	}
+}
+
+// NewTabularWriter returns the writer for format. The XLSX writer starts
+// writing the workbook immediately, so response headers must be set first.
+func NewTabularWriter(format string, w io.Writer) (TabularWriter, error) {
+	switch format {
+	case constants.ENUM_EXPORT_FORMAT_CSV:
+		return newCSVWriter(w), nil
+	case constants.ENUM_EXPORT_FORMAT_NDJSON:
+		return newNDJSONWriter(w), nil
+	case constants.ENUM_EXPORT_FORMAT_XLSX:
+		return newXLSXWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", constants.ErrInvalidExportFormat, format)
+	}
+}
+
+type csvWriter struct {
+	out io.Writer
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{out: w, csv: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) WriteHeader(columns []string) error {
+	return cw.csv.Write(columns)
+}
+
+// signedNumber matches a plain signed number, which covers E.164 phone
+// numbers; a spreadsheet reads it as a value, not a formula.
+var signedNumber = regexp.MustCompile(`^[+-]\d+(\.\d+)?$`)
+
+// WriteRow neutralizes values that a spreadsheet would run as a formula.
+func (cw *csvWriter) WriteRow(values []string) error {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		if isFormula(value) {
+			value = "'" + value
+		}
+		escaped[i] = value
+	}
+	return cw.csv.Write(escaped)
+}
+
+func isFormula(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return true
+	case '+', '-':
+		return !signedNumber.MatchString(value)
+	}
+	return false
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.csv.Flush()
+	flushUnderlying(cw.out)
+	return cw.csv.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	return cw.Flush()
+}
+
+type ndjsonWriter struct {
+	out     io.Writer
+	buf     *bufio.Writer
+	columns []string
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{out: w, buf: bufio.NewWriter(w)}
+}
+
+func (nw *ndjsonWriter) WriteHeader(columns []string) error {
+	nw.columns = columns
+	return nil
+}
+
+func (nw *ndjsonWriter) WriteRow(values []string) error {
+	row := make(map[string]string, len(nw.columns))
+	for i, column := range nw.columns {
+		if i < len(values) {
+			row[column] = values[i]
+		}
+	}
+
+	line, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+
+	if _, err := nw.buf.Write(line); err != nil {
+		return err
+	}
+	return nw.buf.WriteByte('\n')
+}
+
+func (nw *ndjsonWriter) Flush() error {
+	if err := nw.buf.Flush(); err != nil {
+		return err
+	}
+	flushUnderlying(nw.out)
+	return nil
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nw.Flush()
+}
+
+// xlsxWriter writes a single-sheet workbook using inline strings, so rows
+// can go straight into the zip stream without a shared string table.
+type xlsxWriter struct {
+	out     io.Writer
+	archive *zip.Writer
+	sheet   io.Writer
+	row     int
+	err     error
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	xw := &xlsxWriter{out: w, archive: zip.NewWriter(w)}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		if xw.err = xw.writePart(part.name, part.content); xw.err != nil {
+			return xw
+		}
+	}
+
+	xw.sheet, xw.err = xw.archive.Create("xl/worksheets/sheet1.xml")
+	if xw.err == nil {
+		_, xw.err = io.WriteString(xw.sheet, xlsxSheetStart)
+	}
+	return xw
+}
+
+func (xw *xlsxWriter) writePart(name, content string) error {
+	part, err := xw.archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+func (xw *xlsxWriter) WriteHeader(columns []string) error {
+	return xw.WriteRow(columns)
+}
+
+func (xw *xlsxWriter) WriteRow(values []string) error {
+	if xw.err != nil {
+		return xw.err
+	}
+
+	xw.row++
+	var b strings.Builder
+	b.WriteString(`<row r="` + strconv.Itoa(xw.row) + `">`)
+	for i, value := range values {
+		b.WriteString(`<c r="` + xlsxColumnName(i) + strconv.Itoa(xw.row) + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(&b, []byte(value)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+
+	_, xw.err = io.WriteString(xw.sheet, b.String())
+	return xw.err
+}
+
+func (xw *xlsxWriter) Flush() error {
+	if xw.err != nil {
+		return xw.err
+	}
+	if err := xw.archive.Flush(); err != nil {
+		return err
+	}
+	flushUnderlying(xw.out)
+	return nil
+}
+
+func (xw *xlsxWriter) Close() error {
+	if xw.err != nil {
+		return xw.err
+	}
+	if _, err := io.WriteString(xw.sheet, xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := xw.archive.Close(); err != nil {
+		return err
+	}
+	flushUnderlying(xw.out)
+	return nil
+}
+
+// xlsxColumnName turns a zero-based index into a spreadsheet column name
+// (0 -> A, 25 -> Z, 26 -> AA).
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
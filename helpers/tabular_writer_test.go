@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCSVWriter_WriteRow_NeutralizesFormulas(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{"@cmd", "'@cmd"},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"+6281234567890", "+6281234567890"},
+		{"-12.5", "-12.5"},
+		{"Some User", "Some User"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		cw := newCSVWriter(&buf)
+		if err := cw.WriteRow([]string{tt.value}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := cw.Flush(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := bytes.TrimSpace(buf.Bytes()); string(got) != tt.want && string(got) != `"`+tt.want+`"` {
+			t.Errorf("%q: expected %q, got %q", tt.value, tt.want, got)
+		}
+	}
+}
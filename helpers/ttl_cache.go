@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"sync"
+	"time"
+)
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTLCache keeps values for a fixed time and holds at most size of them.
+// When it is full, expired values are dropped first and then an arbitrary
+// one, so memory use stays bounded however many keys pass through it.
+type TTLCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[K]ttlEntry[V]
+}
+
+func NewTTLCache[K comparable, V any](ttl time.Duration, size int) *TTLCache[K, V] {
+	return &TTLCache[K, V]{ttl: ttl, size: size, entries: make(map[K]ttlEntry[V], size)}
+}
+
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.size {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = ttlEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *TTLCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLCache_ExpiresValues(t *testing.T) {
+	cache := NewTTLCache[string, int](time.Millisecond, 10)
+	cache.Set("a", 1)
+
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a fresh value, got %d %v", v, ok)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("expected the value to expire")
+	}
+}
+
+func TestTTLCache_StaysWithinSize(t *testing.T) {
+	cache := NewTTLCache[int, int](time.Minute, 3)
+	for i := range 10 {
+		cache.Set(i, i)
+	}
+
+	if len(cache.entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(cache.entries))
+	}
+	if v, ok := cache.Get(9); !ok || v != 9 {
+		t.Fatalf("expected the latest value to be kept, got %d %v", v, ok)
+	}
+}
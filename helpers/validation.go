@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// EmbeddedField names an embedded struct in a validator namespace. JSON
+// flattens embedded structs away, so field paths skip it.
+const EmbeddedField = "<embedded>"
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// IsValidCustomFieldKey reports whether key can name a custom field: a
+// lowercase letter followed by up to 62 lowercase letters, digits and
+// underscores.
+func IsValidCustomFieldKey(key string) bool {
+	return customFieldKeyPattern.MatchString(key)
+}
+
+var setUpValidator sync.Once
+
+// SetUpValidator teaches the validator gin runs at bind time the rules of
+// this API, so binding tags can use email, phone, country_code, regexp and
+// custom_field_key, and names failing fields the way clients send them.
+func SetUpValidator() {
+	setUpValidator.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			if field.Anonymous {
+				return EmbeddedField
+			}
+			return ""
+		})
+
+		// email replaces the validator's own rule, which accepts addresses
+		// without a top-level domain that the services then reject.
+		v.RegisterValidation("email", func(fl validator.FieldLevel) bool {
+			return IsValidEmail(fl.Field().String())
+		})
+		v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+			_, err := NormalizePhoneNumber(fl.Field().String(), GetPhoneDefaultRegion())
+			return err == nil
+		})
+		v.RegisterValidation("country_code", func(fl validator.FieldLevel) bool {
+			return IsValidCountryCode(strings.ToUpper(strings.TrimSpace(fl.Field().String())))
+		})
+		v.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
+			_, err := regexp.Compile(fl.Field().String())
+			return err == nil
+		})
+		v.RegisterValidation("custom_field_key", func(fl validator.FieldLevel) bool {
+			return IsValidCustomFieldKey(fl.Field().String())
+		})
+	})
+}
@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+)
+
+// StartAccountErasureJob periodically anonymizes accounts whose deletion
+// grace period has expired until ctx is cancelled.
+func StartAccountErasureJob(ctx context.Context, userService service.IUserService) {
+	interval := getInterval("ACCOUNT_ERASURE_INTERVAL_MINUTE", constants.ENUM_ACCOUNT_ERASURE_INTERVAL_MINUTE)
+
+	runPeriodically(ctx, "account erasure", interval, func(ctx context.Context) {
+		erased, err := userService.EraseDueAccounts(ctx)
+		if err != nil {
+			logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_ERASE_ACCOUNT)
+		} else if erased > 0 {
+			logging.Log.Infof(constants.MESSAGE_SUCCESS_ERASE_ACCOUNT+": %d account(s)", erased)
+		}
+	})
+}
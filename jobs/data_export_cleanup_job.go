@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/service"
+)
+
+// StartDataExportCleanupJob periodically deletes expired data export
+// archives and fails exports whose build was lost until ctx is cancelled.
+// It also runs on startup, which catches builds interrupted by a restart.
+func StartDataExportCleanupJob(ctx context.Context, dataExportService service.IDataExportService) {
+	interval := getInterval("DATA_EXPORT_CLEANUP_INTERVAL_MINUTE", constants.ENUM_DATA_EXPORT_CLEANUP_INTERVAL_MINUTE)
+
+	runPeriodically(ctx, "data export cleanup", interval, func(ctx context.Context) {
+		deleted, err := dataExportService.CleanUpDataExports(ctx)
+		if err != nil {
+			logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CLEAN_UP_DATA_EXPORT)
+		} else if deleted > 0 {
+			logging.Log.Infof(constants.MESSAGE_SUCCESS_CLEAN_UP_DATA_EXPORT+": %d export(s)", deleted)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/logging"
+)
+
+// getInterval reads a job interval in minutes from the environment.
+func getInterval(env string, fallbackMinutes int) time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv(env))
+	if err != nil || minutes <= 0 {
+		minutes = fallbackMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// runPeriodically calls run right away and then every interval until ctx is
+// cancelled.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, run func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+
+	logging.Log.Infof("%s job started, interval %s", name, interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			run(ctx)
+
+			select {
+			case <-ctx.Done():
+				logging.Log.Infof("%s job stopped", name)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
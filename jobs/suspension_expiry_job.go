@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/service"
+)
+
+// StartSuspensionExpiryJob periodically reactivates users whose suspension
+// has ended. Login and authentication also reactivate them on the spot, this
+// keeps the status shown to admins up to date.
+func StartSuspensionExpiryJob(ctx context.Context, userService service.IUserService) {
+	interval := getInterval("SUSPENSION_EXPIRY_INTERVAL_MINUTE", constants.ENUM_SUSPENSION_EXPIRY_INTERVAL_MINUTE)
+
+	runPeriodically(ctx, "suspension expiry", interval, func(ctx context.Context) {
+		_, _ = userService.ReactivateExpiredSuspensions(ctx)
+	})
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -9,6 +10,8 @@ import (
 	"github.com/mferdian/golang_boiller_plate/command"
 	"github.com/mferdian/golang_boiller_plate/config/database"
 	"github.com/mferdian/golang_boiller_plate/controller"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/jobs"
 	"github.com/mferdian/golang_boiller_plate/logging"
 	"github.com/mferdian/golang_boiller_plate/middleware"
 	"github.com/mferdian/golang_boiller_plate/repository"
@@ -21,6 +24,9 @@ func main() {
 	logging.SetUpLogger()
 	logging.Log.Info("Logger initialized")
 
+	// ==== Set up request validation ====
+	helpers.SetUpValidator()
+
 	// Load .env
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found")
@@ -36,20 +42,59 @@ func main() {
 		return
 	}
 
+	// Cursor signing key
+	if err := helpers.SetUpCursorKey(); err != nil {
+		logging.Log.Fatal(err)
+	}
+
 	var (
-		jwtService = service.NewJWTService()
+		jwtService  = service.NewJWTService()
+		mailService = service.NewMailService()
+
+		customFieldRepo       = repository.NewCustomFieldRepository(db)
+		customFieldService    = service.NewCustomFieldService(customFieldRepo)
+		customFieldController = controller.NewCustomFieldController(customFieldService)
 
 		userRepo       = repository.NewUserRepository(db)
-		userService    = service.NewUserService(userRepo, jwtService)
+		userService    = service.NewUserService(userRepo, customFieldRepo, jwtService, mailService)
 		userController = controller.NewUserController(userService)
+
+		addressRepo       = repository.NewAddressRepository(db)
+		addressService    = service.NewAddressService(userRepo, addressRepo)
+		addressController = controller.NewAddressController(addressService)
+
+		preferenceRepo       = repository.NewUserPreferenceRepository(db)
+		preferenceService    = service.NewUserPreferenceService(userRepo, preferenceRepo)
+		preferenceController = controller.NewUserPreferenceController(preferenceService)
+
+		invitationRepo       = repository.NewInvitationRepository(db)
+		invitationService    = service.NewInvitationService(userRepo, invitationRepo, mailService)
+		invitationController = controller.NewInvitationController(invitationService)
+
+		dataExportRepo       = repository.NewDataExportRepository(db)
+		dataExportService    = service.NewDataExportService(userRepo, dataExportRepo, addressRepo, preferenceService, customFieldRepo)
+		dataExportController = controller.NewDataExportController(dataExportService)
+
+		docsController = controller.NewDocsController()
 	)
 
+	// Background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.StartAccountErasureJob(jobCtx, userService)
+	jobs.StartSuspensionExpiryJob(jobCtx, userService)
+	jobs.StartDataExportCleanupJob(jobCtx, dataExportService)
+
 	server := gin.Default()
 	server.Use(middleware.CORSMiddleware())
+	server.Use(middleware.ErrorHandler())
 
-	routes.PublicRoutes(server, userController)
-	routes.AdminRoutes(server, userController, jwtService)
-	routes.UserRoutes(server, userController, jwtService)
+	routes.APIRoutes(server, func(api *gin.RouterGroup) {
+		routes.PublicRoutes(api, userController, invitationController)
+		routes.AdminRoutes(api, userController, invitationController, customFieldController, jwtService, userService)
+		routes.UserRoutes(api, userController, dataExportController, addressController, preferenceController, jwtService, userService)
+	})
+	routes.DocsRoutes(server, docsController)
 
 	server.Static("/assets", "./assets")
 
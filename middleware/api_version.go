@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+// APIVersion pins the routes of a versioned group, such as /api/v2, to that
+// version.
+func APIVersion(version string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		setAPIVersion(ctx, version)
+		ctx.Next()
+	}
+}
+
+// NegotiateAPIVersion serves the unversioned /api routes in the version
+// asked for by a version parameter on the Accept header, such as
+// "application/json; version=2", and in the default version otherwise.
+func NegotiateAPIVersion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Vary", "Accept")
+
+		version, err := acceptedAPIVersion(ctx.GetHeader("Accept"))
+		if err != nil {
+			logging.Log.WithError(err).Warn("API version not acceptable")
+			res := utils.BuildResponseFailed(constants.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
+			ctx.AbortWithStatusJSON(http.StatusNotAcceptable, res)
+			return
+		}
+
+		setAPIVersion(ctx, version)
+		ctx.Next()
+	}
+}
+
+func acceptedAPIVersion(accept string) (string, error) {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		requested, ok := params["version"]
+		if !ok {
+			continue
+		}
+
+		version := "v" + strings.TrimPrefix(strings.ToLower(requested), "v")
+		switch version {
+		case constants.ENUM_API_VERSION_1, constants.ENUM_API_VERSION_2:
+			return version, nil
+		}
+		return "", fmt.Errorf("%w: %q", constants.ErrUnsupportedAPIVersion, requested)
+	}
+
+	return constants.ENUM_API_VERSION_DEFAULT, nil
+}
+
+// setAPIVersion records the version for the controllers and announces it,
+// with a Deprecation and Sunset header when the version is scheduled for
+// removal.
+func setAPIVersion(ctx *gin.Context, version string) {
+	ctx.Set("api_version", version)
+	ctx.Header("API-Version", version)
+
+	deprecatedAt, sunsetAt := helpers.GetAPIVersionSchedule(version)
+	if !deprecatedAt.IsZero() {
+		ctx.Header("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+	}
+	if !sunsetAt.IsZero() {
+		ctx.Header("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/mferdian/golang_boiller_plate/utils"
 )
 
-func Authentication(jwtService service.InterfaceJWTService) gin.HandlerFunc {
+func Authentication(jwtService service.InterfaceJWTService, userService service.IUserService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
@@ -38,10 +38,19 @@ func Authentication(jwtService service.InterfaceJWTService) gin.HandlerFunc {
 			return
 		}
 
+		if err := userService.CheckUserStatus(ctx.Request.Context(), claims.UserID); err != nil {
+			logging.Log.Warnf("Rejected token for inactive account %s: %v", claims.UserID, err)
+			ctx.Error(err).SetMeta(constants.MESSAGE_FAILED_ACCOUNT_INACTIVE)
+			ctx.Abort()
+			return
+		}
+
+		userService.TouchLastSeen(ctx.Request.Context(), claims.UserID)
+
 		logging.Log.Infof("Authenticated request - UserID: %s, Role: %s", claims.UserID, claims.Role)
 
 		ctx.Set("Authorization", tokenStr)
-		ctx.Set("id", claims.ID)
+		ctx.Set("id", claims.UserID)
 		ctx.Set("role", claims.Role)
 
 		ctx.Next()
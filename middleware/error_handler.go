@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+var errorKindStatus = map[constants.ErrorKind]int{
+	constants.ENUM_ERROR_KIND_VALIDATION:   http.StatusUnprocessableEntity,
+	constants.ENUM_ERROR_KIND_NOT_FOUND:    http.StatusNotFound,
+	constants.ENUM_ERROR_KIND_CONFLICT:     http.StatusConflict,
+	constants.ENUM_ERROR_KIND_UNAUTHORIZED: http.StatusUnauthorized,
+	constants.ENUM_ERROR_KIND_FORBIDDEN:    http.StatusForbidden,
+	constants.ENUM_ERROR_KIND_GONE:         http.StatusGone,
+	constants.ENUM_ERROR_KIND_INTERNAL:     http.StatusInternalServerError,
+}
+
+// errorStatus is the HTTP status a request fails with because of err.
+func errorStatus(err error) int {
+	if status, ok := errorKindStatus[constants.ErrorOf(err).Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorHandler answers requests whose handler stopped at an error added with
+// ctx.Error, using the error's meta as the message. The status follows the
+// error's kind and the body carries its code. Internal errors show only
+// their safe message, since the cause may be a database or driver error.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		last := ctx.Errors.Last()
+		message, ok := last.Meta.(string)
+		if !ok {
+			message = constants.MESSAGE_FAILED_PROSES_REQUEST
+		}
+
+		appErr := constants.ErrorOf(last.Err)
+		detail := last.Err.Error()
+
+		entry := logging.Log.WithError(last.Err).WithField("code", appErr.Code)
+		if appErr.Kind == constants.ENUM_ERROR_KIND_INTERNAL {
+			entry.Error(message)
+			detail = appErr.Message
+		} else {
+			entry.Warn(message)
+		}
+
+		res := utils.BuildResponseFailed(message, detail, nil)
+		res.Code = appErr.Code
+		ctx.JSON(errorStatus(last.Err), res)
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/utils"
+)
+
+func serveError(err error) (int, utils.Response) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/", func(ctx *gin.Context) {
+		ctx.Error(err).SetMeta(constants.MESSAGE_FAILED_GET_DETAIL_USER)
+		ctx.Abort()
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var res utils.Response
+	json.Unmarshal(w.Body.Bytes(), &res)
+	return w.Code, res
+}
+
+func TestErrorHandler_StatusFollowsKind(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{constants.ErrInvalidUUID, http.StatusUnprocessableEntity, "invalid_uuid"},
+		{constants.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+		{constants.ErrEmailAlreadyExists, http.StatusConflict, "email_already_exists"},
+		{constants.ErrInvalidLoginCredential, http.StatusUnauthorized, "invalid_login_credential"},
+		{constants.ErrAccountBanned, http.StatusForbidden, "account_banned"},
+		{constants.ErrDataExportExpired, http.StatusGone, "data_export_expired"},
+		{constants.ErrGetUserByID, http.StatusInternalServerError, "get_user_by_id"},
+	}
+
+	for _, tt := range tests {
+		status, res := serveError(tt.err)
+		if status != tt.status || res.Code != tt.code {
+			t.Errorf("%v: expected %d %s, got %d %s", tt.err, tt.status, tt.code, status, res.Code)
+		}
+		if res.Messsage != constants.MESSAGE_FAILED_GET_DETAIL_USER {
+			t.Errorf("%v: unexpected message %q", tt.err, res.Messsage)
+		}
+	}
+}
+
+func TestErrorHandler_KeepsDetailOfWrappedError(t *testing.T) {
+	status, res := serveError(fmt.Errorf("%w: %q", constants.ErrInvalidExportFormat, "pdf"))
+	if status != http.StatusUnprocessableEntity || res.Code != "invalid_export_format" {
+		t.Fatalf("expected 422 invalid_export_format, got %d %s", status, res.Code)
+	}
+	if res.Error != `invalid export format, use csv, ndjson or xlsx: "pdf"` {
+		t.Fatalf("unexpected error: %v", res.Error)
+	}
+}
+
+func TestErrorHandler_HidesCauseOfInternalError(t *testing.T) {
+	status, res := serveError(errors.New(`pq: relation "users" does not exist`))
+	if status != http.StatusInternalServerError || res.Code != constants.ErrInternal.Code {
+		t.Fatalf("expected 500 %s, got %d %s", constants.ErrInternal.Code, status, res.Code)
+	}
+	if res.Error != constants.ErrInternal.Message {
+		t.Fatalf("expected the safe message, got %v", res.Error)
+	}
+
+	_, res = serveError(fmt.Errorf("%w: connection refused", constants.ErrGetUserByID))
+	if res.Error != constants.ErrGetUserByID.Message {
+		t.Fatalf("expected the safe message, got %v", res.Error)
+	}
+}
+
+func TestErrorHandler_LeavesWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/", func(ctx *gin.Context) {
+		ctx.Error(constants.ErrInternal)
+		ctx.JSON(http.StatusOK, utils.BuildResponseSuccess(constants.MESSAGE_SUCCESS_GET_DETAIL_USER, nil))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	var res utils.Response
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if w.Code != http.StatusOK || !res.Status {
+		t.Fatalf("expected the handler's response to stand, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+// legacyEmailConstraints are the names the unique constraint on users.email
+// had before it became the idx_users_email_lower_active index: GORM's own,
+// PostgreSQL's default for a column declared UNIQUE, and the partial index
+// that still told addresses apart by case.
+var legacyEmailConstraints = []string{"uni_users_email", "users_email_key", "idx_users_email_active"}
+
+// DropLegacyEmailUnique drops the old unique constraints on users.email.
+// The first ones also covered trashed rows, so their addresses could not be
+// registered again, and AutoMigrate never drops an index by itself.
+func DropLegacyEmailUnique(db *gorm.DB) error {
+	migrator := db.Migrator()
+
+	for _, name := range legacyEmailConstraints {
+		if migrator.HasConstraint(&model.User{}, name) {
+			if err := migrator.DropConstraint(&model.User{}, name); err != nil {
+				return err
+			}
+		}
+
+		if migrator.HasIndex(&model.User{}, name) {
+			if err := migrator.DropIndex(&model.User{}, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
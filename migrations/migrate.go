@@ -8,9 +8,35 @@ import (
 func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(
 		&model.User{},
+		&model.DataExport{},
+		&model.Address{},
+		&model.UserPreference{},
+		&model.Invitation{},
+		&model.CustomField{},
+		&model.UserCustomFieldValue{},
 	); err != nil {
 		return err
 	}
 
+	if err := DropLegacyEmailUnique(db); err != nil {
+		return err
+	}
+
+	if err := CreateUserSearchIndexes(db); err != nil {
+		return err
+	}
+
+	if err := NormalizePhoneNumbers(db); err != nil {
+		return err
+	}
+
+	if err := CreateUserUniqueIndexes(db); err != nil {
+		return err
+	}
+
+	if err := MoveLegacyAddresses(db); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+// legacyAddressesQuery finds the users whose free-text address has not been
+// moved to a structured one.
+func legacyAddressesQuery(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&model.User{}).
+		Where("address <> ''").
+		Where("NOT EXISTS (SELECT 1 FROM addresses WHERE addresses.user_id = users.id)")
+}
+
+// splitLegacyAddress reads the "street, city" form the free-text address
+// was written in. Anything else cannot be split reliably and is left alone.
+func splitLegacyAddress(text string) (street, city string, ok bool) {
+	i := strings.LastIndex(text, ",")
+	if i < 0 {
+		return "", "", false
+	}
+
+	street, city = strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+	return street, city, street != "" && city != ""
+}
+
+// MoveLegacyAddresses copies the free-text users.address of every user
+// without a structured address into a default home address, once. The old
+// column is kept for clients that still read it, so it runs through runOnce
+// to leave addresses written later alone.
+//
+// The text carries no country, so LEGACY_ADDRESS_COUNTRY must name it; until
+// it does the move waits for the next Migrate. Text that does not split into
+// a street and a city stays in users.address only.
+func MoveLegacyAddresses(db *gorm.DB) error {
+	return runOnce(db, "move_legacy_addresses", func(tx *gorm.DB) error {
+		var pending int64
+		if err := legacyAddressesQuery(tx).Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending == 0 {
+			return nil
+		}
+
+		country := strings.ToUpper(strings.TrimSpace(os.Getenv("LEGACY_ADDRESS_COUNTRY")))
+		if !helpers.IsValidCountryCode(country) {
+			logging.Log.Warnf("%d legacy addresses not moved, set LEGACY_ADDRESS_COUNTRY to their country code", pending)
+			return errMigrationPending
+		}
+
+		var users []model.User
+		return legacyAddressesQuery(tx).Select("id", "address").
+			FindInBatches(&users, 500, func(_ *gorm.DB, batch int) error {
+				addresses := make([]model.Address, 0, len(users))
+				for _, user := range users {
+					street, city, ok := splitLegacyAddress(user.Address)
+					if !ok {
+						logging.Log.Warnf("skip legacy address of user %s, no street and city in %q", user.ID, user.Address)
+						continue
+					}
+
+					addresses = append(addresses, model.Address{
+						ID:        uuid.New(),
+						UserID:    user.ID,
+						Type:      constants.ENUM_ADDRESS_TYPE_HOME,
+						IsDefault: true,
+						Street:    street,
+						City:      city,
+						Country:   country,
+					})
+				}
+
+				if len(addresses) == 0 {
+					return nil
+				}
+
+				if err := tx.Create(&addresses).Error; err != nil {
+					return err
+				}
+
+				logging.Log.Infof("moved %d legacy addresses", len(addresses))
+				return nil
+			}).Error
+	})
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+// NormalizePhoneNumbers rewrites stored phone numbers to E.164. Numbers that
+// cannot be parsed are left untouched and reported, so they can be fixed by
+// hand.
+func NormalizePhoneNumbers(db *gorm.DB) error {
+	region := helpers.GetPhoneDefaultRegion()
+
+	var users []model.User
+	return db.Unscoped().Select("id", "phone_number").
+		Where("phone_number <> ''").
+		FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				normalized, err := helpers.NormalizePhoneNumber(user.PhoneNumber, region)
+				if err != nil {
+					logging.Log.Warnf("skip invalid phone number for user %s: %q", user.ID, user.PhoneNumber)
+					continue
+				}
+
+				if normalized == user.PhoneNumber {
+					continue
+				}
+
+				if err := db.Unscoped().Model(&model.User{}).Where("id = ?", user.ID).
+					Update("phone_number", normalized).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -6,8 +6,19 @@ import (
 )
 
 func Rollback(db *gorm.DB) error {
+	if err := DropUserSearchIndexes(db); err != nil {
+		return err
+	}
+
 	tables := []interface{}{
+		&model.UserCustomFieldValue{},
+		&model.CustomField{},
+		&model.Invitation{},
+		&model.UserPreference{},
+		&model.Address{},
+		&model.DataExport{},
 		&model.User{},
+		&migrationMarker{},
 	}
 
 	for _, table := range tables {
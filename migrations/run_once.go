@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationMarker records a data migration that has run, so Migrate does
+// not apply it again to rows the application wrote since.
+type migrationMarker struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+// errMigrationPending tells runOnce that a data migration could not run yet,
+// usually for lack of configuration, and should be tried on the next Migrate.
+var errMigrationPending = errors.New("migration pending")
+
+// runOnce applies migrate in a transaction unless it has been recorded
+// under name, and records it afterwards.
+func runOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	if err := db.AutoMigrate(&migrationMarker{}); err != nil {
+		return err
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var applied int64
+		if err := tx.Model(&migrationMarker{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			return nil
+		}
+
+		if err := migrate(tx); err != nil {
+			return err
+		}
+
+		return tx.Create(&migrationMarker{Name: name, AppliedAt: time.Now()}).Error
+	})
+	if errors.Is(err, errMigrationPending) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"gorm.io/gorm"
+)
+
+var postgresUserSearchStatements = []string{
+	"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+	"CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, '')))",
+	"CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING GIN (name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (email gin_trgm_ops)",
+}
+
+// users_fts keeps its own copy of name and email, synced by triggers, since
+// users has no integer key for an external content table.
+var sqliteUserSearchStatements = []string{
+	"CREATE VIRTUAL TABLE IF NOT EXISTS users_fts USING fts5(user_id UNINDEXED, name, email)",
+	`CREATE TRIGGER IF NOT EXISTS users_fts_insert AFTER INSERT ON users BEGIN
+		INSERT INTO users_fts (user_id, name, email) VALUES (new.id, new.name, new.email);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS users_fts_update AFTER UPDATE OF name, email ON users BEGIN
+		UPDATE users_fts SET name = new.name, email = new.email WHERE user_id = old.id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS users_fts_delete AFTER DELETE ON users BEGIN
+		DELETE FROM users_fts WHERE user_id = old.id;
+	END`,
+	"INSERT INTO users_fts (user_id, name, email) SELECT id, name, email FROM users WHERE id NOT IN (SELECT user_id FROM users_fts)",
+}
+
+// CreateUserSearchIndexes sets up full-text search on users: tsvector and
+// trigram indexes on Postgres, an FTS5 table on SQLite. SQLite drivers built
+// without FTS5 are skipped, and search falls back to LIKE.
+func CreateUserSearchIndexes(db *gorm.DB) error {
+	var statements []string
+	switch db.Dialector.Name() {
+	case "postgres":
+		statements = postgresUserSearchStatements
+	case "sqlite":
+		statements = sqliteUserSearchStatements
+	default:
+		return nil
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			if strings.Contains(err.Error(), "no such module: fts5") {
+				logging.Log.Warn("sqlite built without fts5, user search falls back to LIKE")
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func DropUserSearchIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "sqlite" {
+		return nil
+	}
+
+	return db.Exec("DROP TABLE IF EXISTS users_fts").Error
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"fmt"
+
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"gorm.io/gorm"
+)
+
+// Both indexes skip trashed users, so a trashed user's address or number
+// can be used again, and restoring them is checked by the service.
+const (
+	createUserEmailIndex = "CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower_active ON users (lower(email)) WHERE deleted_at IS NULL"
+	createUserPhoneIndex = "CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_number_active ON users (phone_number) WHERE deleted_at IS NULL AND phone_number <> ''"
+	dropUserPhoneIndex   = "DROP INDEX IF EXISTS idx_users_phone_number_active"
+)
+
+// CreateUserUniqueIndexes makes the database enforce what the service checks
+// before writing a user, so two concurrent requests cannot both pass the
+// check. Emails are unique regardless of case. Phone numbers are unique only
+// while PHONE_NUMBER_UNIQUE is set, so it must run after
+// NormalizePhoneNumbers.
+func CreateUserUniqueIndexes(db *gorm.DB) error {
+	if err := db.Exec(createUserEmailIndex).Error; err != nil {
+		return fmt.Errorf("create unique email index, emails that differ only in case must be merged first: %w", err)
+	}
+
+	if !helpers.GetPhoneNumberUnique() {
+		return db.Exec(dropUserPhoneIndex).Error
+	}
+
+	if err := db.Exec(createUserPhoneIndex).Error; err != nil {
+		return fmt.Errorf("create unique phone number index, duplicate numbers must be resolved first: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type Address struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;index;not null" json:"user_id"`
+	Type       string    `gorm:"not null" json:"type"`
+	IsDefault  bool      `gorm:"not null;default:false" json:"is_default"`
+	Street     string    `gorm:"not null" json:"street"`
+	City       string    `gorm:"not null" json:"city"`
+	Region     string    `json:"region"`
+	PostalCode string    `json:"postal_code"`
+	Country    string    `gorm:"type:char(2);not null" json:"country"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+
+	TimeStamp
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// CustomField is an extra user attribute defined by an admin. Options holds
+// the allowed values of an enum field as a JSON array.
+type CustomField struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Key      string    `gorm:"uniqueIndex;not null" json:"key"`
+	Label    string    `gorm:"not null" json:"label"`
+	Type     string    `gorm:"not null" json:"type"`
+	Required bool      `gorm:"not null;default:false" json:"required"`
+	Pattern  string    `json:"pattern"`
+	Options  string    `gorm:"type:text;not null;default:'[]'" json:"options"`
+
+	TimeStamp
+}
+
+// UserCustomFieldValue stores the value of a custom field for a user in its
+// canonical text form, which is also what list filters compare against.
+type UserCustomFieldValue struct {
+	UserID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	FieldID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"field_id"`
+	Value   string    `gorm:"type:text;not null" json:"value"`
+
+	User  User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Field CustomField `gorm:"foreignKey:FieldID;constraint:OnDelete:CASCADE" json:"-"`
+
+	TimeStamp
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExport struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;index;not null" json:"user_id"`
+	RequestedBy uuid.UUID  `gorm:"type:uuid;not null" json:"requested_by"`
+	Status      string     `gorm:"not null" json:"status"`
+	FilePath    string     `json:"-"`
+	Error       string     `json:"error"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+
+	TimeStamp
+}
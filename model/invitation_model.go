@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Invitation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Email      string     `gorm:"index;not null" json:"email"`
+	Role       string     `gorm:"not null" json:"role"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	InvitedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"invited_by"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	UserID     *uuid.UUID `gorm:"type:uuid" json:"user_id"`
+
+	TimeStamp
+}
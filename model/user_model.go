@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/mferdian/golang_boiller_plate/helpers"
 	"gorm.io/gorm"
@@ -9,12 +11,32 @@ import (
 type User struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	Name        string    `json:"name"`
-	Email       string    `gorm:"unique; not null" json:"email"`
+	Email       string    `gorm:"not null" json:"email"`
 	Password    string    `json:"password"`
-	PhoneNumber string    `json:"phone_number"`
+	PhoneNumber string    `gorm:"index" json:"phone_number"`
 	Address     string    `json:"address"`
 	Role        string    `json:"role"`
 
+	PendingEmail         string     `json:"pending_email"`
+	EmailChangeToken     string     `gorm:"index" json:"-"`
+	EmailChangeExpiresAt *time.Time `json:"-"`
+	EmailVerifiedAt      *time.Time `json:"email_verified_at"`
+
+	Status          string     `gorm:"not null;default:active;index" json:"status"`
+	StatusReason    string     `json:"status_reason"`
+	SuspendedUntil  *time.Time `json:"suspended_until"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+
+	LastLoginAt *time.Time `json:"last_login_at"`
+	LastLoginIP string     `json:"last_login_ip"`
+	LastSeenAt  *time.Time `gorm:"index" json:"last_seen_at"`
+
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at"`
+	AnonymizedAt        *time.Time `json:"anonymized_at"`
+
+	// Addresses is only loaded when a list expands it
+	Addresses []Address `gorm:"foreignKey:UserID" json:"-"`
 
 	TimeStamp
 }
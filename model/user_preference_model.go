@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// UserPreference stores only the preferences a user changed, as a JSON
+// object. Defaults are applied when reading, so they can change later
+// without touching stored rows.
+type UserPreference struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Overrides string    `gorm:"type:text;not null;default:'{}'" json:"overrides"`
+
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+
+	TimeStamp
+}
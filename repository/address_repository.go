@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type (
+	IAddressRepository interface {
+		Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error
+		CreateAddress(ctx context.Context, tx *gorm.DB, address model.Address) error
+		GetAddressByID(ctx context.Context, tx *gorm.DB, userID, addressID string) (model.Address, bool, error)
+		GetAddressesByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]model.Address, error)
+		CountAddressesByType(ctx context.Context, tx *gorm.DB, userID, addressType string) (int64, error)
+		UpdateAddress(ctx context.Context, tx *gorm.DB, address model.Address) error
+		UnsetDefaultAddress(ctx context.Context, tx *gorm.DB, userID, addressType string) error
+		PromoteDefaultAddress(ctx context.Context, tx *gorm.DB, userID, addressType string) error
+		DeleteAddress(ctx context.Context, tx *gorm.DB, addressID string) error
+	}
+
+	AddressRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewAddressRepository(db *gorm.DB) *AddressRepository {
+	return &AddressRepository{
+		db: db,
+	}
+}
+
+func (ar *AddressRepository) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Transaction(fn)
+}
+
+func (ar *AddressRepository) CreateAddress(ctx context.Context, tx *gorm.DB, address model.Address) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Create(&address).Error
+}
+
+func (ar *AddressRepository) GetAddressByID(ctx context.Context, tx *gorm.DB, userID, addressID string) (model.Address, bool, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var address model.Address
+	if err := tx.WithContext(ctx).Where("id = ? AND user_id = ?", addressID, userID).Take(&address).Error; err != nil {
+		return model.Address{}, false, err
+	}
+
+	return address, true, nil
+}
+
+func (ar *AddressRepository) GetAddressesByUserID(ctx context.Context, tx *gorm.DB, userID string) ([]model.Address, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var addresses []model.Address
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).
+		Order("type").Order("is_default DESC").Order("created_at").
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (ar *AddressRepository) CountAddressesByType(ctx context.Context, tx *gorm.DB, userID, addressType string) (int64, error) {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var count int64
+	err := tx.WithContext(ctx).Model(&model.Address{}).
+		Where("user_id = ? AND type = ?", userID, addressType).
+		Count(&count).Error
+
+	return count, err
+}
+
+// UpdateAddress writes every column, so fields can be cleared and the
+// default flag turned off.
+func (ar *AddressRepository) UpdateAddress(ctx context.Context, tx *gorm.DB, address model.Address) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.Address{}).Where("id = ?", address.ID).
+		Select("type", "is_default", "street", "city", "region", "postal_code", "country").
+		Updates(&address).Error
+}
+
+func (ar *AddressRepository) UnsetDefaultAddress(ctx context.Context, tx *gorm.DB, userID, addressType string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.Address{}).
+		Where("user_id = ? AND type = ? AND is_default = ?", userID, addressType, true).
+		Update("is_default", false).Error
+}
+
+// PromoteDefaultAddress makes the oldest address of the type the default
+// when none of them is.
+func (ar *AddressRepository) PromoteDefaultAddress(ctx context.Context, tx *gorm.DB, userID, addressType string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	var addresses []model.Address
+	err := tx.WithContext(ctx).Where("user_id = ? AND type = ?", userID, addressType).
+		Order("is_default DESC").Order("created_at").Limit(1).
+		Find(&addresses).Error
+	if err != nil || len(addresses) == 0 || addresses[0].IsDefault {
+		return err
+	}
+
+	return tx.WithContext(ctx).Model(&model.Address{}).Where("id = ?", addresses[0].ID).Update("is_default", true).Error
+}
+
+func (ar *AddressRepository) DeleteAddress(ctx context.Context, tx *gorm.DB, addressID string) error {
+	if tx == nil {
+		tx = ar.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", addressID).Delete(&model.Address{}).Error
+}
@@ -1,6 +1,167 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"gorm.io/gorm"
+)
+
+// SortColumn is a column a list may be sorted by. Nullable columns keep
+// their NULLs last in either direction, and Timestamp tells a cursor to read
+// the stored value back as a time.
+type SortColumn struct {
+	Name      string
+	Nullable  bool
+	Timestamp bool
+}
+
+// SortColumns maps the field names a client may sort a list by to the
+// columns they are stored in. Anything outside the map is rejected, so sort
+// input never reaches the SQL as-is.
+type SortColumns map[string]SortColumn
+
+type SortOrder struct {
+	Field  string
+	Column SortColumn
+	Desc   bool
+}
+
+var (
+	UserSortColumns = SortColumns{
+		"name":          {Name: "name"},
+		"email":         {Name: "email"},
+		"role":          {Name: "role"},
+		"status":        {Name: "status"},
+		"created_at":    {Name: "created_at", Timestamp: true},
+		"updated_at":    {Name: "updated_at", Timestamp: true},
+		"last_login_at": {Name: "last_login_at", Nullable: true, Timestamp: true},
+		"last_seen_at":  {Name: "last_seen_at", Nullable: true, Timestamp: true},
+	}
+
+	TrashedUserSortColumns = SortColumns{
+		"name":       {Name: "name"},
+		"email":      {Name: "email"},
+		"role":       {Name: "role"},
+		"created_at": {Name: "created_at", Timestamp: true},
+		"deleted_at": {Name: "deleted_at", Timestamp: true},
+	}
+
+	InvitationSortColumns = SortColumns{
+		"email":      {Name: "email"},
+		"role":       {Name: "role"},
+		"expires_at": {Name: "expires_at", Timestamp: true},
+		"created_at": {Name: "created_at", Timestamp: true},
+	}
+
+	sortByID = SortOrder{Field: "id", Column: SortColumn{Name: "id"}}
+)
+
+func (sc SortColumns) fields() string {
+	fields := make([]string, 0, len(sc))
+	for field := range sc {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
+
+// ParseSort reads a sort parameter such as "name,-created_at", where a
+// leading "-" sorts that field in descending order.
+func ParseSort(value string, columns SortColumns) ([]SortOrder, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var orders []SortOrder
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		field := strings.TrimSpace(part)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "-"), "+")
+
+		column, ok := columns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q, allowed fields are %s", constants.ErrInvalidSortField, field, columns.fields())
+		}
+		if seen[column.Name] {
+			return nil, fmt.Errorf("%w: %q is listed more than once", constants.ErrInvalidSortField, field)
+		}
+		seen[column.Name] = true
+
+		orders = append(orders, SortOrder{Field: field, Column: column, Desc: desc})
+	}
+
+	return orders, nil
+}
+
+// formatSort writes orders back as a sort parameter, so two spellings of
+// the same sort compare equal.
+func formatSort(orders []SortOrder) string {
+	parts := make([]string, 0, len(orders))
+	for _, order := range orders {
+		if order.Desc {
+			parts = append(parts, "-"+order.Field)
+		} else {
+			parts = append(parts, order.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// resolveSort returns the sort parameter in its canonical form, or fallback
+// when it is empty or invalid.
+func resolveSort(value string, columns SortColumns, fallback string) string {
+	orders, err := ParseSort(value, columns)
+	if err != nil || len(orders) == 0 {
+		return fallback
+	}
+	return formatSort(orders)
+}
+
+// sortOrders parses a sort that is already known to be valid and ends it on
+// the primary key, so rows that tie keep a stable order between pages.
+func sortOrders(value string, columns SortColumns) []SortOrder {
+	orders, _ := ParseSort(value, columns)
+	return append(orders, sortByID)
+}
+
+// orderBy renders the orders as an ORDER BY list. Reversed, every direction
+// flips and NULLs come first, which walks the same order backwards.
+func orderBy(orders []SortOrder, reverse bool) string {
+	parts := make([]string, 0, len(orders))
+	for _, order := range orders {
+		part := order.Column.Name + " ASC"
+		if order.Desc != reverse {
+			part = order.Column.Name + " DESC"
+		}
+
+		if order.Column.Nullable {
+			if reverse {
+				part += " NULLS FIRST"
+			} else {
+				part += " NULLS LAST"
+			}
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// SortBy orders by the sort parameter, which is expected to be validated
+// with ParseSort by the caller; an invalid one falls back as well.
+func SortBy(value string, columns SortColumns, fallback string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(orderBy(sortOrders(resolveSort(value, columns, fallback), columns), false))
+	}
+}
 
 func Paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -8,3 +169,75 @@ func Paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(offset).Limit(perPage)
 	}
 }
+
+// SearchUser matches users by name, email or phone number. Phone numbers are
+// stored in E.164, so the search is normalized the same way and also matched
+// on its digits alone to find partial numbers.
+func SearchUser(search string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search == "" {
+			return db
+		}
+
+		searchValue := "%" + strings.ToLower(search) + "%"
+		condition := "LOWER(name) LIKE ? OR LOWER(email) LIKE ?"
+		args := []any{searchValue, searchValue}
+
+		phoneCondition, phoneArgs := searchPhone(search)
+		condition += phoneCondition
+		args = append(args, phoneArgs...)
+
+		return db.Where(condition, args...)
+	}
+}
+
+// searchPhone returns the phone number part of a user search, to be OR-ed
+// onto the name and email conditions.
+func searchPhone(search string) (string, []any) {
+	var condition string
+	var args []any
+
+	if phone, err := helpers.NormalizePhoneNumber(search, helpers.GetPhoneDefaultRegion()); err == nil {
+		condition += " OR phone_number = ?"
+		args = append(args, phone)
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, search)
+	if len(digits) >= 4 {
+		condition += " OR phone_number LIKE ?"
+		args = append(args, "%"+digits+"%")
+	}
+
+	return condition, args
+}
+
+// InactiveSince matches users with no recorded activity after cutoff. Users
+// who never signed in count from the moment their account was created.
+func InactiveSince(cutoff time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("COALESCE(last_seen_at, last_login_at, created_at) < ?", cutoff)
+	}
+}
+
+// HasCustomFieldValues matches users whose custom fields, looked up by key,
+// hold exactly the given canonical values.
+func HasCustomFieldValues(filters map[string]string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		keys := make([]string, 0, len(filters))
+		for key := range filters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			db = db.Where("EXISTS (SELECT 1 FROM user_custom_field_values v JOIN custom_fields f ON f.id = v.field_id "+
+				"WHERE v.user_id = users.id AND f.key = ? AND v.value = ?)", key, filters[key])
+		}
+		return db
+	}
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var cursorSchemaCache = &sync.Map{}
+
+// ValidatePagination checks the page, sort and cursor of a list request, so
+// bad input is reported to the client before the repository is queried. Page
+// sizes above the configured maximum are capped rather than rejected.
+func ValidatePagination(req *dto.PaginationRequest, columns SortColumns) error {
+	if req.Page < 0 || req.PerPage < 0 || req.Limit < 0 {
+		return fmt.Errorf("%w: page, per_page and limit must not be negative", constants.ErrInvalidPagination)
+	}
+
+	maxPerPage := helpers.GetPaginationMaxPerPage()
+	req.PerPage = min(req.PerPage, maxPerPage)
+	req.Limit = min(req.Limit, maxPerPage)
+
+	if _, err := ParseSort(req.Sort, columns); err != nil {
+		return err
+	}
+
+	if req.Cursor == "" {
+		return nil
+	}
+
+	_, _, err := decodeCursor(*req, columns)
+	return err
+}
+
+// decodeCursor reads the request cursor and the sort it was issued for.
+// Following next_cursor needs no sort parameter, but a different one than
+// the cursor was issued for is rejected.
+func decodeCursor(req dto.PaginationRequest, columns SortColumns) ([]SortOrder, helpers.Cursor, error) {
+	cursor, err := helpers.DecodeCursor(req.Cursor)
+	if err != nil {
+		return nil, helpers.Cursor{}, err
+	}
+
+	orders, err := ParseSort(cursor.Sort, columns)
+	if err != nil || len(orders) == 0 {
+		return nil, helpers.Cursor{}, constants.ErrInvalidCursor
+	}
+
+	if req.Sort != "" {
+		requested, _ := ParseSort(req.Sort, columns)
+		if formatSort(requested) != cursor.Sort {
+			return nil, helpers.Cursor{}, fmt.Errorf("%w: it was issued for sort %q", constants.ErrInvalidCursor, cursor.Sort)
+		}
+	}
+
+	orders = append(orders, sortByID)
+	if len(cursor.Values) != len(orders) {
+		return nil, helpers.Cursor{}, constants.ErrInvalidCursor
+	}
+
+	for i, order := range orders {
+		value := cursor.Values[i]
+		if value == nil {
+			if !order.Column.Nullable {
+				return nil, helpers.Cursor{}, constants.ErrInvalidCursor
+			}
+			continue
+		}
+
+		if order.Column.Timestamp {
+			text, ok := value.(string)
+			if !ok {
+				return nil, helpers.Cursor{}, constants.ErrInvalidCursor
+			}
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, helpers.Cursor{}, constants.ErrInvalidCursor
+			}
+			cursor.Values[i] = t
+		}
+	}
+
+	return orders, cursor, nil
+}
+
+// keysetCondition matches the rows that come after values in the given
+// order: those past it on the first column, or tied on it and past it on
+// the next, and so on down to the primary key.
+func keysetCondition(orders []SortOrder, values []any, reverse bool) (string, []any) {
+	var disjuncts []string
+	var args []any
+
+	for i, order := range orders {
+		var parts []string
+		var partArgs []any
+
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				parts = append(parts, orders[j].Column.Name+" IS NULL")
+			} else {
+				parts = append(parts, orders[j].Column.Name+" = ?")
+				partArgs = append(partArgs, values[j])
+			}
+		}
+
+		// NULLs sort last walking forwards and first walking backwards, see
+		// orderBy.
+		column := order.Column.Name
+		nullsAfter := order.Column.Nullable && !reverse
+		switch {
+		case values[i] == nil && nullsAfter:
+			continue
+		case values[i] == nil:
+			parts = append(parts, column+" IS NOT NULL")
+		default:
+			op := ">"
+			if order.Desc != reverse {
+				op = "<"
+			}
+			if nullsAfter {
+				parts = append(parts, "("+column+" "+op+" ? OR "+column+" IS NULL)")
+			} else {
+				parts = append(parts, column+" "+op+" ?")
+			}
+			partArgs = append(partArgs, values[i])
+		}
+
+		disjuncts = append(disjuncts, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, partArgs...)
+	}
+
+	if len(disjuncts) == 0 {
+		return "1 = 0", nil
+	}
+
+	return strings.Join(disjuncts, " OR "), args
+}
+
+// rowCursor issues a cursor pointing at row.
+func rowCursor(ctx context.Context, sch *schema.Schema, row reflect.Value, sort string, orders []SortOrder, before bool) (string, error) {
+	values := make([]any, len(orders))
+	for i, order := range orders {
+		field := sch.LookUpField(order.Column.Name)
+		if field == nil {
+			return "", fmt.Errorf("%w: column %q not found on %s", constants.ErrInvalidSortField, order.Column.Name, sch.Name)
+		}
+
+		value := field.ReflectValueOf(ctx, row)
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+
+		switch {
+		case value.Kind() == reflect.Ptr:
+			values[i] = nil
+		case value.Type().Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()):
+			v, err := value.Interface().(driver.Valuer).Value()
+			if err != nil {
+				return "", err
+			}
+			values[i] = v
+		default:
+			values[i] = value.Interface()
+		}
+	}
+
+	return helpers.EncodeCursor(helpers.Cursor{Sort: sort, Values: values, Before: before})
+}
+
+// CursorPaginate returns the rows after the request cursor, or before it
+// for a prev_cursor, with keyset predicates on the sort columns rather than
+// an OFFSET, so rows inserted while a client pages through do not shift the
+// pages. The query must be validated with ValidatePagination first.
+func CursorPaginate[T any](query *gorm.DB, req dto.PaginationRequest, columns SortColumns, fallback string) ([]T, dto.PaginationResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = constants.ENUM_PAGINATION_LIMIT
+	}
+
+	sort := resolveSort(req.Sort, columns, fallback)
+	orders := sortOrders(sort, columns)
+	reverse := false
+
+	if req.Cursor != "" {
+		var cursor helpers.Cursor
+		var err error
+		orders, cursor, err = decodeCursor(req, columns)
+		if err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+
+		sort = cursor.Sort
+		reverse = cursor.Before
+		condition, args := keysetCondition(orders, cursor.Values, reverse)
+		query = query.Where(condition, args...)
+	}
+
+	var rows []T
+	if err := query.Order(orderBy(orders, reverse)).Limit(limit + 1).Find(&rows).Error; err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	more := len(rows) > limit
+	if more {
+		rows = rows[:limit]
+	}
+
+	if reverse {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	res := dto.PaginationResponse{PerPage: limit}
+	if len(rows) == 0 {
+		return rows, res, nil
+	}
+
+	sch, err := schema.Parse(new(T), cursorSchemaCache, query.NamingStrategy)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+
+	// Walking backwards, the page the cursor came from is still ahead;
+	// walking forwards from a cursor, the previous page is still behind.
+	if more || reverse {
+		last := reflect.ValueOf(&rows[len(rows)-1]).Elem()
+		if res.NextCursor, err = rowCursor(query.Statement.Context, sch, last, sort, orders, false); err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+	}
+
+	if (reverse && more) || (!reverse && req.Cursor != "") {
+		first := reflect.ValueOf(&rows[0]).Elem()
+		if res.PrevCursor, err = rowCursor(query.Statement.Context, sch, first, sort, orders, true); err != nil {
+			return nil, dto.PaginationResponse{}, err
+		}
+	}
+
+	return rows, res, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	ICustomFieldRepository interface {
+		Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error
+
+		CreateCustomField(ctx context.Context, tx *gorm.DB, field model.CustomField) error
+		GetCustomFieldByID(ctx context.Context, tx *gorm.DB, fieldID string) (model.CustomField, bool, error)
+		GetCustomFieldByKey(ctx context.Context, tx *gorm.DB, key string) (model.CustomField, bool, error)
+		GetAllCustomField(ctx context.Context, tx *gorm.DB) ([]model.CustomField, error)
+		UpdateCustomField(ctx context.Context, tx *gorm.DB, field model.CustomField) error
+		DeleteCustomField(ctx context.Context, tx *gorm.DB, fieldID string) error
+
+		GetUserCustomFieldValues(ctx context.Context, tx *gorm.DB, userIDs []string) ([]model.UserCustomFieldValue, error)
+		SaveUserCustomFieldValues(ctx context.Context, tx *gorm.DB, values []model.UserCustomFieldValue) error
+		DeleteUserCustomFieldValues(ctx context.Context, tx *gorm.DB, userID string, fieldIDs []string) error
+	}
+
+	CustomFieldRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewCustomFieldRepository(db *gorm.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{
+		db: db,
+	}
+}
+
+func (cr *CustomFieldRepository) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	return tx.WithContext(ctx).Transaction(fn)
+}
+
+func (cr *CustomFieldRepository) CreateCustomField(ctx context.Context, tx *gorm.DB, field model.CustomField) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	return tx.WithContext(ctx).Create(&field).Error
+}
+
+func (cr *CustomFieldRepository) GetCustomFieldByID(ctx context.Context, tx *gorm.DB, fieldID string) (model.CustomField, bool, error) {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	var field model.CustomField
+	if err := tx.WithContext(ctx).Where("id = ?", fieldID).Take(&field).Error; err != nil {
+		return model.CustomField{}, false, err
+	}
+
+	return field, true, nil
+}
+
+func (cr *CustomFieldRepository) GetCustomFieldByKey(ctx context.Context, tx *gorm.DB, key string) (model.CustomField, bool, error) {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	var field model.CustomField
+	if err := tx.WithContext(ctx).Where("key = ?", key).Take(&field).Error; err != nil {
+		return model.CustomField{}, false, err
+	}
+
+	return field, true, nil
+}
+
+func (cr *CustomFieldRepository) GetAllCustomField(ctx context.Context, tx *gorm.DB) ([]model.CustomField, error) {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	var fields []model.CustomField
+	if err := tx.WithContext(ctx).Order("key ASC").Find(&fields).Error; err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// UpdateCustomField writes the editable columns, including zero values such
+// as required being switched off or the pattern being cleared.
+func (cr *CustomFieldRepository) UpdateCustomField(ctx context.Context, tx *gorm.DB, field model.CustomField) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.CustomField{}).Where("id = ?", field.ID).
+		Select("label", "required", "pattern", "options", "updated_at").
+		Updates(&field).Error
+}
+
+// DeleteCustomField removes the definition and every value stored for it.
+func (cr *CustomFieldRepository) DeleteCustomField(ctx context.Context, tx *gorm.DB, fieldID string) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("field_id = ?", fieldID).Delete(&model.UserCustomFieldValue{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id = ?", fieldID).Delete(&model.CustomField{}).Error
+	})
+}
+
+// GetUserCustomFieldValues loads the values of several users at once, with
+// their field definitions.
+func (cr *CustomFieldRepository) GetUserCustomFieldValues(ctx context.Context, tx *gorm.DB, userIDs []string) ([]model.UserCustomFieldValue, error) {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	var values []model.UserCustomFieldValue
+	if len(userIDs) == 0 {
+		return values, nil
+	}
+
+	if err := tx.WithContext(ctx).Preload("Field").Where("user_id IN ?", userIDs).Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// SaveUserCustomFieldValues inserts the values or replaces existing ones.
+func (cr *CustomFieldRepository) SaveUserCustomFieldValues(ctx context.Context, tx *gorm.DB, values []model.UserCustomFieldValue) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "field_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&values).Error
+}
+
+func (cr *CustomFieldRepository) DeleteUserCustomFieldValues(ctx context.Context, tx *gorm.DB, userID string, fieldIDs []string) error {
+	if tx == nil {
+		tx = cr.db
+	}
+
+	if len(fieldIDs) == 0 {
+		return nil
+	}
+
+	return tx.WithContext(ctx).Where("user_id = ? AND field_id IN ?", userID, fieldIDs).Delete(&model.UserCustomFieldValue{}).Error
+}
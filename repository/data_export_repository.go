@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type (
+	IDataExportRepository interface {
+		CreateDataExport(ctx context.Context, tx *gorm.DB, export model.DataExport) error
+		GetDataExportByID(ctx context.Context, tx *gorm.DB, exportID string) (model.DataExport, bool, error)
+		UpdateDataExport(ctx context.Context, tx *gorm.DB, export model.DataExport) error
+		GetExpiredDataExports(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]model.DataExport, error)
+		DeleteDataExportByID(ctx context.Context, tx *gorm.DB, exportID string) error
+		FailStaleDataExports(ctx context.Context, tx *gorm.DB, createdBefore time.Time, reason string) (int64, error)
+	}
+
+	DataExportRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewDataExportRepository(db *gorm.DB) *DataExportRepository {
+	return &DataExportRepository{
+		db: db,
+	}
+}
+
+func (der *DataExportRepository) CreateDataExport(ctx context.Context, tx *gorm.DB, export model.DataExport) error {
+	if tx == nil {
+		tx = der.db
+	}
+
+	return tx.WithContext(ctx).Create(&export).Error
+}
+
+func (der *DataExportRepository) GetDataExportByID(ctx context.Context, tx *gorm.DB, exportID string) (model.DataExport, bool, error) {
+	if tx == nil {
+		tx = der.db
+	}
+
+	var export model.DataExport
+	if err := tx.WithContext(ctx).Where("id = ?", exportID).Take(&export).Error; err != nil {
+		return model.DataExport{}, false, err
+	}
+
+	return export, true, nil
+}
+
+func (der *DataExportRepository) UpdateDataExport(ctx context.Context, tx *gorm.DB, export model.DataExport) error {
+	if tx == nil {
+		tx = der.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", export.ID).Updates(&export).Error
+}
+
+func (der *DataExportRepository) GetExpiredDataExports(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]model.DataExport, error) {
+	if tx == nil {
+		tx = der.db
+	}
+
+	var exports []model.DataExport
+	if err := tx.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (der *DataExportRepository) DeleteDataExportByID(ctx context.Context, tx *gorm.DB, exportID string) error {
+	if tx == nil {
+		tx = der.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Where("id = ?", exportID).Delete(&model.DataExport{}).Error
+}
+
+// FailStaleDataExports marks exports still pending since before
+// createdBefore as failed. Their build was lost, usually to a restart, and
+// nothing else would ever finish them.
+func (der *DataExportRepository) FailStaleDataExports(ctx context.Context, tx *gorm.DB, createdBefore time.Time, reason string) (int64, error) {
+	if tx == nil {
+		tx = der.db
+	}
+
+	result := tx.WithContext(ctx).Model(&model.DataExport{}).
+		Where("status = ? AND created_at < ?", constants.ENUM_DATA_EXPORT_STATUS_PENDING, createdBefore).
+		Updates(map[string]any{
+			"status": constants.ENUM_DATA_EXPORT_STATUS_FAILED,
+			"error":  reason,
+		})
+
+	return result.RowsAffected, result.Error
+}
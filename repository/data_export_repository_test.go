@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/config/database"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/model"
+)
+
+func TestDataExportRepository_CleanUpQueries(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewDataExportRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "export@mail.com")
+	now := time.Now()
+	expiredAt, validUntil := now.Add(-time.Hour), now.Add(time.Hour)
+
+	stale := model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: constants.ENUM_DATA_EXPORT_STATUS_PENDING}
+	building := model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: constants.ENUM_DATA_EXPORT_STATUS_PENDING}
+	expired := model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: constants.ENUM_DATA_EXPORT_STATUS_READY, ExpiresAt: &expiredAt}
+	ready := model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: constants.ENUM_DATA_EXPORT_STATUS_READY, ExpiresAt: &validUntil}
+	for _, export := range []*model.DataExport{&stale, &building, &expired, &ready} {
+		if err := db.Create(export).Error; err != nil {
+			t.Fatalf("failed to create data export: %v", err)
+		}
+	}
+	db.Model(&stale).Update("created_at", now.Add(-2*time.Hour))
+
+	failed, err := repo.FailStaleDataExports(ctx, nil, now.Add(-time.Hour), "timed out")
+	if err != nil || failed != 1 {
+		t.Fatalf("expected one stale export failed, got %d, %v", failed, err)
+	}
+
+	var storedStale, storedBuilding model.DataExport
+	db.Take(&storedStale, "id = ?", stale.ID)
+	if storedStale.Status != constants.ENUM_DATA_EXPORT_STATUS_FAILED || storedStale.Error != "timed out" {
+		t.Fatalf("expected the stale export to be failed, got %q (%s)", storedStale.Status, storedStale.Error)
+	}
+	db.Take(&storedBuilding, "id = ?", building.ID)
+	if storedBuilding.Status != constants.ENUM_DATA_EXPORT_STATUS_PENDING {
+		t.Fatalf("expected the recent export to stay pending, got %q", storedBuilding.Status)
+	}
+
+	exports, err := repo.GetExpiredDataExports(ctx, nil, now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exports) != 1 || exports[0].ID != expired.ID {
+		t.Fatalf("expected only the expired export, got %v", exports)
+	}
+
+	if err := repo.DeleteDataExportByID(ctx, nil, expired.ID.String()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var left int64
+	db.Unscoped().Model(&model.DataExport{}).Where("id = ?", expired.ID).Count(&left)
+	if left != 0 {
+		t.Fatalf("expected the expired export row to be gone")
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"gorm.io/gorm"
+)
+
+// FieldSet maps the attributes a client may pick with fields= to the columns
+// each is read from. Attributes without columns are filled in after the
+// query, like custom fields.
+type FieldSet map[string][]string
+
+// Expansion is a related resource loaded through a model association, in
+// the order its own endpoint lists it.
+type Expansion struct {
+	Association string
+	Order       string
+}
+
+// Expansions maps the related resources a client may embed with expand= to
+// how they are loaded.
+type Expansions map[string]Expansion
+
+var (
+	UserFields = FieldSet{
+		"id":            {"id"},
+		"name":          {"name"},
+		"email":         {"email"},
+		"phone_number":  {"phone_number"},
+		"address":       {"address"},
+		"status":        {"status"},
+		"pending_email": {"pending_email"},
+		"last_login_at": {"last_login_at"},
+		"last_login_ip": {"last_login_ip"},
+		"last_seen_at":  {"last_seen_at"},
+		"custom_fields": nil,
+		"highlights":    nil,
+	}
+
+	// Trashed users carry no custom fields
+	TrashedUserFields = FieldSet{
+		"id":            {"id"},
+		"name":          {"name"},
+		"email":         {"email"},
+		"phone_number":  {"phone_number"},
+		"address":       {"address"},
+		"status":        {"status"},
+		"pending_email": {"pending_email"},
+		"last_login_at": {"last_login_at"},
+		"last_login_ip": {"last_login_ip"},
+		"last_seen_at":  {"last_seen_at"},
+		"highlights":    nil,
+		"deleted_at":    {"deleted_at"},
+	}
+
+	UserExpansions = Expansions{
+		"addresses": {Association: "Addresses", Order: "type, is_default DESC, created_at"},
+	}
+)
+
+func (fs FieldSet) fields() string {
+	fields := make([]string, 0, len(fs))
+	for field := range fs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
+
+func (e Expansions) fields() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
+
+// ValidateFields checks every attribute of a fields= parameter against set.
+func ValidateFields(fields []string, set FieldSet) error {
+	for _, field := range fields {
+		if _, ok := set[field]; !ok {
+			return fmt.Errorf("%w: %q, allowed fields are %s", constants.ErrInvalidField, field, set.fields())
+		}
+	}
+	return nil
+}
+
+// ValidateExpand checks every resource of an expand= parameter against
+// expansions.
+func ValidateExpand(expand []string, expansions Expansions) error {
+	for _, field := range expand {
+		if _, ok := expansions[field]; !ok {
+			return fmt.Errorf("%w: %q, allowed values are %s", constants.ErrInvalidExpand, field, expansions.fields())
+		}
+	}
+	return nil
+}
+
+// SelectFields reads only the columns behind fields, plus the ones a list
+// needs whatever was picked: the primary key, which related resources and
+// custom fields are looked up by, and every column the list sorts on, so a
+// cursor can be built from any row. No fields reads every column.
+func SelectFields(fields []string, set FieldSet, sortColumns SortColumns) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(fields) == 0 {
+			return db
+		}
+
+		columns := []string{sortByID.Column.Name}
+		seen := map[string]bool{sortByID.Column.Name: true}
+		add := func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+
+		for _, field := range fields {
+			for _, column := range set[field] {
+				add(column)
+			}
+		}
+		for _, field := range sortedFields(sortColumns) {
+			add(sortColumns[field].Name)
+		}
+
+		return db.Select(columns)
+	}
+}
+
+// Expand preloads the associations behind each resource of expand.
+func Expand(expand []string, expansions Expansions) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, field := range expand {
+			if expansion, ok := expansions[field]; ok {
+				db = db.Preload(expansion.Association, func(db *gorm.DB) *gorm.DB {
+					return db.Order(expansion.Order)
+				})
+			}
+		}
+		return db
+	}
+}
+
+func sortedFields(columns SortColumns) []string {
+	fields := make([]string, 0, len(columns))
+	for field := range columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
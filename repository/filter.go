@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type FilterType int
+
+const (
+	FilterString FilterType = iota
+	FilterNumber
+	FilterBool
+	FilterTime
+	FilterUUID
+)
+
+// FilterField is a column a list may be filtered on. Its type decides how
+// values are parsed and which operators apply.
+type FilterField struct {
+	Column   string
+	Type     FilterType
+	Nullable bool
+}
+
+// FilterFields maps the field names a client may filter a list on to their
+// columns. Like SortColumns, anything outside the map is rejected.
+type FilterFields map[string]FilterField
+
+var (
+	UserFilterFields = NewFilterFields(&model.User{},
+		"name", "email", "phone_number", "role", "status", "email_verified_at",
+		"suspended_until", "last_login_at", "last_seen_at", "created_at", "updated_at")
+
+	TrashedUserFilterFields = NewFilterFields(&model.User{},
+		"name", "email", "role", "status", "created_at", "deleted_at")
+
+	InvitationFilterFields = NewFilterFields(&model.Invitation{},
+		"email", "role", "invited_by", "expires_at", "accepted_at", "revoked_at", "created_at")
+)
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	deletedAtType   = reflect.TypeOf(gorm.DeletedAt{})
+	uuidType        = reflect.TypeOf(uuid.UUID{})
+	filterSchemaMap = &sync.Map{}
+)
+
+// NewFilterFields whitelists columns of model for filtering, taking the type
+// of each from its Go field, so a repository only has to name them. It
+// panics on a column the model does not have, as that is a programming
+// error caught at start up.
+func NewFilterFields(m any, columns ...string) FilterFields {
+	sch, err := schema.Parse(m, filterSchemaMap, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+
+	fields := make(FilterFields, len(columns))
+	for _, column := range columns {
+		field := sch.LookUpField(column)
+		if field == nil {
+			panic(fmt.Sprintf("filter column %q not found on %s", column, sch.Name))
+		}
+
+		typ := field.FieldType
+		nullable := typ.Kind() == reflect.Ptr || typ == deletedAtType
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		var filterType FilterType
+		switch {
+		case typ == timeType || typ == deletedAtType:
+			filterType = FilterTime
+		case typ == uuidType:
+			filterType = FilterUUID
+		case typ.Kind() == reflect.String:
+			filterType = FilterString
+		case typ.Kind() == reflect.Bool:
+			filterType = FilterBool
+		case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Float64:
+			filterType = FilterNumber
+		default:
+			panic(fmt.Sprintf("filter column %q has unsupported type %s", column, typ))
+		}
+
+		fields[column] = FilterField{Column: field.DBName, Type: filterType, Nullable: nullable}
+	}
+
+	return fields
+}
+
+func (ff FilterFields) fields() string {
+	fields := make([]string, 0, len(ff))
+	for field := range ff {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
+
+func parseFilterValue(field FilterField, value string) (any, error) {
+	switch field.Type {
+	case FilterNumber:
+		return strconv.ParseFloat(value, 64)
+	case FilterBool:
+		return strconv.ParseBool(value)
+	case FilterTime:
+		if t, err := time.Parse(constants.ENUM_FILTER_DATE_FORMAT, value); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.RFC3339, value)
+	case FilterUUID:
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return id.String(), nil
+	default:
+		return value, nil
+	}
+}
+
+// filterDay reports whether value is a date without a time on a time field.
+// Such a value stands for the whole day, [from, to).
+func filterDay(field FilterField, value string) (from, to time.Time, ok bool) {
+	if field.Type != FilterTime {
+		return time.Time{}, time.Time{}, false
+	}
+
+	day, err := time.Parse(constants.ENUM_FILTER_DATE_FORMAT, value)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return day, day.AddDate(0, 0, 1), true
+}
+
+// filterClause translates one condition into a parameterized SQL clause.
+// Negative operators also match NULLs, which SQL comparisons would drop.
+func filterClause(condition dto.FilterCondition, fields FilterFields) (string, []any, error) {
+	field, ok := fields[condition.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unknown field %q, allowed fields are %s", constants.ErrInvalidFilter, condition.Field, fields.fields())
+	}
+
+	switch condition.Operator {
+	case constants.ENUM_FILTER_OPERATOR_EQ, constants.ENUM_FILTER_OPERATOR_NE,
+		constants.ENUM_FILTER_OPERATOR_GT, constants.ENUM_FILTER_OPERATOR_GTE,
+		constants.ENUM_FILTER_OPERATOR_LT, constants.ENUM_FILTER_OPERATOR_LTE,
+		constants.ENUM_FILTER_OPERATOR_IN, constants.ENUM_FILTER_OPERATOR_NIN,
+		constants.ENUM_FILTER_OPERATOR_CONTAINS, constants.ENUM_FILTER_OPERATOR_NULL:
+	default:
+		return "", nil, fmt.Errorf("%w: unknown operator %q on %s", constants.ErrInvalidFilter, condition.Operator, condition.Field)
+	}
+
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s[%s] %s", constants.ErrInvalidFilter, condition.Field, condition.Operator, reason)
+	}
+
+	column := field.Column
+	orNull := ""
+	if field.Nullable {
+		orNull = " OR " + column + " IS NULL"
+	}
+
+	switch condition.Operator {
+	case constants.ENUM_FILTER_OPERATOR_NULL:
+		if !field.Nullable {
+			return "", nil, invalid("is not supported, the field is never empty")
+		}
+		isNull, err := strconv.ParseBool(condition.Value)
+		if err != nil {
+			return "", nil, invalid("expects true or false")
+		}
+		if isNull {
+			return column + " IS NULL", nil, nil
+		}
+		return column + " IS NOT NULL", nil, nil
+
+	case constants.ENUM_FILTER_OPERATOR_CONTAINS:
+		if field.Type != FilterString {
+			return "", nil, invalid("is only supported on text fields")
+		}
+		return "LOWER(" + column + ") LIKE ?", []any{"%" + strings.ToLower(condition.Value) + "%"}, nil
+
+	case constants.ENUM_FILTER_OPERATOR_IN, constants.ENUM_FILTER_OPERATOR_NIN:
+		var values []any
+		var days []string
+		for _, part := range strings.Split(condition.Value, ",") {
+			part = strings.TrimSpace(part)
+			if from, to, ok := filterDay(field, part); ok {
+				days = append(days, "("+column+" >= ? AND "+column+" < ?)")
+				values = append(values, from, to)
+				continue
+			}
+
+			value, err := parseFilterValue(field, part)
+			if err != nil {
+				return "", nil, invalid(fmt.Sprintf("has an invalid value %q", part))
+			}
+			if field.Type == FilterTime {
+				days = append(days, column+" = ?")
+			}
+			values = append(values, value)
+		}
+
+		// Times are matched one by one, as a date matches a whole day.
+		if field.Type == FilterTime {
+			if condition.Operator == constants.ENUM_FILTER_OPERATOR_IN {
+				return "(" + strings.Join(days, " OR ") + ")", values, nil
+			}
+			return "(NOT (" + strings.Join(days, " OR ") + ")" + orNull + ")", values, nil
+		}
+
+		if condition.Operator == constants.ENUM_FILTER_OPERATOR_IN {
+			return column + " IN ?", []any{values}, nil
+		}
+		return "(" + column + " NOT IN ?" + orNull + ")", []any{values}, nil
+	}
+
+	if from, to, ok := filterDay(field, condition.Value); ok {
+		switch condition.Operator {
+		case constants.ENUM_FILTER_OPERATOR_EQ:
+			return "(" + column + " >= ? AND " + column + " < ?)", []any{from, to}, nil
+		case constants.ENUM_FILTER_OPERATOR_NE:
+			return "(" + column + " < ? OR " + column + " >= ?" + orNull + ")", []any{from, to}, nil
+		case constants.ENUM_FILTER_OPERATOR_GT:
+			return column + " >= ?", []any{to}, nil
+		case constants.ENUM_FILTER_OPERATOR_GTE:
+			return column + " >= ?", []any{from}, nil
+		case constants.ENUM_FILTER_OPERATOR_LT:
+			return column + " < ?", []any{from}, nil
+		default:
+			return column + " < ?", []any{to}, nil
+		}
+	}
+
+	value, err := parseFilterValue(field, condition.Value)
+	if err != nil {
+		return "", nil, invalid(fmt.Sprintf("has an invalid value %q", condition.Value))
+	}
+
+	switch condition.Operator {
+	case constants.ENUM_FILTER_OPERATOR_EQ:
+		return column + " = ?", []any{value}, nil
+	case constants.ENUM_FILTER_OPERATOR_NE:
+		return "(" + column + " <> ?" + orNull + ")", []any{value}, nil
+	}
+
+	if field.Type == FilterBool || field.Type == FilterUUID {
+		return "", nil, invalid("is not supported on this field")
+	}
+
+	switch condition.Operator {
+	case constants.ENUM_FILTER_OPERATOR_GT:
+		return column + " > ?", []any{value}, nil
+	case constants.ENUM_FILTER_OPERATOR_GTE:
+		return column + " >= ?", []any{value}, nil
+	case constants.ENUM_FILTER_OPERATOR_LT:
+		return column + " < ?", []any{value}, nil
+	default:
+		return column + " <= ?", []any{value}, nil
+	}
+}
+
+// ValidateFilters checks the filters of a list request, so bad input is
+// reported to the client before the repository is queried.
+func ValidateFilters(conditions []dto.FilterCondition, fields FilterFields) error {
+	for _, condition := range conditions {
+		if _, _, err := filterClause(condition, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilterBy applies every condition, all of which must match. The conditions
+// are expected to be validated with ValidateFilters by the caller.
+func FilterBy(conditions []dto.FilterCondition, fields FilterFields) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, condition := range conditions {
+			query, args, err := filterClause(condition, fields)
+			if err != nil {
+				db.AddError(err)
+				return db
+			}
+			db = db.Where(query, args...)
+		}
+		return db
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/config/database"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+)
+
+func TestFilterClause(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	at := time.Date(2026, 1, 1, 10, 30, 0, 0, time.UTC)
+	invitedBy := "6f1c4a8e-2b0d-4c1e-9a55-3d2f7b8e9c10"
+
+	tests := []struct {
+		name      string
+		fields    FilterFields
+		condition dto.FilterCondition
+		sql       string
+		args      []any
+	}{
+		{"eq string", UserFilterFields, dto.FilterCondition{Field: "role", Operator: "eq", Value: "admin"},
+			"role = ?", []any{"admin"}},
+		{"ne keeps nulls", UserFilterFields, dto.FilterCondition{Field: "last_seen_at", Operator: "ne", Value: "2026-01-01T10:30:00Z"},
+			"(last_seen_at <> ? OR last_seen_at IS NULL)", []any{at}},
+		{"ne on a column never null", UserFilterFields, dto.FilterCondition{Field: "role", Operator: "ne", Value: "admin"},
+			"(role <> ?)", []any{"admin"}},
+		{"in", UserFilterFields, dto.FilterCondition{Field: "role", Operator: "in", Value: "admin, user"},
+			"role IN ?", []any{[]any{"admin", "user"}}},
+		{"nin", UserFilterFields, dto.FilterCondition{Field: "phone_number", Operator: "nin", Value: "1,2"},
+			"(phone_number NOT IN ?)", []any{[]any{"1", "2"}}},
+		{"in uuid", InvitationFilterFields, dto.FilterCondition{Field: "invited_by", Operator: "in", Value: invitedBy},
+			"invited_by IN ?", []any{[]any{invitedBy}}},
+		{"contains", UserFilterFields, dto.FilterCondition{Field: "name", Operator: "contains", Value: "Ann"},
+			"LOWER(name) LIKE ?", []any{"%ann%"}},
+		{"null", UserFilterFields, dto.FilterCondition{Field: "email_verified_at", Operator: "null", Value: "false"},
+			"email_verified_at IS NOT NULL", nil},
+		{"eq time", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "eq", Value: "2026-01-01T10:30:00Z"},
+			"created_at = ?", []any{at}},
+		{"eq date", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "eq", Value: "2026-01-01"},
+			"(created_at >= ? AND created_at < ?)", []any{day, nextDay}},
+		{"ne date keeps nulls", UserFilterFields, dto.FilterCondition{Field: "last_login_at", Operator: "ne", Value: "2026-01-01"},
+			"(last_login_at < ? OR last_login_at >= ? OR last_login_at IS NULL)", []any{day, nextDay}},
+		{"gt date", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "gt", Value: "2026-01-01"},
+			"created_at >= ?", []any{nextDay}},
+		{"lte date", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "lte", Value: "2026-01-01"},
+			"created_at < ?", []any{nextDay}},
+		{"in dates and times", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "in", Value: "2026-01-01,2026-01-01T10:30:00Z"},
+			"((created_at >= ? AND created_at < ?) OR created_at = ?)", []any{day, nextDay, at}},
+		{"nin dates keeps nulls", UserFilterFields, dto.FilterCondition{Field: "last_seen_at", Operator: "nin", Value: "2026-01-01"},
+			"(NOT ((last_seen_at >= ? AND last_seen_at < ?)) OR last_seen_at IS NULL)", []any{day, nextDay}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := filterClause(tt.condition, tt.fields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+				t.Fatalf("expected %s %v, got %s %v", tt.sql, tt.args, sql, args)
+			}
+		})
+	}
+}
+
+func TestFilterClause_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    FilterFields
+		condition dto.FilterCondition
+	}{
+		{"unknown field", UserFilterFields, dto.FilterCondition{Field: "password", Operator: "eq", Value: "x"}},
+		{"unknown operator", UserFilterFields, dto.FilterCondition{Field: "role", Operator: "like", Value: "x"}},
+		{"invalid time", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "gte", Value: "yesterday"}},
+		{"invalid time in list", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "in", Value: "2026-01-01,soon"}},
+		{"invalid uuid", InvitationFilterFields, dto.FilterCondition{Field: "invited_by", Operator: "eq", Value: "42"}},
+		{"range on uuid", InvitationFilterFields, dto.FilterCondition{Field: "invited_by", Operator: "gt", Value: "6f1c4a8e-2b0d-4c1e-9a55-3d2f7b8e9c10"}},
+		{"contains on time", UserFilterFields, dto.FilterCondition{Field: "created_at", Operator: "contains", Value: "2026"}},
+		{"null on a column never null", UserFilterFields, dto.FilterCondition{Field: "role", Operator: "null", Value: "true"}},
+		{"null expects a bool", UserFilterFields, dto.FilterCondition{Field: "last_seen_at", Operator: "null", Value: "maybe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := filterClause(tt.condition, tt.fields); !errors.Is(err, constants.ErrInvalidFilter) {
+				t.Fatalf("expected ErrInvalidFilter, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterBy_DateMatchesWholeDay(t *testing.T) {
+	db := database.SetupTestDB(t)
+
+	for email, createdAt := range map[string]time.Time{
+		"before@mail.com": time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC),
+		"during@mail.com": time.Date(2026, 1, 1, 15, 0, 0, 0, time.UTC),
+		"after@mail.com":  time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		user := createUser(t, db, email)
+		db.Model(&user).Update("created_at", createdAt)
+	}
+
+	var emails []string
+	err := db.Model(&model.User{}).
+		Scopes(FilterBy([]dto.FilterCondition{{Field: "created_at", Operator: "eq", Value: "2026-01-01"}}, UserFilterFields)).
+		Pluck("email", &emails).Error
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(emails) != 1 || emails[0] != "during@mail.com" {
+		t.Fatalf("expected only the user created during the day, got %v", emails)
+	}
+}
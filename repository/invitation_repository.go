@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type (
+	IInvitationRepository interface {
+		Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error
+		CreateInvitation(ctx context.Context, tx *gorm.DB, invitation model.Invitation) error
+		GetInvitationByID(ctx context.Context, tx *gorm.DB, invitationID string) (model.Invitation, bool, error)
+		GetInvitationByTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (model.Invitation, bool, error)
+		GetPendingInvitationByEmail(ctx context.Context, tx *gorm.DB, email string, now time.Time) (model.Invitation, bool, error)
+		GetAllInvitationWithPagination(ctx context.Context, tx *gorm.DB, req dto.InvitationPaginationRequest, now time.Time) (dto.InvitationPaginationRepositoryResponse, error)
+		UpdateInvitationColumns(ctx context.Context, tx *gorm.DB, invitationID string, columns map[string]any) error
+		MarkInvitationAccepted(ctx context.Context, tx *gorm.DB, invitationID string, columns map[string]any) (bool, error)
+	}
+
+	InvitationRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{
+		db: db,
+	}
+}
+
+func (ir *InvitationRepository) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	return tx.WithContext(ctx).Transaction(fn)
+}
+
+func (ir *InvitationRepository) CreateInvitation(ctx context.Context, tx *gorm.DB, invitation model.Invitation) error {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	return tx.WithContext(ctx).Create(&invitation).Error
+}
+
+func (ir *InvitationRepository) GetInvitationByID(ctx context.Context, tx *gorm.DB, invitationID string) (model.Invitation, bool, error) {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	var invitation model.Invitation
+	if err := tx.WithContext(ctx).Where("id = ?", invitationID).Take(&invitation).Error; err != nil {
+		return model.Invitation{}, false, err
+	}
+
+	return invitation, true, nil
+}
+
+func (ir *InvitationRepository) GetInvitationByTokenHash(ctx context.Context, tx *gorm.DB, tokenHash string) (model.Invitation, bool, error) {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	var invitation model.Invitation
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).Take(&invitation).Error; err != nil {
+		return model.Invitation{}, false, err
+	}
+
+	return invitation, true, nil
+}
+
+func (ir *InvitationRepository) GetPendingInvitationByEmail(ctx context.Context, tx *gorm.DB, email string, now time.Time) (model.Invitation, bool, error) {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	var invitation model.Invitation
+	if err := tx.WithContext(ctx).Scopes(invitationStatus(constants.ENUM_INVITATION_STATUS_PENDING, now)).
+		Where("LOWER(email) = ?", strings.ToLower(email)).
+		Take(&invitation).Error; err != nil {
+		return model.Invitation{}, false, err
+	}
+
+	return invitation, true, nil
+}
+
+// invitationStatus filters invitations by their derived status. An empty
+// status matches every invitation.
+func invitationStatus(status string, now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch status {
+		case constants.ENUM_INVITATION_STATUS_PENDING:
+			return db.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)
+		case constants.ENUM_INVITATION_STATUS_EXPIRED:
+			return db.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= ?", now)
+		case constants.ENUM_INVITATION_STATUS_ACCEPTED:
+			return db.Where("accepted_at IS NOT NULL")
+		case constants.ENUM_INVITATION_STATUS_REVOKED:
+			return db.Where("revoked_at IS NOT NULL")
+		default:
+			return db
+		}
+	}
+}
+
+func (ir *InvitationRepository) GetAllInvitationWithPagination(ctx context.Context, tx *gorm.DB, req dto.InvitationPaginationRequest, now time.Time) (dto.InvitationPaginationRepositoryResponse, error) {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	var invitations []model.Invitation
+	var count int64
+
+	if req.PaginationRequest.PerPage == 0 {
+		req.PaginationRequest.PerPage = constants.ENUM_PAGINATION_LIMIT
+	}
+
+	if req.PaginationRequest.Page == 0 {
+		req.PaginationRequest.Page = constants.ENUM_PAGINATION_PAGE
+	}
+
+	query := tx.WithContext(ctx).Model(&model.Invitation{}).Scopes(invitationStatus(req.Status, now))
+
+	if req.PaginationRequest.Search != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%"+strings.ToLower(req.PaginationRequest.Search)+"%")
+	}
+
+	query = query.Scopes(FilterBy(req.PaginationRequest.Filters, InvitationFilterFields))
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.InvitationPaginationRepositoryResponse{}, err
+	}
+
+	if req.PaginationRequest.IsCursor() {
+		rows, pagination, err := CursorPaginate[model.Invitation](query, req.PaginationRequest, InvitationSortColumns, "-created_at")
+		if err != nil {
+			return dto.InvitationPaginationRepositoryResponse{}, err
+		}
+
+		pagination.Count = count
+		return dto.InvitationPaginationRepositoryResponse{
+			Invitations:        rows,
+			PaginationResponse: pagination,
+		}, nil
+	}
+
+	if err := query.Scopes(SortBy(req.PaginationRequest.Sort, InvitationSortColumns, "-created_at")).Scopes(Paginate(req.PaginationRequest.Page, req.PaginationRequest.PerPage)).Find(&invitations).Error; err != nil {
+		return dto.InvitationPaginationRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PaginationRequest.PerPage)))
+
+	return dto.InvitationPaginationRepositoryResponse{
+		Invitations: invitations,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.PaginationRequest.Page,
+			PerPage: req.PaginationRequest.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, nil
+}
+
+func (ir *InvitationRepository) UpdateInvitationColumns(ctx context.Context, tx *gorm.DB, invitationID string, columns map[string]any) error {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.Invitation{}).Where("id = ?", invitationID).Updates(columns).Error
+}
+
+// MarkInvitationAccepted only updates an invitation nobody has used or
+// revoked yet and reports whether it did, so a token cannot be accepted
+// twice by concurrent requests.
+func (ir *InvitationRepository) MarkInvitationAccepted(ctx context.Context, tx *gorm.DB, invitationID string, columns map[string]any) (bool, error) {
+	if tx == nil {
+		tx = ir.db
+	}
+
+	result := tx.WithContext(ctx).Model(&model.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", invitationID).
+		Updates(columns)
+
+	return result.RowsAffected == 1, result.Error
+}
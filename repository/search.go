@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Search engines mark matched fragments with these control characters, so
+// the rest of the text can be HTML-escaped before they become <mark> tags.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+type (
+	// IUserSearch matches users against a search term on name, email and
+	// phone number, ranks the matches and highlights the matched fragments.
+	IUserSearch interface {
+		Match(term string) func(db *gorm.DB) *gorm.DB
+		// Rank scores a row's relevance to term, higher first. ok is false
+		// when the engine cannot rank, in which case the list keeps its sort.
+		Rank(term string) (rank clause.Expr, ok bool)
+		// Highlight returns, per user ID, the name and email with the
+		// matched fragments wrapped in <mark> tags. Fields that did not match
+		// are left out.
+		Highlight(ctx context.Context, tx *gorm.DB, term string, userIDs []string) (map[string]map[string]string, error)
+	}
+
+	// postgresUserSearch combines full-text search on name and email with
+	// pg_trgm similarity, so misspelled terms still match.
+	postgresUserSearch struct{}
+
+	// sqliteUserSearch uses the users_fts FTS5 table, matching each word of
+	// the term as a prefix.
+	sqliteUserSearch struct{}
+
+	// likeUserSearch is the fallback when no full-text index is available.
+	likeUserSearch struct{}
+)
+
+// NewUserSearch picks the search engine for the database behind db. SQLite
+// only gets full-text search when the driver is built with FTS5 support
+// (the sqlite_fts5 build tag) and the users_fts table exists.
+func NewUserSearch(db *gorm.DB) IUserSearch {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return postgresUserSearch{}
+	case "sqlite":
+		if db.Migrator().HasTable("users_fts") {
+			return sqliteUserSearch{}
+		}
+	}
+	return likeUserSearch{}
+}
+
+// SortByRelevance orders by rank, best first, then by the list's sort.
+func SortByRelevance(rank clause.Expr, value string, columns SortColumns, fallback string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		order := rank.SQL + " DESC, " + orderBy(sortOrders(resolveSort(value, columns, fallback), columns), false)
+		return db.Order(clause.OrderBy{Expression: clause.Expr{SQL: order, Vars: rank.Vars}})
+	}
+}
+
+// renderHighlight escapes text and turns the engine's markers into <mark>
+// tags. It reports false when nothing in text was marked.
+func renderHighlight(text string) (string, bool) {
+	if !strings.Contains(text, highlightStart) {
+		return "", false
+	}
+
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, highlightStart, "<mark>")
+	text = strings.ReplaceAll(text, highlightStop, "</mark>")
+	return text, true
+}
+
+type highlightRow struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+func collectHighlights(rows []highlightRow) map[string]map[string]string {
+	highlights := make(map[string]map[string]string)
+	for _, row := range rows {
+		fields := make(map[string]string)
+		if name, ok := renderHighlight(row.Name); ok {
+			fields["name"] = name
+		}
+		if email, ok := renderHighlight(row.Email); ok {
+			fields["email"] = email
+		}
+		if len(fields) > 0 {
+			highlights[row.ID] = fields
+		}
+	}
+	return highlights
+}
+
+const postgresUserDocument = "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, ''))"
+
+func (postgresUserSearch) Match(term string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if term == "" {
+			return db
+		}
+
+		pattern := "%" + term + "%"
+		condition := postgresUserDocument + " @@ websearch_to_tsquery('simple', ?)" +
+			" OR name % ? OR email % ? OR name ILIKE ? OR email ILIKE ?"
+		args := []any{term, term, term, pattern, pattern}
+
+		phoneCondition, phoneArgs := searchPhone(term)
+		condition += phoneCondition
+		args = append(args, phoneArgs...)
+
+		return db.Where(condition, args...)
+	}
+}
+
+func (postgresUserSearch) Rank(term string) (clause.Expr, bool) {
+	if term == "" {
+		return clause.Expr{}, false
+	}
+
+	return clause.Expr{
+		SQL:  "ts_rank(" + postgresUserDocument + ", websearch_to_tsquery('simple', ?)) + GREATEST(similarity(name, ?), similarity(email, ?))",
+		Vars: []any{term, term, term},
+	}, true
+}
+
+func (postgresUserSearch) Highlight(ctx context.Context, tx *gorm.DB, term string, userIDs []string) (map[string]map[string]string, error) {
+	if term == "" || len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	options := "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true"
+	var rows []highlightRow
+	err := tx.WithContext(ctx).Table("users").
+		Select("id, ts_headline('simple', name, websearch_to_tsquery('simple', ?), ?) AS name, "+
+			"ts_headline('simple', email, websearch_to_tsquery('simple', ?), ?) AS email", term, options, term, options).
+		Where("id IN ?", userIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return collectHighlights(rows), nil
+}
+
+// ftsQuery turns a term into an FTS5 query matching every word as a prefix.
+// Words are reduced to letters and digits, so the term cannot inject FTS5
+// syntax.
+func ftsQuery(term string) string {
+	words := strings.FieldsFunc(strings.ToLower(term), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	parts := make([]string, 0, len(words))
+	for _, word := range words {
+		parts = append(parts, `"`+word+`"*`)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (sqliteUserSearch) Match(term string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		query := ftsQuery(term)
+		if query == "" {
+			return db.Scopes(SearchUser(term))
+		}
+
+		searchValue := "%" + strings.ToLower(term) + "%"
+		condition := "id IN (SELECT user_id FROM users_fts WHERE users_fts MATCH ?) OR LOWER(name) LIKE ? OR LOWER(email) LIKE ?"
+		args := []any{query, searchValue, searchValue}
+
+		phoneCondition, phoneArgs := searchPhone(term)
+		condition += phoneCondition
+		args = append(args, phoneArgs...)
+
+		return db.Where(condition, args...)
+	}
+}
+
+func (sqliteUserSearch) Rank(term string) (clause.Expr, bool) {
+	query := ftsQuery(term)
+	if query == "" {
+		return clause.Expr{}, false
+	}
+
+	// bm25 scores better matches lower
+	return clause.Expr{
+		SQL:  "COALESCE((SELECT -bm25(users_fts) FROM users_fts WHERE users_fts MATCH ? AND users_fts.user_id = users.id), 0)",
+		Vars: []any{query},
+	}, true
+}
+
+func (sqliteUserSearch) Highlight(ctx context.Context, tx *gorm.DB, term string, userIDs []string) (map[string]map[string]string, error) {
+	query := ftsQuery(term)
+	if query == "" || len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []highlightRow
+	err := tx.WithContext(ctx).Table("users_fts").
+		Select("user_id AS id, highlight(users_fts, 1, ?, ?) AS name, highlight(users_fts, 2, ?, ?) AS email",
+			highlightStart, highlightStop, highlightStart, highlightStop).
+		Where("users_fts MATCH ? AND user_id IN ?", query, userIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return collectHighlights(rows), nil
+}
+
+func (likeUserSearch) Match(term string) func(db *gorm.DB) *gorm.DB {
+	return SearchUser(term)
+}
+
+func (likeUserSearch) Rank(string) (clause.Expr, bool) {
+	return clause.Expr{}, false
+}
+
+// markSubstring marks every case-insensitive occurrence of term in text.
+func markSubstring(text, term string) string {
+	lowerText, lowerTerm := strings.ToLower(text), strings.ToLower(term)
+	if lowerTerm == "" || len(lowerText) != len(text) {
+		return text
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lowerText, lowerTerm)
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+
+		b.WriteString(text[:i])
+		end := i + len(lowerTerm)
+		b.WriteString(highlightStart + text[i:end] + highlightStop)
+		text, lowerText = text[end:], lowerText[end:]
+	}
+}
+
+func (likeUserSearch) Highlight(ctx context.Context, tx *gorm.DB, term string, userIDs []string) (map[string]map[string]string, error) {
+	if term == "" || len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []highlightRow
+	if err := tx.WithContext(ctx).Table("users").Select("id, name, email").Where("id IN ?", userIDs).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		rows[i].Name = markSubstring(rows[i].Name, term)
+		rows[i].Email = markSubstring(rows[i].Email, term)
+	}
+
+	return collectHighlights(rows), nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mferdian/golang_boiller_plate/config/database"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createNamedUser adds a user for the search to find. The tests run against
+// FTS5 when built with the sqlite_fts5 tag, and against LIKE otherwise.
+func createNamedUser(t *testing.T, db *gorm.DB, name, email string) model.User {
+	t.Helper()
+
+	user := createUser(t, db, email)
+	if err := db.Model(&user).Update("name", name).Error; err != nil {
+		t.Fatalf("failed to name user: %v", err)
+	}
+	return user
+}
+
+func TestRenderHighlight(t *testing.T) {
+	got, ok := renderHighlight("<b>" + highlightStart + "Tom" + highlightStop + " & Jerry</b>")
+	if !ok || got != "&lt;b&gt;<mark>Tom</mark> &amp; Jerry&lt;/b&gt;" {
+		t.Fatalf("expected escaped text with a mark, got %q %v", got, ok)
+	}
+
+	if _, ok := renderHighlight("<b>Tom</b>"); ok {
+		t.Fatalf("expected text without markers not to be highlighted")
+	}
+}
+
+func TestUserSearch_Match(t *testing.T) {
+	db := database.SetupTestDB(t)
+	search := NewUserSearch(db)
+
+	alice := createNamedUser(t, db, "Alice Smith", "alice@mail.com")
+	alicia := createNamedUser(t, db, "Alicia Keys", "keys@mail.com")
+	createNamedUser(t, db, "Bob Jones", "bob@mail.com")
+
+	var ids []string
+	if err := db.Model(&model.User{}).Scopes(search.Match("ali")).Order("email").Pluck("id", &ids).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != alice.ID.String() || ids[1] != alicia.ID.String() {
+		t.Fatalf("expected alice and alicia, got %v", ids)
+	}
+}
+
+func TestUserSearch_Rank(t *testing.T) {
+	db := database.SetupTestDB(t)
+	search := NewUserSearch(db)
+
+	rank, ok := search.Rank("alice")
+	if _, fts := search.(sqliteUserSearch); !fts {
+		if ok {
+			t.Fatalf("expected LIKE search not to rank")
+		}
+		t.Skip("SQLite built without FTS5, run with -tags sqlite_fts5")
+	}
+	if !ok {
+		t.Fatalf("expected FTS5 search to rank")
+	}
+
+	createNamedUser(t, db, "Alice Smith", "smith@mail.com")
+	best := createNamedUser(t, db, "Alice Alice", "alice@mail.com")
+
+	var ids []string
+	err := db.Model(&model.User{}).Scopes(search.Match("alice")).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: rank.SQL + " DESC", Vars: rank.Vars}}).
+		Pluck("id", &ids).Error
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != best.ID.String() {
+		t.Fatalf("expected the user matching name and email first, got %v", ids)
+	}
+}
+
+func TestUserSearch_Highlight(t *testing.T) {
+	db := database.SetupTestDB(t)
+	search := NewUserSearch(db)
+
+	user := createNamedUser(t, db, "<Alice> & Co", "alice@mail.com")
+	other := createNamedUser(t, db, "Bob Jones", "bob@mail.com")
+
+	highlights, err := search.Highlight(context.Background(), db, "alice", []string{user.ID.String(), other.ID.String()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := highlights[user.ID.String()]
+	if fields["name"] != "&lt;<mark>Alice</mark>&gt; &amp; Co" {
+		t.Fatalf("expected the name escaped and marked, got %q", fields["name"])
+	}
+	if fields["email"] != "<mark>alice</mark>@mail.com" {
+		t.Fatalf("expected the email marked, got %q", fields["email"])
+	}
+	if _, ok := highlights[other.ID.String()]; ok {
+		t.Fatalf("expected no highlight for a user that did not match")
+	}
+}
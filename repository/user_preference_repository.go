@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	IUserPreferenceRepository interface {
+		GetUserPreference(ctx context.Context, tx *gorm.DB, userID string) (model.UserPreference, bool, error)
+		SaveUserPreference(ctx context.Context, tx *gorm.DB, preference model.UserPreference) error
+	}
+
+	UserPreferenceRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewUserPreferenceRepository(db *gorm.DB) *UserPreferenceRepository {
+	return &UserPreferenceRepository{
+		db: db,
+	}
+}
+
+func (upr *UserPreferenceRepository) GetUserPreference(ctx context.Context, tx *gorm.DB, userID string) (model.UserPreference, bool, error) {
+	if tx == nil {
+		tx = upr.db
+	}
+
+	var preference model.UserPreference
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Take(&preference).Error; err != nil {
+		return model.UserPreference{}, false, err
+	}
+
+	return preference, true, nil
+}
+
+// SaveUserPreference inserts the row or replaces the overrides of an
+// existing one.
+func (upr *UserPreferenceRepository) SaveUserPreference(ctx context.Context, tx *gorm.DB, preference model.UserPreference) error {
+	if tx == nil {
+		tx = upr.db
+	}
+
+	return tx.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"overrides", "updated_at"}),
+	}).Create(&preference).Error
+}
@@ -3,36 +3,75 @@ package repository
 import (
 	"context"
 	"math"
-	"strings"
+	"time"
 
+	"github.com/mferdian/golang_boiller_plate/constants"
 	"github.com/mferdian/golang_boiller_plate/dto"
 	"github.com/mferdian/golang_boiller_plate/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
 	IUserRepository interface {
+		Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error
+
 		Register(ctx context.Context, tx *gorm.DB, user model.User) error
 		GetUserByID(ctx context.Context, tx *gorm.DB, userID string) (model.User, bool, error)
 		GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (model.User, bool, error)
+		GetUserByPhoneNumber(ctx context.Context, tx *gorm.DB, phoneNumber string) (model.User, bool, error)
+		GetUserByEmailChangeToken(ctx context.Context, tx *gorm.DB, tokenHash string) (model.User, bool, error)
 		GetAllUser(ctx context.Context, tx *gorm.DB, search string) ([]model.User, error)
 		GetAllUserWithPagination(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error)
+		StreamUsers(ctx context.Context, tx *gorm.DB, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error
 		CreateUser(ctx context.Context, tx *gorm.DB, user model.User) error
+		CreateUserWithHashedPassword(ctx context.Context, tx *gorm.DB, user model.User) error
 		UpdateUser(ctx context.Context, tx *gorm.DB, user model.User) error
+		UpdateUserColumns(ctx context.Context, tx *gorm.DB, userID string, columns map[string]any) error
+		GetUsersByIDs(ctx context.Context, tx *gorm.DB, userIDs []string) ([]model.User, error)
+		GetUserIDsByFilter(ctx context.Context, tx *gorm.DB, filter dto.BulkUserFilter, limit int) ([]string, error)
 		DeleteUserByID(ctx context.Context, tx *gorm.DB, userID string) error
+
+		GetAllTrashedUserWithPagination(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error)
+		GetTrashedUserByID(ctx context.Context, tx *gorm.DB, userID string) (model.User, bool, error)
+		RestoreUserByID(ctx context.Context, tx *gorm.DB, userID string) error
+		PurgeUserByID(ctx context.Context, tx *gorm.DB, userID string) ([]string, error)
+
+		ScheduleUserDeletion(ctx context.Context, tx *gorm.DB, userID string, requestedAt, scheduledAt time.Time) error
+		CancelUserDeletion(ctx context.Context, tx *gorm.DB, userID string) error
+		GetUsersDueForErasure(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]model.User, error)
+		AnonymizeUser(ctx context.Context, tx *gorm.DB, user model.User) ([]string, error)
+
+		ReactivateExpiredSuspensions(ctx context.Context, tx *gorm.DB, now time.Time) (int64, error)
+
+		RecordUserLogin(ctx context.Context, tx *gorm.DB, userID string, loginAt time.Time, ip string) error
+		TouchUserLastSeen(ctx context.Context, tx *gorm.DB, userID string, seenAt, staleBefore time.Time) error
 	}
 
 	UserRepository struct {
-		db *gorm.DB
+		db     *gorm.DB
+		search IUserSearch
 	}
 )
 
 func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{
-		db: db,
+		db:     db,
+		search: NewUserSearch(db),
 	}
 }
 
+// Transaction runs fn inside a database transaction. When tx is already a
+// transaction the call opens a savepoint, so fn can fail on its own without
+// aborting the outer transaction.
+func (ur *UserRepository) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Transaction(fn)
+}
+
 func (ur *UserRepository) Register(ctx context.Context, tx *gorm.DB, user model.User) error {
 	if tx == nil {
 		tx = ur.db
@@ -59,8 +98,36 @@ func (ur *UserRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email
 		tx = ur.db
 	}
 
+	// Matches idx_users_email_lower_active, which treats emails that only
+	// differ in case as the same.
 	var user model.User
-	if err := tx.WithContext(ctx).Where("email = ?", email).Take(&user).Error; err != nil {
+	if err := tx.WithContext(ctx).Where("lower(email) = lower(?)", email).Take(&user).Error; err != nil {
+		return model.User{}, false, err
+	}
+
+	return user, true, nil
+}
+
+func (ur *UserRepository) GetUserByPhoneNumber(ctx context.Context, tx *gorm.DB, phoneNumber string) (model.User, bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var user model.User
+	if err := tx.WithContext(ctx).Where("phone_number = ?", phoneNumber).Take(&user).Error; err != nil {
+		return model.User{}, false, err
+	}
+
+	return user, true, nil
+}
+
+func (ur *UserRepository) GetUserByEmailChangeToken(ctx context.Context, tx *gorm.DB, tokenHash string) (model.User, bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var user model.User
+	if err := tx.WithContext(ctx).Where("email_change_token = ?", tokenHash).Take(&user).Error; err != nil {
 		return model.User{}, false, err
 	}
 
@@ -76,10 +143,10 @@ func (ur *UserRepository) GetAllUser(ctx context.Context, tx *gorm.DB, search st
 
 	query := tx.WithContext(ctx).Model(&model.User{})
 
-	if search != "" {
-		searchValue := "%" + strings.ToLower(search) + "%"
-		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?",
-			searchValue, searchValue)
+	query = query.Scopes(ur.search.Match(search))
+
+	if rank, ok := ur.search.Rank(search); ok {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{SQL: rank.SQL + " DESC", Vars: rank.Vars}})
 	}
 
 	if err := query.Find(&users).Error; err != nil {
@@ -107,30 +174,53 @@ func (ur *UserRepository) GetAllUserWithPagination(ctx context.Context, tx *gorm
 		req.PaginationRequest.Page = 1
 	}
 
-	query := tx.WithContext(ctx).Model(&model.User{})
+	query := tx.WithContext(ctx).Model(&model.User{}).
+		Scopes(ur.filterUsers(req.PaginationRequest.Search, req.UserID, req.InactiveDays, req.CustomFields, req.PaginationRequest.Filters))
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.UserPaginationRepositoryResponse{}, err
+	}
 
-	if req.PaginationRequest.Search != "" {
-		searchValue := "%" + strings.ToLower(req.PaginationRequest.Search) + "%"
-		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?",
-			searchValue, searchValue)
+	query = query.Scopes(SelectFields(req.FieldList(), UserFields, UserSortColumns), Expand(req.ExpandList(), UserExpansions))
+
+	if req.PaginationRequest.IsCursor() {
+		rows, pagination, err := CursorPaginate[model.User](query, req.PaginationRequest, UserSortColumns, "-created_at")
+		if err != nil {
+			return dto.UserPaginationRepositoryResponse{}, err
+		}
+
+		highlights, err := ur.highlight(ctx, tx, req.PaginationRequest.Search, rows)
+		if err != nil {
+			return dto.UserPaginationRepositoryResponse{}, err
+		}
+
+		pagination.Count = count
+		return dto.UserPaginationRepositoryResponse{
+			Users:              rows,
+			Highlights:         highlights,
+			PaginationResponse: pagination,
+		}, nil
 	}
 
-	if req.UserID != "" {
-		query = query.Where("id = ?", req.UserID)
+	sortBy := SortBy(req.PaginationRequest.Sort, UserSortColumns, "-created_at")
+	if rank, ok := ur.search.Rank(req.PaginationRequest.Search); ok && req.PaginationRequest.Sort == "" {
+		sortBy = SortByRelevance(rank, req.PaginationRequest.Sort, UserSortColumns, "-created_at")
 	}
 
-	if err := query.Count(&count).Error; err != nil {
+	if err := query.Scopes(sortBy).Scopes(Paginate(req.PaginationRequest.Page, req.PaginationRequest.PerPage)).Find(&users).Error; err != nil {
 		return dto.UserPaginationRepositoryResponse{}, err
 	}
 
-	if err := query.Order("created_at DESC").Scopes(Paginate(req.PaginationRequest.Page, req.PaginationRequest.PerPage)).Find(&users).Error; err != nil {
+	highlights, err := ur.highlight(ctx, tx, req.PaginationRequest.Search, users)
+	if err != nil {
 		return dto.UserPaginationRepositoryResponse{}, err
 	}
 
 	totalPage := int64(math.Ceil(float64(count) / float64(req.PaginationRequest.PerPage)))
 
 	return dto.UserPaginationRepositoryResponse{
-		Users: users,
+		Users:      users,
+		Highlights: highlights,
 		PaginationResponse: dto.PaginationResponse{
 			Page:    req.PaginationRequest.Page,
 			PerPage: req.PaginationRequest.PerPage,
@@ -140,6 +230,56 @@ func (ur *UserRepository) GetAllUserWithPagination(ctx context.Context, tx *gorm
 	}, err
 }
 
+// highlight marks where a page of users matched the search term. Cursor
+// pages are not ranked, as relevance is not a column a cursor can resume
+// from, but they are highlighted all the same.
+func (ur *UserRepository) highlight(ctx context.Context, tx *gorm.DB, search string, users []model.User) (map[string]map[string]string, error) {
+	if search == "" || len(users) == 0 {
+		return nil, nil
+	}
+
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID.String())
+	}
+
+	return ur.search.Highlight(ctx, tx, search, userIDs)
+}
+
+// filterUsers narrows a user query to the users a list, an export or a bulk
+// action asks for, so all of them match the same rows.
+func (ur *UserRepository) filterUsers(search, userID string, inactiveDays int, customFields map[string]string, filters []dto.FilterCondition) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(ur.search.Match(search))
+
+		if userID != "" {
+			db = db.Where("id = ?", userID)
+		}
+
+		if inactiveDays > 0 {
+			db = db.Scopes(InactiveSince(time.Now().AddDate(0, 0, -inactiveDays)))
+		}
+
+		return db.Scopes(HasCustomFieldValues(customFields), FilterBy(filters, UserFilterFields))
+	}
+}
+
+// StreamUsers hands matching users to fn one batch at a time, walking the
+// table by primary key so memory use does not grow with the table size.
+func (ur *UserRepository) StreamUsers(ctx context.Context, tx *gorm.DB, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	query := tx.WithContext(ctx).Model(&model.User{}).
+		Scopes(ur.filterUsers(req.Search, req.UserID, req.InactiveDays, req.CustomFields, req.Filters))
+
+	var users []model.User
+	return query.FindInBatches(&users, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(users)
+	}).Error
+}
+
 func (ur *UserRepository) CreateUser(ctx context.Context, tx *gorm.DB, user model.User) error {
 	if tx == nil {
 		tx = ur.db
@@ -148,6 +288,16 @@ func (ur *UserRepository) CreateUser(ctx context.Context, tx *gorm.DB, user mode
 	return tx.WithContext(ctx).Create(&user).Error
 }
 
+// CreateUserWithHashedPassword inserts a user whose password the caller has
+// already hashed, skipping the hook that would hash it again.
+func (ur *UserRepository) CreateUserWithHashedPassword(ctx context.Context, tx *gorm.DB, user model.User) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Session(&gorm.Session{SkipHooks: true}).Create(&user).Error
+}
+
 func (ur *UserRepository) UpdateUser(ctx context.Context, tx *gorm.DB, user model.User) error {
 	if tx == nil {
 		tx = ur.db
@@ -156,6 +306,53 @@ func (ur *UserRepository) UpdateUser(ctx context.Context, tx *gorm.DB, user mode
 	return tx.WithContext(ctx).Where("id = ?", user.ID).Updates(&user).Error
 }
 
+// UpdateUserColumns writes the given columns as they are, including zero
+// values, which UpdateUser skips.
+func (ur *UserRepository) UpdateUserColumns(ctx context.Context, tx *gorm.DB, userID string, columns map[string]any) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(columns).Error
+}
+
+func (ur *UserRepository) GetUsersByIDs(ctx context.Context, tx *gorm.DB, userIDs []string) ([]model.User, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var users []model.User
+	if err := tx.WithContext(ctx).Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetUserIDsByFilter selects users with the scope the list uses. The role
+// shortcut is one more eq condition, and the filter is expected to be
+// validated by the caller.
+func (ur *UserRepository) GetUserIDsByFilter(ctx context.Context, tx *gorm.DB, filter dto.BulkUserFilter, limit int) ([]string, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	filters := append([]dto.FilterCondition{}, filter.Filters...)
+	if filter.Role != "" {
+		filters = append(filters, dto.FilterCondition{Field: "role", Operator: constants.ENUM_FILTER_OPERATOR_EQ, Value: filter.Role})
+	}
+
+	query := tx.WithContext(ctx).Model(&model.User{}).
+		Scopes(ur.filterUsers(filter.Search, "", filter.InactiveDays, filter.CustomFields, filters))
+
+	var userIDs []string
+	if err := query.Order("id").Limit(limit).Pluck("id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 func (ur *UserRepository) DeleteUserByID(ctx context.Context, tx *gorm.DB, userID string) error {
 	if tx == nil {
 		tx = ur.db
@@ -163,3 +360,279 @@ func (ur *UserRepository) DeleteUserByID(ctx context.Context, tx *gorm.DB, userI
 
 	return tx.WithContext(ctx).Where("id = ?", userID).Delete(&model.User{}).Error
 }
+
+func (ur *UserRepository) GetAllTrashedUserWithPagination(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var users []model.User
+	var count int64
+
+	if req.PaginationRequest.PerPage == 0 {
+		req.PaginationRequest.PerPage = 10
+	}
+
+	if req.PaginationRequest.Page == 0 {
+		req.PaginationRequest.Page = 1
+	}
+
+	query := tx.WithContext(ctx).Unscoped().Model(&model.User{}).Where("deleted_at IS NOT NULL")
+
+	query = query.Scopes(ur.search.Match(req.PaginationRequest.Search))
+
+	if req.UserID != "" {
+		query = query.Where("id = ?", req.UserID)
+	}
+
+	query = query.Scopes(FilterBy(req.PaginationRequest.Filters, TrashedUserFilterFields))
+
+	if err := query.Count(&count).Error; err != nil {
+		return dto.UserPaginationRepositoryResponse{}, err
+	}
+
+	query = query.Scopes(SelectFields(req.FieldList(), TrashedUserFields, TrashedUserSortColumns), Expand(req.ExpandList(), UserExpansions))
+
+	if req.PaginationRequest.IsCursor() {
+		rows, pagination, err := CursorPaginate[model.User](query, req.PaginationRequest, TrashedUserSortColumns, "-deleted_at")
+		if err != nil {
+			return dto.UserPaginationRepositoryResponse{}, err
+		}
+
+		highlights, err := ur.highlight(ctx, tx, req.PaginationRequest.Search, rows)
+		if err != nil {
+			return dto.UserPaginationRepositoryResponse{}, err
+		}
+
+		pagination.Count = count
+		return dto.UserPaginationRepositoryResponse{
+			Users:              rows,
+			Highlights:         highlights,
+			PaginationResponse: pagination,
+		}, nil
+	}
+
+	sortBy := SortBy(req.PaginationRequest.Sort, TrashedUserSortColumns, "-deleted_at")
+	if rank, ok := ur.search.Rank(req.PaginationRequest.Search); ok && req.PaginationRequest.Sort == "" {
+		sortBy = SortByRelevance(rank, req.PaginationRequest.Sort, TrashedUserSortColumns, "-deleted_at")
+	}
+
+	if err := query.Scopes(sortBy).Scopes(Paginate(req.PaginationRequest.Page, req.PaginationRequest.PerPage)).Find(&users).Error; err != nil {
+		return dto.UserPaginationRepositoryResponse{}, err
+	}
+
+	highlights, err := ur.highlight(ctx, tx, req.PaginationRequest.Search, users)
+	if err != nil {
+		return dto.UserPaginationRepositoryResponse{}, err
+	}
+
+	totalPage := int64(math.Ceil(float64(count) / float64(req.PaginationRequest.PerPage)))
+
+	return dto.UserPaginationRepositoryResponse{
+		Users:      users,
+		Highlights: highlights,
+		PaginationResponse: dto.PaginationResponse{
+			Page:    req.PaginationRequest.Page,
+			PerPage: req.PaginationRequest.PerPage,
+			MaxPage: totalPage,
+			Count:   count,
+		},
+	}, nil
+}
+
+func (ur *UserRepository) GetTrashedUserByID(ctx context.Context, tx *gorm.DB, userID string) (model.User, bool, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var user model.User
+	if err := tx.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", userID).Take(&user).Error; err != nil {
+		return model.User{}, false, err
+	}
+
+	return user, true, nil
+}
+
+func (ur *UserRepository) RestoreUserByID(ctx context.Context, tx *gorm.DB, userID string) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Model(&model.User{}).Where("id = ?", userID).Update("deleted_at", nil).Error
+}
+
+// PurgeUserByID deletes a trashed user for good, together with the rows
+// that reference them. It returns the paths of their data export archives
+// for the caller to remove once the deletion has committed.
+func (ur *UserRepository) PurgeUserByID(ctx context.Context, tx *gorm.DB, userID string) ([]string, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var archives []string
+	err := tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var trashed int64
+		if err := tx.Unscoped().Model(&model.User{}).Where("id = ? AND deleted_at IS NOT NULL", userID).Count(&trashed).Error; err != nil {
+			return err
+		}
+		if trashed == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		if archives, err = deleteUserData(tx, userID); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", userID).Delete(&model.User{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archives, nil
+}
+
+// deleteUserData deletes the rows that belong to a user and returns the
+// paths of the data export archives recorded for them.
+func deleteUserData(tx *gorm.DB, userID string) ([]string, error) {
+	var archives []string
+	if err := tx.Unscoped().Model(&model.DataExport{}).
+		Where("user_id = ? AND file_path <> ''", userID).
+		Pluck("file_path", &archives).Error; err != nil {
+		return nil, err
+	}
+
+	for _, dependent := range []any{&model.Address{}, &model.UserPreference{}, &model.UserCustomFieldValue{}, &model.DataExport{}} {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(dependent).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return archives, nil
+}
+
+func (ur *UserRepository) ScheduleUserDeletion(ctx context.Context, tx *gorm.DB, userID string, requestedAt, scheduledAt time.Time) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"deletion_requested_at": requestedAt,
+		"deletion_scheduled_at": scheduledAt,
+	}).Error
+}
+
+func (ur *UserRepository) CancelUserDeletion(ctx context.Context, tx *gorm.DB, userID string) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]any{
+		"deletion_requested_at": nil,
+		"deletion_scheduled_at": nil,
+	}).Error
+}
+
+// GetUsersDueForErasure includes trashed users: moving an account to the
+// trash keeps its personal data, so it still has to be anonymized.
+func (ur *UserRepository) GetUsersDueForErasure(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]model.User, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var users []model.User
+	if err := tx.WithContext(ctx).Unscoped().
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ? AND anonymized_at IS NULL", now).
+		Order("deletion_scheduled_at ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// AnonymizeUser overwrites the personal data of the user in place, deletes
+// the rows that belong to them and soft-deletes the user, keeping the ID so
+// references to it stay valid. A user already in the trash keeps their
+// original deleted_at. It returns the paths of the data export
+// archives, which hold personal data too, for the caller to remove.
+func (ur *UserRepository) AnonymizeUser(ctx context.Context, tx *gorm.DB, user model.User) ([]string, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	var archives []string
+	err := tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if archives, err = deleteUserData(tx, user.ID.String()); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&model.User{}).Where("id = ?", user.ID).Updates(map[string]any{
+			"name":                    user.Name,
+			"email":                   user.Email,
+			"password":                user.Password,
+			"phone_number":            "",
+			"address":                 "",
+			"pending_email":           "",
+			"email_change_token":      "",
+			"email_change_expires_at": nil,
+			"email_verified_at":       nil,
+			"last_login_ip":           "",
+			"deletion_scheduled_at":   nil,
+			"anonymized_at":           user.AnonymizedAt,
+			"deleted_at":              gorm.Expr("COALESCE(deleted_at, ?)", user.AnonymizedAt),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archives, nil
+}
+
+func (ur *UserRepository) ReactivateExpiredSuspensions(ctx context.Context, tx *gorm.DB, now time.Time) (int64, error) {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	result := tx.WithContext(ctx).Model(&model.User{}).
+		Where("status = ? AND suspended_until IS NOT NULL AND suspended_until <= ?", constants.ENUM_USER_STATUS_SUSPENDED, now).
+		Updates(map[string]any{
+			"status":            constants.ENUM_USER_STATUS_ACTIVE,
+			"status_reason":     "",
+			"suspended_until":   nil,
+			"status_changed_at": now,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// RecordUserLogin and TouchUserLastSeen write activity columns only, so they
+// do not bump updated_at.
+func (ur *UserRepository) RecordUserLogin(ctx context.Context, tx *gorm.DB, userID string, loginAt time.Time, ip string) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).
+		UpdateColumns(map[string]any{
+			"last_login_at": loginAt,
+			"last_login_ip": ip,
+			"last_seen_at":  loginAt,
+		}).Error
+}
+
+// TouchUserLastSeen only writes when the stored value is older than
+// staleBefore, so several instances of the API do not all update the row.
+func (ur *UserRepository) TouchUserLastSeen(ctx context.Context, tx *gorm.DB, userID string, seenAt, staleBefore time.Time) error {
+	if tx == nil {
+		tx = ur.db
+	}
+
+	return tx.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", userID, staleBefore).
+		UpdateColumn("last_seen_at", seenAt).Error
+}
@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/config/database"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/migrations"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+func createUser(t *testing.T, db *gorm.DB, email string) model.User {
+	t.Helper()
+
+	user := model.User{
+		ID:       uuid.New(),
+		Name:     "Some User",
+		Email:    email,
+		Password: "password123",
+		Role:     "user",
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	return user
+}
+
+func TestUserRepository_PurgeUserByID_DeletesDependents(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "purge@mail.com")
+	completedAt := time.Now()
+	rows := []any{
+		&model.Address{ID: uuid.New(), UserID: user.ID, Type: "home", Street: "Jl. Merdeka 1", City: "Jakarta", Country: "ID"},
+		&model.UserPreference{UserID: user.ID, Overrides: "{}"},
+		&model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: "ready", FilePath: "storage/exports/a.zip", CompletedAt: &completedAt},
+		&model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: "failed"},
+	}
+	for _, row := range rows {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("failed to create %T: %v", row, err)
+		}
+	}
+
+	if err := db.Delete(&user).Error; err != nil {
+		t.Fatalf("failed to trash user: %v", err)
+	}
+
+	archives, err := repo.PurgeUserByID(ctx, nil, user.ID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(archives) != 1 || archives[0] != "storage/exports/a.zip" {
+		t.Fatalf("unexpected archives: %v", archives)
+	}
+
+	for _, table := range []any{&model.User{}, &model.Address{}, &model.UserPreference{}, &model.DataExport{}} {
+		var count int64
+		db.Unscoped().Model(table).Count(&count)
+		if count != 0 {
+			t.Errorf("expected no %T rows left, got %d", table, count)
+		}
+	}
+}
+
+func TestUserRepository_PurgeUserByID_KeepsLiveUser(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := createUser(t, db, "live@mail.com")
+	address := model.Address{ID: uuid.New(), UserID: user.ID, Type: "home", Street: "Jl. Merdeka 1", City: "Jakarta", Country: "ID"}
+	if err := db.Create(&address).Error; err != nil {
+		t.Fatalf("failed to create address: %v", err)
+	}
+
+	_, err := repo.PurgeUserByID(context.Background(), nil, user.ID.String())
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+
+	var users, addresses int64
+	db.Model(&model.User{}).Count(&users)
+	db.Model(&model.Address{}).Count(&addresses)
+	if users != 1 || addresses != 1 {
+		t.Fatalf("expected the live user and their address to stay, got %d users and %d addresses", users, addresses)
+	}
+}
+
+func TestUserRepository_AnonymizeUser_DeletesDataExports(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+
+	user := createUser(t, db, "erase@mail.com")
+	completedAt := time.Now()
+	export := model.DataExport{ID: uuid.New(), UserID: user.ID, RequestedBy: user.ID, Status: "ready", FilePath: "storage/exports/b.zip", CompletedAt: &completedAt}
+	if err := db.Create(&export).Error; err != nil {
+		t.Fatalf("failed to create data export: %v", err)
+	}
+
+	now := time.Now()
+	user.Name = "Deleted User"
+	user.Email = "deleted+" + user.ID.String() + "@invalid"
+	user.AnonymizedAt = &now
+
+	archives, err := repo.AnonymizeUser(context.Background(), nil, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(archives) != 1 || archives[0] != export.FilePath {
+		t.Fatalf("unexpected archives: %v", archives)
+	}
+
+	var exports, users int64
+	db.Unscoped().Model(&model.DataExport{}).Count(&exports)
+	db.Unscoped().Model(&model.User{}).Where("id = ?", user.ID).Count(&users)
+	if exports != 0 || users != 1 {
+		t.Fatalf("expected the exports gone and the user kept, got %d exports and %d users", exports, users)
+	}
+}
+
+func TestUserRepository_AnonymizeUser_ErasesTrashedUser(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "trashed@mail.com")
+	trashedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	db.Model(&user).Updates(map[string]any{
+		"deletion_scheduled_at": time.Now().Add(-time.Minute),
+		"deleted_at":            trashedAt,
+	})
+
+	due, err := repo.GetUsersDueForErasure(ctx, nil, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != user.ID {
+		t.Fatalf("expected the trashed user to be due for erasure, got %v", due)
+	}
+
+	now := time.Now()
+	user = due[0]
+	user.Name = "Deleted User"
+	user.Email = "deleted+" + user.ID.String() + "@invalid"
+	user.AnonymizedAt = &now
+	if _, err := repo.AnonymizeUser(ctx, nil, user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var stored model.User
+	db.Unscoped().Take(&stored, "id = ?", user.ID)
+	if stored.AnonymizedAt == nil || stored.Email != user.Email || stored.Name != user.Name {
+		t.Fatalf("expected the trashed user to be anonymized, got %+v", stored)
+	}
+	if !stored.DeletedAt.Valid || !stored.DeletedAt.Time.Equal(trashedAt) {
+		t.Fatalf("expected deleted_at to stay %v, got %v", trashedAt, stored.DeletedAt)
+	}
+
+	due, err = repo.GetUsersDueForErasure(ctx, nil, time.Now(), 10)
+	if err != nil || len(due) != 0 {
+		t.Fatalf("expected nothing left to erase, got %v, %v", due, err)
+	}
+}
+
+func TestUserRepository_StreamUsers_AppliesListFilters(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+
+	admin := createUser(t, db, "admin@mail.com")
+	db.Model(&admin).Update("role", "admin")
+	createUser(t, db, "user@mail.com")
+
+	dormant := createUser(t, db, "dormant@mail.com")
+	db.Model(&dormant).Updates(map[string]any{"role": "admin", "created_at": time.Now().AddDate(0, 0, -90)})
+
+	var streamed []string
+	err := repo.StreamUsers(context.Background(), nil, dto.ExportUserRequest{
+		InactiveDays: 30,
+		Filters:      []dto.FilterCondition{{Field: "role", Operator: "eq", Value: "admin"}},
+	}, 10, func(users []model.User) error {
+		for _, user := range users {
+			streamed = append(streamed, user.Email)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(streamed) != 1 || streamed[0] != dormant.Email {
+		t.Fatalf("expected only the dormant admin, got %v", streamed)
+	}
+}
+
+func TestUserRepository_GetUserIDsByFilter_MatchesList(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	createUser(t, db, "active-admin@mail.com")
+	dormantAdmin := createUser(t, db, "dormant-admin@mail.com")
+	dormantUser := createUser(t, db, "dormant-user@mail.com")
+	db.Model(&model.User{}).Where("email LIKE ?", "%admin%").Update("role", "admin")
+	db.Model(&model.User{}).Where("email LIKE ?", "dormant%").Update("created_at", time.Now().AddDate(0, 0, -90))
+	db.Model(&dormantUser).Update("status", "suspended")
+
+	filter := dto.BulkUserFilter{
+		InactiveDays: 30,
+		Filters:      []dto.FilterCondition{{Field: "role", Operator: "eq", Value: "admin"}},
+	}
+
+	userIDs, err := repo.GetUserIDsByFilter(ctx, nil, filter, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, err := repo.GetAllUserWithPagination(ctx, nil, dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Filters: filter.Filters},
+		InactiveDays:      filter.InactiveDays,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(userIDs) != 1 || userIDs[0] != dormantAdmin.ID.String() || len(list.Users) != 1 || list.Users[0].ID != dormantAdmin.ID {
+		t.Fatalf("expected the bulk selection and the list to hold only the dormant admin, got %v and %d users", userIDs, len(list.Users))
+	}
+
+	userIDs, err = repo.GetUserIDsByFilter(ctx, nil, dto.BulkUserFilter{
+		Role:    "user",
+		Filters: []dto.FilterCondition{{Field: "status", Operator: "eq", Value: "suspended"}},
+	}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(userIDs) != 1 || userIDs[0] != dormantUser.ID.String() {
+		t.Fatalf("expected the role shortcut to combine with the filters, got %v", userIDs)
+	}
+}
+
+func TestUserRepository_TouchUserLastSeen_SkipsFreshValue(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, db, "seen@mail.com")
+	first := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	if err := repo.TouchUserLastSeen(ctx, nil, user.ID.String(), first, first.Add(-5*time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := repo.TouchUserLastSeen(ctx, nil, user.ID.String(), now, now.Add(-5*time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var stored model.User
+	db.Take(&stored, "id = ?", user.ID)
+	if stored.LastSeenAt == nil || !stored.LastSeenAt.Equal(first) {
+		t.Fatalf("expected last_seen_at to stay %v within the throttle interval, got %v", first, stored.LastSeenAt)
+	}
+}
+
+func TestUserRepository_UniqueEmailIgnoresCaseAndTrash(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	trashed := createUser(t, db, "reuse@mail.com")
+	if err := db.Delete(&trashed).Error; err != nil {
+		t.Fatalf("failed to trash user: %v", err)
+	}
+
+	live := model.User{ID: uuid.New(), Name: "Some User", Email: "Reuse@Mail.com", Password: "password123", Role: "user"}
+	if err := repo.CreateUser(ctx, nil, live); err != nil {
+		t.Fatalf("expected a trashed user's email to be free, got %v", err)
+	}
+
+	found, _, err := repo.GetUserByEmail(ctx, nil, "REUSE@mail.com")
+	if err != nil || found.ID != live.ID {
+		t.Fatalf("expected the lookup to ignore case, got %v, %v", found.ID, err)
+	}
+
+	duplicate := model.User{ID: uuid.New(), Name: "Some User", Email: "reuse@MAIL.com", Password: "password123", Role: "user"}
+	if err := repo.CreateUser(ctx, nil, duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("expected gorm.ErrDuplicatedKey, got %v", err)
+	}
+}
+
+func TestUserRepository_UniquePhoneNumberFollowsSetting(t *testing.T) {
+	t.Setenv("PHONE_NUMBER_UNIQUE", "true")
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	for i, email := range []string{"first@mail.com", "second@mail.com", "third@mail.com"} {
+		phone := "+6281234567890"
+		if i == 2 {
+			phone = ""
+		}
+		user := model.User{ID: uuid.New(), Name: "Some User", Email: email, Password: "password123", Role: "user", PhoneNumber: phone}
+		err := repo.CreateUser(ctx, nil, user)
+		if i == 1 && !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("expected gorm.ErrDuplicatedKey for a shared number, got %v", err)
+		}
+		if i != 1 && err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Setenv("PHONE_NUMBER_UNIQUE", "false")
+	if err := migrations.CreateUserUniqueIndexes(db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shared := model.User{ID: uuid.New(), Name: "Some User", Email: "fourth@mail.com", Password: "password123", Role: "user", PhoneNumber: "+6281234567890"}
+	if err := repo.CreateUser(ctx, nil, shared); err != nil {
+		t.Fatalf("expected shared numbers once the setting is off, got %v", err)
+	}
+}
+
+func TestUserRepository_CreateUserWithHashedPassword_KeepsHash(t *testing.T) {
+	db := database.SetupTestDB(t)
+	repo := NewUserRepository(db)
+
+	hashed, err := helpers.HashPassword("password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := model.User{ID: uuid.New(), Name: "Some User", Email: "hashed@mail.com", Password: hashed, Role: "user"}
+	if err := repo.CreateUserWithHashedPassword(context.Background(), nil, user); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var stored model.User
+	db.Take(&stored, "id = ?", user.ID)
+	if stored.Password != hashed {
+		t.Fatalf("expected the hash to be stored as given, got %q", stored.Password)
+	}
+}
@@ -8,13 +8,47 @@ import (
 	"github.com/mferdian/golang_boiller_plate/service"
 )
 
-func AdminRoutes(r *gin.Engine, userController controller.IUserController,
-	jwtService service.InterfaceJWTService) {
-	admin := r.Group("/api/users")
-	admin.Use(middleware.Authentication(jwtService))
+func AdminRoutes(r *gin.RouterGroup, userController controller.IUserController,
+	invitationController controller.IInvitationController,
+	customFieldController controller.ICustomFieldController,
+	jwtService service.InterfaceJWTService, userService service.IUserService) {
+	admin := r.Group("/users")
+	admin.Use(middleware.Authentication(jwtService, userService))
 	admin.Use(middleware.AuthorizeRole(constants.ENUM_ROLE_ADMIN))
 
 	// User management
 	admin.POST("", userController.CreateUser)
 	admin.GET("", userController.GetAllUser)
+	admin.POST("/import", userController.ImportUsers)
+	admin.GET("/export", userController.ExportUsers)
+	admin.PATCH("/bulk", userController.BulkUpdateUsers)
+	admin.DELETE("/bulk", userController.BulkDeleteUsers)
+
+	// Account status
+	admin.PATCH("/:id/status", userController.UpdateUserStatus)
+
+	// Soft-deleted users
+	admin.GET("/trash", userController.GetAllTrashedUser)
+	admin.POST("/:id/restore", userController.RestoreUser)
+	admin.DELETE("/:id/purge", userController.PurgeUser)
+
+	// Invitations
+	invitations := r.Group("/invitations")
+	invitations.Use(middleware.Authentication(jwtService, userService))
+	invitations.Use(middleware.AuthorizeRole(constants.ENUM_ROLE_ADMIN))
+
+	invitations.POST("", invitationController.CreateInvitation)
+	invitations.GET("", invitationController.GetAllInvitation)
+	invitations.POST("/:id/resend", invitationController.ResendInvitation)
+	invitations.DELETE("/:id", invitationController.RevokeInvitation)
+
+	// Custom profile fields, readable by every signed-in user so clients
+	// can render them, managed by admins
+	customFields := r.Group("/custom-fields")
+	customFields.Use(middleware.Authentication(jwtService, userService))
+
+	customFields.GET("", customFieldController.GetAllCustomField)
+	customFields.POST("", middleware.AuthorizeRole(constants.ENUM_ROLE_ADMIN), customFieldController.CreateCustomField)
+	customFields.PATCH("/:id", middleware.AuthorizeRole(constants.ENUM_ROLE_ADMIN), customFieldController.UpdateCustomField)
+	customFields.DELETE("/:id", middleware.AuthorizeRole(constants.ENUM_ROLE_ADMIN), customFieldController.DeleteCustomField)
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/middleware"
+)
+
+// APIRoutes mounts the routes that register adds under /api/v1 and /api/v2.
+// Both versions share the controllers, which map responses to the DTOs of the
+// version. The bare /api prefix keeps serving existing clients, in the
+// version their Accept header asks for or v1.
+func APIRoutes(r *gin.Engine, register func(api *gin.RouterGroup)) {
+	register(r.Group("/api/v1", middleware.APIVersion(constants.ENUM_API_VERSION_1)))
+	register(r.Group("/api/v2", middleware.APIVersion(constants.ENUM_API_VERSION_2)))
+	register(r.Group("/api", middleware.NegotiateAPIVersion()))
+}
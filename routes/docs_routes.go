@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/controller"
+)
+
+// DocsRoutes serves the OpenAPI document and the API reference built on it,
+// outside the versioned /api prefixes since they describe every version.
+func DocsRoutes(r *gin.Engine, docsController controller.IDocsController) {
+	r.GET("/openapi.json", docsController.GetOpenAPI)
+	r.GET("/docs", docsController.GetDocsUI)
+}
@@ -0,0 +1,63 @@
+package routes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/controller"
+	"github.com/mferdian/golang_boiller_plate/docs"
+)
+
+// apiRoutes registers the API the way main does, with controllers that are
+// never called.
+func apiRoutes() gin.RoutesInfo {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	userController := controller.NewUserController(nil)
+	invitationController := controller.NewInvitationController(nil)
+	APIRoutes(r, func(api *gin.RouterGroup) {
+		PublicRoutes(api, userController, invitationController)
+		AdminRoutes(api, userController, invitationController, controller.NewCustomFieldController(nil), nil, nil)
+		UserRoutes(api, userController, controller.NewDataExportController(nil), controller.NewAddressController(nil),
+			controller.NewUserPreferenceController(nil), nil, nil)
+	})
+
+	return r.Routes()
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	routes := apiRoutes()
+	if len(routes) == 0 {
+		t.Fatal("no routes registered")
+	}
+
+	for _, route := range routes {
+		path := strings.TrimPrefix(route.Path, "/api")
+		path = strings.TrimPrefix(path, "/"+constants.ENUM_API_VERSION_1)
+		path = strings.TrimPrefix(path, "/"+constants.ENUM_API_VERSION_2)
+
+		for _, version := range []string{constants.ENUM_API_VERSION_1, constants.ENUM_API_VERSION_2} {
+			if docs.OpenAPI(version).Paths[docs.PathOf(path)][strings.ToLower(route.Method)] == nil {
+				t.Errorf("%s %s is missing from the %s OpenAPI document", route.Method, route.Path, version)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_DocumentsNoUnknownRoute(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, route := range apiRoutes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, operation := range docs.Operations {
+		for _, prefix := range []string{"/api/" + constants.ENUM_API_VERSION_1, "/api/" + constants.ENUM_API_VERSION_2, "/api"} {
+			if !registered[operation.Method+" "+prefix+operation.Path] {
+				t.Errorf("%s %s is documented but not routed under %s", operation.Method, operation.Path, prefix)
+			}
+		}
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"github.com/mferdian/golang_boiller_plate/controller"
 )
 
-func PublicRoutes(r *gin.Engine, userController controller.IUserController) {
-	public := r.Group("/api")
+func PublicRoutes(r *gin.RouterGroup, userController controller.IUserController, invitationController controller.IInvitationController) {
+	public := r.Group("")
 	public.POST("/register", userController.Register)
 	public.POST("/login", userController.Login)
+	public.GET("/confirm-email", userController.ConfirmEmailChange)
+	public.POST("/auth/accept-invite", invitationController.AcceptInvitation)
 }
@@ -8,16 +8,40 @@ import (
 )
 
 func UserRoutes(
-	r *gin.Engine,
+	r *gin.RouterGroup,
 	userController controller.IUserController,
+	dataExportController controller.IDataExportController,
+	addressController controller.IAddressController,
+	preferenceController controller.IUserPreferenceController,
 	jwtService service.InterfaceJWTService,
+	userService service.IUserService,
 ) {
-	user := r.Group("/api/users")
-	user.Use(middleware.Authentication(jwtService))
+	// --- Current User ---
+	me := r.Group("/me")
+	me.Use(middleware.Authentication(jwtService, userService))
+	me.GET("", userController.GetMe)
+	me.PATCH("", userController.UpdateMe)
+	me.DELETE("", userController.DeleteMe)
+
+	user := r.Group("/users")
+	user.Use(middleware.Authentication(jwtService, userService))
 
 	// --- User Routes ---
 	user.PATCH("/:id", userController.UpdateUser)
 	user.GET("/:id", userController.GetUserByID)
 	user.DELETE("/:id", userController.DeleteUser)
-	
+
+	// --- Personal Data Export ---
+	user.POST("/:id/export", dataExportController.RequestDataExport)
+	user.GET("/:id/export/:exportId", dataExportController.DownloadDataExport)
+
+	// --- Addresses ---
+	user.GET("/:id/addresses", addressController.GetAddresses)
+	user.POST("/:id/addresses", addressController.CreateAddress)
+	user.PATCH("/:id/addresses/:addressId", addressController.UpdateAddress)
+	user.DELETE("/:id/addresses/:addressId", addressController.DeleteAddress)
+
+	// --- Preferences ---
+	user.GET("/:id/preferences", preferenceController.GetUserPreferences)
+	user.PATCH("/:id/preferences", preferenceController.UpdateUserPreferences)
 }
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"github.com/mferdian/golang_boiller_plate/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	IAddressService interface {
+		GetAddresses(ctx context.Context, req dto.GetAddressesRequest) ([]dto.AddressResponse, error)
+		CreateAddress(ctx context.Context, req dto.CreateAddressRequest) (dto.AddressResponse, error)
+		UpdateAddress(ctx context.Context, req dto.UpdateAddressRequest) (dto.AddressResponse, error)
+		DeleteAddress(ctx context.Context, req dto.DeleteAddressRequest) (dto.AddressResponse, error)
+	}
+
+	AddressService struct {
+		userRepo    repository.IUserRepository
+		addressRepo repository.IAddressRepository
+	}
+)
+
+func NewAddressService(userRepo repository.IUserRepository, addressRepo repository.IAddressRepository) *AddressService {
+	return &AddressService{
+		userRepo:    userRepo,
+		addressRepo: addressRepo,
+	}
+}
+
+func toAddressResponse(address model.Address) dto.AddressResponse {
+	return dto.AddressResponse{
+		ID:         address.ID,
+		Type:       address.Type,
+		IsDefault:  address.IsDefault,
+		Street:     address.Street,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		CreatedAt:  address.CreatedAt,
+		UpdatedAt:  address.UpdatedAt,
+	}
+}
+
+func toAddressResponses(addresses []model.Address) []dto.AddressResponse {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	responses := make([]dto.AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		responses = append(responses, toAddressResponse(address))
+	}
+	return responses
+}
+
+// validateAddress normalizes the address in place and checks the fields
+// every address needs.
+func validateAddress(address *model.Address) error {
+	address.Street = strings.TrimSpace(address.Street)
+	address.City = strings.TrimSpace(address.City)
+	address.Region = strings.TrimSpace(address.Region)
+	address.PostalCode = strings.TrimSpace(address.PostalCode)
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+
+	switch address.Type {
+	case constants.ENUM_ADDRESS_TYPE_HOME, constants.ENUM_ADDRESS_TYPE_BILLING, constants.ENUM_ADDRESS_TYPE_SHIPPING:
+	default:
+		return constants.ErrInvalidAddressType
+	}
+
+	if address.Street == "" || address.City == "" {
+		return constants.ErrAddressRequired
+	}
+
+	if !helpers.IsValidCountryCode(address.Country) {
+		return constants.ErrInvalidCountryCode
+	}
+
+	return nil
+}
+
+func (as *AddressService) GetAddresses(ctx context.Context, req dto.GetAddressesRequest) ([]dto.AddressResponse, error) {
+	addresses, err := as.addressRepo.GetAddressesByUserID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Error(constants.MESSAGE_FAILED_GET_LIST_ADDRESS)
+		return nil, constants.ErrGetAllAddress
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_ADDRESS+": %s", req.UserID)
+
+	datas := make([]dto.AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		datas = append(datas, toAddressResponse(address))
+	}
+
+	return datas, nil
+}
+
+func (as *AddressService) CreateAddress(ctx context.Context, req dto.CreateAddressRequest) (dto.AddressResponse, error) {
+	user, _, err := as.userRepo.GetUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Error(constants.MESSAGE_FAILED_CREATE_ADDRESS)
+		return dto.AddressResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	address := model.Address{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		Type:       req.Type,
+		IsDefault:  req.IsDefault,
+		Street:     req.Street,
+		City:       req.City,
+		Region:     req.Region,
+		PostalCode: req.PostalCode,
+		Country:    req.Country,
+	}
+
+	if err := validateAddress(&address); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CREATE_ADDRESS)
+		return dto.AddressResponse{}, err
+	}
+
+	err = as.addressRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		// The first address of a type is its default.
+		count, err := as.addressRepo.CountAddressesByType(ctx, tx, req.UserID, address.Type)
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			address.IsDefault = true
+		}
+
+		if address.IsDefault {
+			if err := as.addressRepo.UnsetDefaultAddress(ctx, tx, req.UserID, address.Type); err != nil {
+				return err
+			}
+		}
+
+		return as.addressRepo.CreateAddress(ctx, tx, address)
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_ADDRESS)
+		return dto.AddressResponse{}, constants.ErrCreateAddress
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_ADDRESS+": %s", address.ID)
+
+	return toAddressResponse(address), nil
+}
+
+func (as *AddressService) UpdateAddress(ctx context.Context, req dto.UpdateAddressRequest) (dto.AddressResponse, error) {
+	address, _, err := as.addressRepo.GetAddressByID(ctx, nil, req.UserID, req.AddressID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.AddressID).Warn(constants.MESSAGE_FAILED_UPDATE_ADDRESS)
+		return dto.AddressResponse{}, notFoundOr(err, constants.ErrAddressNotFound, constants.ErrUpdateAddress)
+	}
+
+	previousType := address.Type
+	wasDefault := address.IsDefault
+
+	if req.Type != nil {
+		address.Type = *req.Type
+	}
+	if req.IsDefault != nil {
+		address.IsDefault = *req.IsDefault
+	}
+	if req.Street != nil {
+		address.Street = *req.Street
+	}
+	if req.City != nil {
+		address.City = *req.City
+	}
+	if req.Region != nil {
+		address.Region = *req.Region
+	}
+	if req.PostalCode != nil {
+		address.PostalCode = *req.PostalCode
+	}
+	if req.Country != nil {
+		address.Country = *req.Country
+	}
+
+	if err := validateAddress(&address); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UPDATE_ADDRESS)
+		return dto.AddressResponse{}, err
+	}
+
+	// A type that moves or loses its default gets one of its remaining
+	// addresses promoted, so every type in use keeps exactly one default.
+	if address.Type != previousType && req.IsDefault == nil {
+		address.IsDefault = false
+	}
+
+	err = as.addressRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := as.addressRepo.UnsetDefaultAddress(ctx, tx, req.UserID, address.Type); err != nil {
+				return err
+			}
+		}
+
+		if err := as.addressRepo.UpdateAddress(ctx, tx, address); err != nil {
+			return err
+		}
+
+		if wasDefault && (!address.IsDefault || address.Type != previousType) {
+			if err := as.addressRepo.PromoteDefaultAddress(ctx, tx, req.UserID, previousType); err != nil {
+				return err
+			}
+		}
+
+		return as.addressRepo.PromoteDefaultAddress(ctx, tx, req.UserID, address.Type)
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_ADDRESS)
+		return dto.AddressResponse{}, constants.ErrUpdateAddress
+	}
+
+	updated, _, err := as.addressRepo.GetAddressByID(ctx, nil, req.UserID, req.AddressID)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_ADDRESS)
+		return dto.AddressResponse{}, constants.ErrUpdateAddress
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_ADDRESS+": %s", address.ID)
+
+	return toAddressResponse(updated), nil
+}
+
+func (as *AddressService) DeleteAddress(ctx context.Context, req dto.DeleteAddressRequest) (dto.AddressResponse, error) {
+	address, _, err := as.addressRepo.GetAddressByID(ctx, nil, req.UserID, req.AddressID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.AddressID).Warn(constants.MESSAGE_FAILED_DELETE_ADDRESS)
+		return dto.AddressResponse{}, notFoundOr(err, constants.ErrAddressNotFound, constants.ErrDeleteAddress)
+	}
+
+	err = as.addressRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := as.addressRepo.DeleteAddress(ctx, tx, req.AddressID); err != nil {
+			return err
+		}
+
+		if !address.IsDefault {
+			return nil
+		}
+
+		return as.addressRepo.PromoteDefaultAddress(ctx, tx, req.UserID, address.Type)
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_DELETE_ADDRESS)
+		return dto.AddressResponse{}, constants.ErrDeleteAddress
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_DELETE_ADDRESS+": %s", address.ID)
+
+	return toAddressResponse(address), nil
+}
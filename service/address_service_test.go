@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type mockAddressRepo struct {
+	createFn      func(ctx context.Context, address model.Address) error
+	getByIDFn     func(ctx context.Context, userID, addressID string) (model.Address, bool, error)
+	getByUserIDFn func(ctx context.Context, userID string) ([]model.Address, error)
+	countByTypeFn func(ctx context.Context, userID, addressType string) (int64, error)
+	updateFn      func(ctx context.Context, address model.Address) error
+	unsetFn       func(ctx context.Context, userID, addressType string) error
+	promoteFn     func(ctx context.Context, userID, addressType string) error
+	deleteFn      func(ctx context.Context, addressID string) error
+}
+
+func (m *mockAddressRepo) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return fn(tx)
+}
+
+func (m *mockAddressRepo) CreateAddress(ctx context.Context, _ *gorm.DB, address model.Address) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, address)
+	}
+	return nil
+}
+
+func (m *mockAddressRepo) GetAddressByID(ctx context.Context, _ *gorm.DB, userID, addressID string) (model.Address, bool, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, userID, addressID)
+	}
+	return model.Address{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockAddressRepo) GetAddressesByUserID(ctx context.Context, _ *gorm.DB, userID string) ([]model.Address, error) {
+	if m.getByUserIDFn != nil {
+		return m.getByUserIDFn(ctx, userID)
+	}
+	return []model.Address{}, nil
+}
+
+func (m *mockAddressRepo) CountAddressesByType(ctx context.Context, _ *gorm.DB, userID, addressType string) (int64, error) {
+	if m.countByTypeFn != nil {
+		return m.countByTypeFn(ctx, userID, addressType)
+	}
+	return 0, nil
+}
+
+func (m *mockAddressRepo) UpdateAddress(ctx context.Context, _ *gorm.DB, address model.Address) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, address)
+	}
+	return nil
+}
+
+func (m *mockAddressRepo) UnsetDefaultAddress(ctx context.Context, _ *gorm.DB, userID, addressType string) error {
+	if m.unsetFn != nil {
+		return m.unsetFn(ctx, userID, addressType)
+	}
+	return nil
+}
+
+func (m *mockAddressRepo) PromoteDefaultAddress(ctx context.Context, _ *gorm.DB, userID, addressType string) error {
+	if m.promoteFn != nil {
+		return m.promoteFn(ctx, userID, addressType)
+	}
+	return nil
+}
+
+func (m *mockAddressRepo) DeleteAddress(ctx context.Context, _ *gorm.DB, addressID string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, addressID)
+	}
+	return nil
+}
+
+func existingUserRepo() *mockUserRepo {
+	return &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id)}, true, nil
+		},
+	}
+}
+
+// Unit Test
+
+func TestAddressService_CreateAddress_InvalidRequests(t *testing.T) {
+	as := NewAddressService(existingUserRepo(), &mockAddressRepo{})
+
+	cases := []struct {
+		name string
+		req  dto.CreateAddressRequest
+		want error
+	}{
+		{"unknown type", dto.CreateAddressRequest{Type: "office", Street: "Jl. Merdeka 1", City: "Jakarta", Country: "ID"}, constants.ErrInvalidAddressType},
+		{"missing city", dto.CreateAddressRequest{Type: constants.ENUM_ADDRESS_TYPE_HOME, Street: "Jl. Merdeka 1", Country: "ID"}, constants.ErrAddressRequired},
+		{"invalid country", dto.CreateAddressRequest{Type: constants.ENUM_ADDRESS_TYPE_HOME, Street: "Jl. Merdeka 1", City: "Jakarta", Country: "XX"}, constants.ErrInvalidCountryCode},
+		{"country name", dto.CreateAddressRequest{Type: constants.ENUM_ADDRESS_TYPE_HOME, Street: "Jl. Merdeka 1", City: "Jakarta", Country: "Indonesia"}, constants.ErrInvalidCountryCode},
+	}
+
+	for _, tc := range cases {
+		tc.req.UserID = uuid.NewString()
+		if _, err := as.CreateAddress(context.Background(), tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestAddressService_CreateAddress_FirstOfTypeIsDefault(t *testing.T) {
+	var created model.Address
+	unset := false
+
+	repo := &mockAddressRepo{
+		createFn: func(ctx context.Context, address model.Address) error {
+			created = address
+			return nil
+		},
+		unsetFn: func(ctx context.Context, userID, addressType string) error {
+			unset = true
+			return nil
+		},
+	}
+
+	as := NewAddressService(existingUserRepo(), repo)
+
+	resp, err := as.CreateAddress(context.Background(), dto.CreateAddressRequest{
+		UserID:  uuid.NewString(),
+		Type:    constants.ENUM_ADDRESS_TYPE_BILLING,
+		Street:  " Jl. Merdeka No. 1 ",
+		City:    "Jakarta",
+		Country: "id",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !resp.IsDefault || !created.IsDefault || !unset {
+		t.Fatalf("expected first billing address to become default")
+	}
+
+	if created.Country != "ID" || created.Street != "Jl. Merdeka No. 1" {
+		t.Fatalf("expected normalized address, got %+v", created)
+	}
+}
+
+func TestAddressService_CreateAddress_NotDefault(t *testing.T) {
+	repo := &mockAddressRepo{
+		countByTypeFn: func(ctx context.Context, userID, addressType string) (int64, error) {
+			return 1, nil
+		},
+		unsetFn: func(ctx context.Context, userID, addressType string) error {
+			t.Fatalf("expected the existing default to be kept")
+			return nil
+		},
+	}
+
+	as := NewAddressService(existingUserRepo(), repo)
+
+	resp, err := as.CreateAddress(context.Background(), dto.CreateAddressRequest{
+		UserID:  uuid.NewString(),
+		Type:    constants.ENUM_ADDRESS_TYPE_SHIPPING,
+		Street:  "Jl. Sudirman No. 2",
+		City:    "Bandung",
+		Country: "ID",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.IsDefault {
+		t.Fatalf("expected address not to be default")
+	}
+}
+
+func TestAddressService_UpdateAddress_NotFound(t *testing.T) {
+	as := NewAddressService(existingUserRepo(), &mockAddressRepo{})
+
+	city := "Surabaya"
+	_, err := as.UpdateAddress(context.Background(), dto.UpdateAddressRequest{
+		UserID:    uuid.NewString(),
+		AddressID: uuid.NewString(),
+		City:      &city,
+	})
+
+	if !errors.Is(err, constants.ErrAddressNotFound) {
+		t.Fatalf("expected ErrAddressNotFound, got %v", err)
+	}
+}
+
+func TestAddressService_DeleteAddress_PromotesNextDefault(t *testing.T) {
+	promoted := ""
+
+	repo := &mockAddressRepo{
+		getByIDFn: func(ctx context.Context, userID, addressID string) (model.Address, bool, error) {
+			return model.Address{
+				ID:        uuid.MustParse(addressID),
+				UserID:    uuid.MustParse(userID),
+				Type:      constants.ENUM_ADDRESS_TYPE_HOME,
+				IsDefault: true,
+			}, true, nil
+		},
+		promoteFn: func(ctx context.Context, userID, addressType string) error {
+			promoted = addressType
+			return nil
+		},
+	}
+
+	as := NewAddressService(existingUserRepo(), repo)
+
+	_, err := as.DeleteAddress(context.Background(), dto.DeleteAddressRequest{
+		UserID:    uuid.NewString(),
+		AddressID: uuid.NewString(),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if promoted != constants.ENUM_ADDRESS_TYPE_HOME {
+		t.Fatalf("expected another home address to be promoted")
+	}
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"github.com/mferdian/golang_boiller_plate/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	ICustomFieldService interface {
+		GetAllCustomField(ctx context.Context) ([]dto.CustomFieldResponse, error)
+		CreateCustomField(ctx context.Context, req dto.CreateCustomFieldRequest) (dto.CustomFieldResponse, error)
+		UpdateCustomField(ctx context.Context, req dto.UpdateCustomFieldRequest) (dto.CustomFieldResponse, error)
+		DeleteCustomField(ctx context.Context, req dto.DeleteCustomFieldRequest) (dto.CustomFieldResponse, error)
+	}
+
+	CustomFieldService struct {
+		customFieldRepo repository.ICustomFieldRepository
+	}
+)
+
+func NewCustomFieldService(customFieldRepo repository.ICustomFieldRepository) *CustomFieldService {
+	return &CustomFieldService{
+		customFieldRepo: customFieldRepo,
+	}
+}
+
+func customFieldOptions(field model.CustomField) []string {
+	var options []string
+	if err := json.Unmarshal([]byte(field.Options), &options); err != nil {
+		return nil
+	}
+	return options
+}
+
+func toCustomFieldResponse(field model.CustomField) dto.CustomFieldResponse {
+	return dto.CustomFieldResponse{
+		ID:        field.ID,
+		Key:       field.Key,
+		Label:     field.Label,
+		Type:      field.Type,
+		Required:  field.Required,
+		Pattern:   field.Pattern,
+		Options:   customFieldOptions(field),
+		CreatedAt: field.CreatedAt,
+		UpdatedAt: field.UpdatedAt,
+	}
+}
+
+// validateCustomField normalizes the definition in place and checks the
+// rules shared by create and update.
+func validateCustomField(field *model.CustomField, options []string) error {
+	field.Label = strings.TrimSpace(field.Label)
+	if field.Label == "" {
+		return constants.ErrCustomFieldLabelRequired
+	}
+
+	switch field.Type {
+	case constants.ENUM_CUSTOM_FIELD_TYPE_STRING, constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER,
+		constants.ENUM_CUSTOM_FIELD_TYPE_BOOLEAN, constants.ENUM_CUSTOM_FIELD_TYPE_DATE, constants.ENUM_CUSTOM_FIELD_TYPE_ENUM:
+	default:
+		return constants.ErrInvalidCustomFieldType
+	}
+
+	if _, err := regexp.Compile(field.Pattern); err != nil {
+		return fmt.Errorf("%w: %s", constants.ErrInvalidCustomFieldPattern, err)
+	}
+
+	if field.Type != constants.ENUM_CUSTOM_FIELD_TYPE_ENUM && len(options) > 0 {
+		return constants.ErrInvalidCustomFieldOptions
+	}
+
+	if field.Type == constants.ENUM_CUSTOM_FIELD_TYPE_ENUM {
+		if len(options) == 0 {
+			return constants.ErrInvalidCustomFieldOptions
+		}
+
+		seen := make(map[string]bool, len(options))
+		for _, option := range options {
+			if option == "" || seen[option] {
+				return constants.ErrInvalidCustomFieldOptions
+			}
+			seen[option] = true
+		}
+	}
+
+	if options == nil {
+		options = []string{}
+	}
+
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	field.Options = string(raw)
+
+	return nil
+}
+
+// canonicalCustomFieldValue checks a value against its field and returns the
+// text form it is stored and filtered as.
+func canonicalCustomFieldValue(field model.CustomField, value any) (string, error) {
+	invalid := fmt.Errorf("%w: %s must be a %s", constants.ErrInvalidCustomFieldValue, field.Key, field.Type)
+
+	var canonical string
+	switch field.Type {
+	case constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER:
+		number, ok := value.(float64)
+		if !ok {
+			return "", invalid
+		}
+		canonical = strconv.FormatFloat(number, 'f', -1, 64)
+	case constants.ENUM_CUSTOM_FIELD_TYPE_BOOLEAN:
+		boolean, ok := value.(bool)
+		if !ok {
+			return "", invalid
+		}
+		canonical = strconv.FormatBool(boolean)
+	case constants.ENUM_CUSTOM_FIELD_TYPE_DATE:
+		text, ok := value.(string)
+		if !ok {
+			return "", invalid
+		}
+		date, err := time.Parse(constants.ENUM_CUSTOM_FIELD_DATE_FORMAT, text)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a date formatted as YYYY-MM-DD", constants.ErrInvalidCustomFieldValue, field.Key)
+		}
+		canonical = date.Format(constants.ENUM_CUSTOM_FIELD_DATE_FORMAT)
+	case constants.ENUM_CUSTOM_FIELD_TYPE_ENUM:
+		text, ok := value.(string)
+		if !ok {
+			return "", invalid
+		}
+		allowed := false
+		for _, option := range customFieldOptions(field) {
+			if option == text {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", fmt.Errorf("%w: %s must be one of %s", constants.ErrInvalidCustomFieldValue, field.Key, strings.Join(customFieldOptions(field), ", "))
+		}
+		canonical = text
+	default:
+		text, ok := value.(string)
+		if !ok {
+			return "", invalid
+		}
+		canonical = text
+	}
+
+	if field.Pattern != "" {
+		pattern, err := regexp.Compile(field.Pattern)
+		if err != nil || !pattern.MatchString(canonical) {
+			return "", fmt.Errorf("%w: %s does not match the expected format", constants.ErrInvalidCustomFieldValue, field.Key)
+		}
+	}
+
+	return canonical, nil
+}
+
+// customFieldFilterValue turns a query string value into the canonical form
+// used by canonicalCustomFieldValue.
+func customFieldFilterValue(field model.CustomField, raw string) (string, error) {
+	var value any = raw
+
+	switch field.Type {
+	case constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a number", constants.ErrInvalidCustomFieldValue, field.Key)
+		}
+		value = number
+	case constants.ENUM_CUSTOM_FIELD_TYPE_BOOLEAN:
+		boolean, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a boolean", constants.ErrInvalidCustomFieldValue, field.Key)
+		}
+		value = boolean
+	}
+
+	return canonicalCustomFieldValue(field, value)
+}
+
+// typedCustomFieldValue converts a stored value back to its JSON type.
+func typedCustomFieldValue(field model.CustomField, stored string) any {
+	switch field.Type {
+	case constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER:
+		if number, err := strconv.ParseFloat(stored, 64); err == nil {
+			return number
+		}
+	case constants.ENUM_CUSTOM_FIELD_TYPE_BOOLEAN:
+		if boolean, err := strconv.ParseBool(stored); err == nil {
+			return boolean
+		}
+	}
+	return stored
+}
+
+// resolveCustomFieldValues validates the values sent for a user. A null or
+// empty value clears the field. When creating, every required field must be
+// given; when updating, only the fields sent are checked.
+func resolveCustomFieldValues(fields []model.CustomField, input map[string]any, creating bool) (map[uuid.UUID]string, []uuid.UUID, error) {
+	byKey := make(map[string]model.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	for key := range input {
+		if _, ok := byKey[key]; !ok {
+			return nil, nil, fmt.Errorf("%w: %s", constants.ErrUnknownCustomField, key)
+		}
+	}
+
+	save := make(map[uuid.UUID]string)
+	var cleared []uuid.UUID
+
+	for _, field := range fields {
+		value, sent := input[field.Key]
+		if text, ok := value.(string); ok && text == "" {
+			value = nil
+		}
+
+		if value == nil {
+			if field.Required && (sent || creating) {
+				return nil, nil, fmt.Errorf("%w: %s", constants.ErrCustomFieldRequired, field.Key)
+			}
+			if sent {
+				cleared = append(cleared, field.ID)
+			}
+			continue
+		}
+
+		canonical, err := canonicalCustomFieldValue(field, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		save[field.ID] = canonical
+	}
+
+	return save, cleared, nil
+}
+
+// customFieldMaps groups stored values by user as key to typed value.
+func customFieldMaps(values []model.UserCustomFieldValue) map[uuid.UUID]map[string]any {
+	result := make(map[uuid.UUID]map[string]any)
+	for _, value := range values {
+		if result[value.UserID] == nil {
+			result[value.UserID] = make(map[string]any)
+		}
+		result[value.UserID][value.Field.Key] = typedCustomFieldValue(value.Field, value.Value)
+	}
+	return result
+}
+
+func (cs *CustomFieldService) GetAllCustomField(ctx context.Context) ([]dto.CustomFieldResponse, error) {
+	fields, err := cs.customFieldRepo.GetAllCustomField(ctx, nil)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD)
+		return nil, constants.ErrGetAllCustomField
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_CUSTOM_FIELD+": %d fields", len(fields))
+
+	datas := make([]dto.CustomFieldResponse, 0, len(fields))
+	for _, field := range fields {
+		datas = append(datas, toCustomFieldResponse(field))
+	}
+
+	return datas, nil
+}
+
+func (cs *CustomFieldService) CreateCustomField(ctx context.Context, req dto.CreateCustomFieldRequest) (dto.CustomFieldResponse, error) {
+	if !helpers.IsValidCustomFieldKey(req.Key) {
+		logging.Log.Warn(constants.MESSAGE_FAILED_CREATE_CUSTOM_FIELD + ": invalid key")
+		return dto.CustomFieldResponse{}, constants.ErrInvalidCustomFieldKey
+	}
+
+	field := model.CustomField{
+		ID:       uuid.New(),
+		Key:      req.Key,
+		Label:    req.Label,
+		Type:     req.Type,
+		Required: req.Required,
+		Pattern:  req.Pattern,
+	}
+
+	if err := validateCustomField(&field, req.Options); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CREATE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, err
+	}
+
+	_, found, err := cs.customFieldRepo.GetCustomFieldByKey(ctx, nil, field.Key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, constants.ErrInternal
+	}
+	if found {
+		return dto.CustomFieldResponse{}, constants.ErrCustomFieldKeyExists
+	}
+
+	if err := cs.customFieldRepo.CreateCustomField(ctx, nil, field); err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, duplicateOr(err, constants.ErrCustomFieldKeyExists, constants.ErrCreateCustomField)
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_CUSTOM_FIELD+": %s", field.Key)
+
+	return toCustomFieldResponse(field), nil
+}
+
+// UpdateCustomField changes the definition only. Values stored earlier are
+// checked against the new rules the next time the user is updated.
+func (cs *CustomFieldService) UpdateCustomField(ctx context.Context, req dto.UpdateCustomFieldRequest) (dto.CustomFieldResponse, error) {
+	field, _, err := cs.customFieldRepo.GetCustomFieldByID(ctx, nil, req.FieldID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.FieldID).Warn(constants.MESSAGE_FAILED_UPDATE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, notFoundOr(err, constants.ErrCustomFieldNotFound, constants.ErrUpdateCustomField)
+	}
+
+	options := customFieldOptions(field)
+
+	if req.Label != nil {
+		field.Label = *req.Label
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.Pattern != nil {
+		field.Pattern = *req.Pattern
+	}
+	if req.Options != nil {
+		options = *req.Options
+	}
+
+	if err := validateCustomField(&field, options); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UPDATE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, err
+	}
+
+	field.UpdatedAt = time.Now()
+
+	if err := cs.customFieldRepo.UpdateCustomField(ctx, nil, field); err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, duplicateOr(err, constants.ErrCustomFieldKeyExists, constants.ErrUpdateCustomField)
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_CUSTOM_FIELD+": %s", field.Key)
+
+	return toCustomFieldResponse(field), nil
+}
+
+func (cs *CustomFieldService) DeleteCustomField(ctx context.Context, req dto.DeleteCustomFieldRequest) (dto.CustomFieldResponse, error) {
+	field, _, err := cs.customFieldRepo.GetCustomFieldByID(ctx, nil, req.FieldID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.FieldID).Warn(constants.MESSAGE_FAILED_DELETE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, notFoundOr(err, constants.ErrCustomFieldNotFound, constants.ErrDeleteCustomField)
+	}
+
+	if err := cs.customFieldRepo.DeleteCustomField(ctx, nil, req.FieldID); err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_DELETE_CUSTOM_FIELD)
+		return dto.CustomFieldResponse{}, constants.ErrDeleteCustomField
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_DELETE_CUSTOM_FIELD+": %s", field.Key)
+
+	return toCustomFieldResponse(field), nil
+}
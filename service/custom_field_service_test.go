@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type mockCustomFieldRepo struct {
+	createFn       func(ctx context.Context, field model.CustomField) error
+	getByIDFn      func(ctx context.Context, fieldID string) (model.CustomField, bool, error)
+	getByKeyFn     func(ctx context.Context, key string) (model.CustomField, bool, error)
+	getAllFn       func(ctx context.Context) ([]model.CustomField, error)
+	updateFn       func(ctx context.Context, field model.CustomField) error
+	deleteFn       func(ctx context.Context, fieldID string) error
+	getValuesFn    func(ctx context.Context, userIDs []string) ([]model.UserCustomFieldValue, error)
+	saveValuesFn   func(ctx context.Context, values []model.UserCustomFieldValue) error
+	deleteValuesFn func(ctx context.Context, userID string, fieldIDs []string) error
+}
+
+func (m *mockCustomFieldRepo) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return fn(tx)
+}
+
+func (m *mockCustomFieldRepo) CreateCustomField(ctx context.Context, _ *gorm.DB, field model.CustomField) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, field)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldRepo) GetCustomFieldByID(ctx context.Context, _ *gorm.DB, fieldID string) (model.CustomField, bool, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, fieldID)
+	}
+	return model.CustomField{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockCustomFieldRepo) GetCustomFieldByKey(ctx context.Context, _ *gorm.DB, key string) (model.CustomField, bool, error) {
+	if m.getByKeyFn != nil {
+		return m.getByKeyFn(ctx, key)
+	}
+	return model.CustomField{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockCustomFieldRepo) GetAllCustomField(ctx context.Context, _ *gorm.DB) ([]model.CustomField, error) {
+	if m.getAllFn != nil {
+		return m.getAllFn(ctx)
+	}
+	return []model.CustomField{}, nil
+}
+
+func (m *mockCustomFieldRepo) UpdateCustomField(ctx context.Context, _ *gorm.DB, field model.CustomField) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, field)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldRepo) DeleteCustomField(ctx context.Context, _ *gorm.DB, fieldID string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, fieldID)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldRepo) GetUserCustomFieldValues(ctx context.Context, _ *gorm.DB, userIDs []string) ([]model.UserCustomFieldValue, error) {
+	if m.getValuesFn != nil {
+		return m.getValuesFn(ctx, userIDs)
+	}
+	return []model.UserCustomFieldValue{}, nil
+}
+
+func (m *mockCustomFieldRepo) SaveUserCustomFieldValues(ctx context.Context, _ *gorm.DB, values []model.UserCustomFieldValue) error {
+	if m.saveValuesFn != nil {
+		return m.saveValuesFn(ctx, values)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldRepo) DeleteUserCustomFieldValues(ctx context.Context, _ *gorm.DB, userID string, fieldIDs []string) error {
+	if m.deleteValuesFn != nil {
+		return m.deleteValuesFn(ctx, userID, fieldIDs)
+	}
+	return nil
+}
+
+func employeeFields() []model.CustomField {
+	return []model.CustomField{
+		{ID: uuid.New(), Key: "employee_id", Label: "Employee ID", Type: constants.ENUM_CUSTOM_FIELD_TYPE_STRING, Required: true, Pattern: `^E\d{4}$`, Options: "[]"},
+		{ID: uuid.New(), Key: "department", Label: "Department", Type: constants.ENUM_CUSTOM_FIELD_TYPE_ENUM, Options: `["sales","engineering"]`},
+		{ID: uuid.New(), Key: "cost_centre", Label: "Cost centre", Type: constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER, Options: "[]"},
+	}
+}
+
+// Unit Test
+
+// CreateCustomField
+func TestCustomFieldService_CreateCustomField_Success(t *testing.T) {
+	var stored model.CustomField
+	cs := NewCustomFieldService(&mockCustomFieldRepo{
+		createFn: func(ctx context.Context, field model.CustomField) error {
+			stored = field
+			return nil
+		},
+	})
+
+	res, err := cs.CreateCustomField(context.Background(), dto.CreateCustomFieldRequest{
+		Key:     "department",
+		Label:   "Department",
+		Type:    constants.ENUM_CUSTOM_FIELD_TYPE_ENUM,
+		Options: []string{"sales", "engineering"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.Options != `["sales","engineering"]` || len(res.Options) != 2 {
+		t.Fatalf("expected options to be stored, got %q", stored.Options)
+	}
+}
+
+func TestCustomFieldService_CreateCustomField_InvalidDefinition(t *testing.T) {
+	cases := map[string]struct {
+		req dto.CreateCustomFieldRequest
+		err error
+	}{
+		"key":           {dto.CreateCustomFieldRequest{Key: "Employee ID", Label: "Employee ID", Type: "string"}, constants.ErrInvalidCustomFieldKey},
+		"type":          {dto.CreateCustomFieldRequest{Key: "employee_id", Label: "Employee ID", Type: "uuid"}, constants.ErrInvalidCustomFieldType},
+		"pattern":       {dto.CreateCustomFieldRequest{Key: "employee_id", Label: "Employee ID", Type: "string", Pattern: "("}, constants.ErrInvalidCustomFieldPattern},
+		"enum options":  {dto.CreateCustomFieldRequest{Key: "department", Label: "Department", Type: "enum"}, constants.ErrInvalidCustomFieldOptions},
+		"string option": {dto.CreateCustomFieldRequest{Key: "employee_id", Label: "Employee ID", Type: "string", Options: []string{"a"}}, constants.ErrInvalidCustomFieldOptions},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cs := NewCustomFieldService(&mockCustomFieldRepo{})
+
+			_, err := cs.CreateCustomField(context.Background(), tc.req)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestCustomFieldService_CreateCustomField_KeyExists(t *testing.T) {
+	cs := NewCustomFieldService(&mockCustomFieldRepo{
+		getByKeyFn: func(ctx context.Context, key string) (model.CustomField, bool, error) {
+			return model.CustomField{Key: key}, true, nil
+		},
+	})
+
+	_, err := cs.CreateCustomField(context.Background(), dto.CreateCustomFieldRequest{
+		Key:   "employee_id",
+		Label: "Employee ID",
+		Type:  constants.ENUM_CUSTOM_FIELD_TYPE_STRING,
+	})
+	if !errors.Is(err, constants.ErrCustomFieldKeyExists) {
+		t.Fatalf("expected ErrCustomFieldKeyExists, got %v", err)
+	}
+}
+
+// Custom field values
+func TestResolveCustomFieldValues_Create(t *testing.T) {
+	fields := employeeFields()
+
+	save, _, err := resolveCustomFieldValues(fields, map[string]any{
+		"employee_id": "E0042",
+		"department":  "sales",
+		"cost_centre": float64(410),
+	}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if save[fields[0].ID] != "E0042" || save[fields[1].ID] != "sales" || save[fields[2].ID] != "410" {
+		t.Fatalf("unexpected canonical values: %v", save)
+	}
+}
+
+func TestResolveCustomFieldValues_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		input    map[string]any
+		creating bool
+		err      error
+	}{
+		"missing required": {map[string]any{"department": "sales"}, true, constants.ErrCustomFieldRequired},
+		"clear required":   {map[string]any{"employee_id": nil}, false, constants.ErrCustomFieldRequired},
+		"unknown":          {map[string]any{"employee_id": "E0001", "badge": "x"}, true, constants.ErrUnknownCustomField},
+		"pattern":          {map[string]any{"employee_id": "42"}, false, constants.ErrInvalidCustomFieldValue},
+		"enum option":      {map[string]any{"department": "legal"}, false, constants.ErrInvalidCustomFieldValue},
+		"number type":      {map[string]any{"cost_centre": "410"}, false, constants.ErrInvalidCustomFieldValue},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := resolveCustomFieldValues(employeeFields(), tc.input, tc.creating)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestResolveCustomFieldValues_UpdateClearsOptional(t *testing.T) {
+	fields := employeeFields()
+
+	save, cleared, err := resolveCustomFieldValues(fields, map[string]any{"department": nil}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(save) != 0 || len(cleared) != 1 || cleared[0] != fields[1].ID {
+		t.Fatalf("expected department to be cleared only, got %v %v", save, cleared)
+	}
+}
+
+// User integration
+func TestUserService_CreateUser_RequiredCustomFieldMissing(t *testing.T) {
+	us := NewUserService(
+		&mockUserRepo{},
+		&mockCustomFieldRepo{
+			getAllFn: func(ctx context.Context) ([]model.CustomField, error) {
+				return employeeFields(), nil
+			},
+		},
+		&mockJWTService{},
+		&mockMailService{},
+	)
+
+	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
+		Name:     "Valid Name",
+		Email:    "new@mail.com",
+		Password: "password123",
+	})
+	if !errors.Is(err, constants.ErrCustomFieldRequired) {
+		t.Fatalf("expected ErrCustomFieldRequired, got %v", err)
+	}
+}
+
+func TestUserService_GetAllUserWithPagination_CustomFieldFilter(t *testing.T) {
+	var filters map[string]string
+	us := NewUserService(
+		&mockUserRepo{
+			getAllWithPaginationFn: func(ctx context.Context, _ *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+				filters = req.CustomFields
+				return dto.UserPaginationRepositoryResponse{}, nil
+			},
+		},
+		&mockCustomFieldRepo{
+			getAllFn: func(ctx context.Context) ([]model.CustomField, error) {
+				return employeeFields(), nil
+			},
+		},
+		&mockJWTService{},
+		&mockMailService{},
+	)
+
+	_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		CustomFields: map[string]string{"cost_centre": "410.0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if filters["cost_centre"] != "410" {
+		t.Fatalf("expected filter value in canonical form, got %v", filters)
+	}
+
+	_, err = us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		CustomFields: map[string]string{"badge": "x"},
+	})
+	if !errors.Is(err, constants.ErrUnknownCustomField) {
+		t.Fatalf("expected ErrUnknownCustomField, got %v", err)
+	}
+}
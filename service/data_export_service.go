@@ -0,0 +1,340 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"github.com/mferdian/golang_boiller_plate/repository"
+)
+
+type (
+	IDataExportService interface {
+		RequestDataExport(ctx context.Context, req dto.DataExportRequest) (dto.DataExportResponse, error)
+		GetDataExport(ctx context.Context, req dto.GetDataExportRequest) (dto.DataExportResponse, error)
+		CleanUpDataExports(ctx context.Context) (int, error)
+	}
+
+	DataExportService struct {
+		userRepo        repository.IUserRepository
+		dataExportRepo  repository.IDataExportRepository
+		addressRepo     repository.IAddressRepository
+		preferences     IUserPreferenceService
+		customFieldRepo repository.ICustomFieldRepository
+		exportDir       string
+		ttl             time.Duration
+		pendingTimeout  time.Duration
+	}
+
+	// dataExportSection produces the content of one JSON file in the archive.
+	dataExportSection struct {
+		fileName string
+		build    func(ctx context.Context, ds *DataExportService, user model.User) (any, error)
+	}
+)
+
+// dataExportSections lists everything we store about a user. Add an entry
+// here whenever a new table holds personal data.
+var dataExportSections = []dataExportSection{
+	{
+		fileName: "profile.json",
+		build: func(ctx context.Context, ds *DataExportService, user model.User) (any, error) {
+			return dto.DataExportProfile{
+				ID:                  user.ID,
+				Name:                user.Name,
+				Email:               user.Email,
+				PendingEmail:        user.PendingEmail,
+				EmailVerifiedAt:     user.EmailVerifiedAt,
+				PhoneNumber:         user.PhoneNumber,
+				Address:             user.Address,
+				Role:                user.Role,
+				LastLoginAt:         user.LastLoginAt,
+				LastLoginIP:         user.LastLoginIP,
+				DeletionRequestedAt: user.DeletionRequestedAt,
+				DeletionScheduledAt: user.DeletionScheduledAt,
+				CreatedAt:           user.CreatedAt,
+				UpdatedAt:           user.UpdatedAt,
+			}, nil
+		},
+	},
+	{
+		fileName: "addresses.json",
+		build: func(ctx context.Context, ds *DataExportService, user model.User) (any, error) {
+			addresses, err := ds.addressRepo.GetAddressesByUserID(ctx, nil, user.ID.String())
+			if err != nil {
+				return nil, err
+			}
+
+			datas := make([]dto.AddressResponse, 0, len(addresses))
+			for _, address := range addresses {
+				datas = append(datas, toAddressResponse(address))
+			}
+			return datas, nil
+		},
+	},
+	{
+		fileName: "preferences.json",
+		build: func(ctx context.Context, ds *DataExportService, user model.User) (any, error) {
+			return ds.preferences.GetEffectivePreferences(ctx, user.ID.String())
+		},
+	},
+	{
+		fileName: "custom_fields.json",
+		build: func(ctx context.Context, ds *DataExportService, user model.User) (any, error) {
+			values, err := ds.customFieldRepo.GetUserCustomFieldValues(ctx, nil, []string{user.ID.String()})
+			if err != nil {
+				return nil, err
+			}
+
+			customFields := customFieldMaps(values)[user.ID]
+			if customFields == nil {
+				customFields = map[string]any{}
+			}
+			return customFields, nil
+		},
+	},
+}
+
+func getDataExportDir() string {
+	dir := os.Getenv("DATA_EXPORT_DIR")
+	if dir == "" {
+		dir = constants.ENUM_DATA_EXPORT_DIR
+	}
+	return dir
+}
+
+func getDataExportTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("DATA_EXPORT_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = constants.ENUM_DATA_EXPORT_TTL_HOURS
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func getDataExportPendingTimeout() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("DATA_EXPORT_PENDING_TIMEOUT_MINUTE"))
+	if err != nil || minutes <= 0 {
+		minutes = constants.ENUM_DATA_EXPORT_PENDING_TIMEOUT_MINUTE
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func NewDataExportService(userRepo repository.IUserRepository, dataExportRepo repository.IDataExportRepository, addressRepo repository.IAddressRepository, preferences IUserPreferenceService, customFieldRepo repository.ICustomFieldRepository) *DataExportService {
+	return &DataExportService{
+		userRepo:        userRepo,
+		dataExportRepo:  dataExportRepo,
+		addressRepo:     addressRepo,
+		preferences:     preferences,
+		customFieldRepo: customFieldRepo,
+		exportDir:       getDataExportDir(),
+		ttl:             getDataExportTTL(),
+		pendingTimeout:  getDataExportPendingTimeout(),
+	}
+}
+
+func (ds *DataExportService) RequestDataExport(ctx context.Context, req dto.DataExportRequest) (dto.DataExportResponse, error) {
+	user, _, err := ds.userRepo.GetUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Error(constants.MESSAGE_FAILED_REQUEST_DATA_EXPORT)
+		return dto.DataExportResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	requestedBy, err := uuid.Parse(req.RequestedBy)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_REQUEST_DATA_EXPORT)
+		return dto.DataExportResponse{}, constants.ErrGetIDFromToken
+	}
+
+	export := model.DataExport{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		RequestedBy: requestedBy,
+		Status:      constants.ENUM_DATA_EXPORT_STATUS_PENDING,
+	}
+
+	err = ds.dataExportRepo.CreateDataExport(ctx, nil, export)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_REQUEST_DATA_EXPORT)
+		return dto.DataExportResponse{}, constants.ErrCreateDataExport
+	}
+
+	// The archive is assembled after the response is sent, so it must not
+	// depend on the request context.
+	go ds.buildDataExport(context.Background(), export, user)
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_REQUEST_DATA_EXPORT+": %s", export.ID)
+
+	return dto.DataExportResponse{
+		ID:     export.ID,
+		UserID: export.UserID,
+		Status: export.Status,
+	}, nil
+}
+
+func (ds *DataExportService) GetDataExport(ctx context.Context, req dto.GetDataExportRequest) (dto.DataExportResponse, error) {
+	export, _, err := ds.dataExportRepo.GetDataExportByID(ctx, nil, req.ExportID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.ExportID).Warn(constants.MESSAGE_FAILED_GET_DATA_EXPORT)
+		return dto.DataExportResponse{}, notFoundOr(err, constants.ErrDataExportNotFound, constants.ErrInternal)
+	}
+
+	if export.UserID.String() != req.UserID {
+		logging.Log.WithField("id", req.ExportID).Warn(constants.MESSAGE_FAILED_GET_DATA_EXPORT + ": export belongs to another user")
+		return dto.DataExportResponse{}, constants.ErrDataExportNotFound
+	}
+
+	if export.Status == constants.ENUM_DATA_EXPORT_STATUS_FAILED {
+		return dto.DataExportResponse{}, constants.ErrDataExportFailed
+	}
+
+	if export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt) {
+		logging.Log.Warnf(constants.MESSAGE_FAILED_GET_DATA_EXPORT+": %s expired", export.ID)
+		if export.FilePath != "" {
+			_ = os.Remove(export.FilePath)
+		}
+		return dto.DataExportResponse{}, constants.ErrDataExportExpired
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_DATA_EXPORT+": %s", export.ID)
+
+	return dto.DataExportResponse{
+		ID:          export.ID,
+		UserID:      export.UserID,
+		Status:      export.Status,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		FilePath:    export.FilePath,
+	}, nil
+}
+
+// CleanUpDataExports deletes expired archives together with their rows, so
+// personal data does not stay on disk when nobody downloads it, and fails
+// exports whose build never finished so the user can request a new one. It
+// returns how many expired exports were deleted.
+func (ds *DataExportService) CleanUpDataExports(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	failed, err := ds.dataExportRepo.FailStaleDataExports(ctx, nil, now.Add(-ds.pendingTimeout), "data export did not finish in time")
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CLEAN_UP_DATA_EXPORT)
+		return 0, constants.ErrCleanUpDataExport
+	}
+	if failed > 0 {
+		logging.Log.Warnf(constants.MESSAGE_FAILED_BUILD_DATA_EXPORT+": %d export(s) timed out", failed)
+	}
+
+	exports, err := ds.dataExportRepo.GetExpiredDataExports(ctx, nil, now, constants.ENUM_DATA_EXPORT_CLEANUP_BATCH_SIZE)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CLEAN_UP_DATA_EXPORT)
+		return 0, constants.ErrCleanUpDataExport
+	}
+
+	deleted := 0
+	for _, export := range exports {
+		// The file goes first: if it cannot be removed the row stays, and
+		// the next run tries again.
+		if export.FilePath != "" {
+			if err := os.Remove(export.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				logging.Log.WithError(err).WithField("id", export.ID).Error(constants.MESSAGE_FAILED_REMOVE_DATA_EXPORT)
+				continue
+			}
+		}
+
+		if err := ds.dataExportRepo.DeleteDataExportByID(ctx, nil, export.ID.String()); err != nil {
+			logging.Log.WithError(err).WithField("id", export.ID).Error(constants.MESSAGE_FAILED_CLEAN_UP_DATA_EXPORT)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (ds *DataExportService) buildDataExport(ctx context.Context, export model.DataExport, user model.User) {
+	filePath, err := ds.writeDataExportArchive(ctx, export, user)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", export.ID).Error(constants.MESSAGE_FAILED_BUILD_DATA_EXPORT)
+		export.Status = constants.ENUM_DATA_EXPORT_STATUS_FAILED
+		export.Error = err.Error()
+	} else {
+		completedAt := time.Now()
+		expiresAt := completedAt.Add(ds.ttl)
+		export.Status = constants.ENUM_DATA_EXPORT_STATUS_READY
+		export.FilePath = filePath
+		export.CompletedAt = &completedAt
+		export.ExpiresAt = &expiresAt
+	}
+
+	if err := ds.dataExportRepo.UpdateDataExport(ctx, nil, export); err != nil {
+		logging.Log.WithError(err).WithField("id", export.ID).Error(constants.MESSAGE_FAILED_BUILD_DATA_EXPORT)
+		return
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_BUILD_DATA_EXPORT+": %s (%s)", export.ID, export.Status)
+}
+
+func (ds *DataExportService) writeDataExportArchive(ctx context.Context, export model.DataExport, user model.User) (string, error) {
+	if err := os.MkdirAll(ds.exportDir, 0o700); err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(ds.exportDir, export.ID.String()+".zip")
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+
+	archive := zip.NewWriter(file)
+
+	manifest := dto.DataExportManifest{
+		UserID:      user.ID,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	for _, section := range dataExportSections {
+		data, err := section.build(ctx, ds, user)
+		if err != nil {
+			return "", errors.Join(err, archive.Close(), file.Close(), os.Remove(filePath))
+		}
+
+		if err := writeZipJSON(archive, section.fileName, data); err != nil {
+			return "", errors.Join(err, archive.Close(), file.Close(), os.Remove(filePath))
+		}
+		manifest.Files = append(manifest.Files, section.fileName)
+	}
+
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		return "", errors.Join(err, archive.Close(), file.Close(), os.Remove(filePath))
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", errors.Join(err, file.Close(), os.Remove(filePath))
+	}
+
+	if err := file.Close(); err != nil {
+		return "", errors.Join(err, os.Remove(filePath))
+	}
+
+	return filePath, nil
+}
+
+func writeZipJSON(archive *zip.Writer, name string, data any) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
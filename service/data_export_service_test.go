@@ -0,0 +1,257 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type mockDataExportRepo struct {
+	createFn  func(ctx context.Context, export model.DataExport) error
+	getByIDFn func(ctx context.Context, exportID string) (model.DataExport, bool, error)
+	updateFn  func(ctx context.Context, export model.DataExport) error
+
+	getExpiredFn func(ctx context.Context, now time.Time, limit int) ([]model.DataExport, error)
+	deleteFn     func(ctx context.Context, exportID string) error
+	failStaleFn  func(ctx context.Context, createdBefore time.Time, reason string) (int64, error)
+}
+
+func (m *mockDataExportRepo) CreateDataExport(ctx context.Context, _ *gorm.DB, export model.DataExport) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, export)
+	}
+	return nil
+}
+
+func (m *mockDataExportRepo) GetDataExportByID(ctx context.Context, _ *gorm.DB, exportID string) (model.DataExport, bool, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, exportID)
+	}
+	return model.DataExport{}, false, nil
+}
+
+func (m *mockDataExportRepo) UpdateDataExport(ctx context.Context, _ *gorm.DB, export model.DataExport) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, export)
+	}
+	return nil
+}
+
+func (m *mockDataExportRepo) GetExpiredDataExports(ctx context.Context, _ *gorm.DB, now time.Time, limit int) ([]model.DataExport, error) {
+	if m.getExpiredFn != nil {
+		return m.getExpiredFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockDataExportRepo) DeleteDataExportByID(ctx context.Context, _ *gorm.DB, exportID string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, exportID)
+	}
+	return nil
+}
+
+func (m *mockDataExportRepo) FailStaleDataExports(ctx context.Context, _ *gorm.DB, createdBefore time.Time, reason string) (int64, error) {
+	if m.failStaleFn != nil {
+		return m.failStaleFn(ctx, createdBefore, reason)
+	}
+	return 0, nil
+}
+
+// Unit Test
+
+func TestDataExportService_GetDataExport_OtherUser(t *testing.T) {
+	repo := &mockDataExportRepo{
+		getByIDFn: func(ctx context.Context, exportID string) (model.DataExport, bool, error) {
+			return model.DataExport{
+				ID:     uuid.MustParse(exportID),
+				UserID: uuid.New(),
+				Status: constants.ENUM_DATA_EXPORT_STATUS_READY,
+			}, true, nil
+		},
+	}
+
+	ds := NewDataExportService(&mockUserRepo{}, repo, &mockAddressRepo{}, NewUserPreferenceService(&mockUserRepo{}, &mockUserPreferenceRepo{}), &mockCustomFieldRepo{})
+
+	_, err := ds.GetDataExport(context.Background(), dto.GetDataExportRequest{
+		UserID:   uuid.NewString(),
+		ExportID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrDataExportNotFound) {
+		t.Fatalf("expected ErrDataExportNotFound, got %v", err)
+	}
+}
+
+func TestDataExportService_GetDataExport_Expired(t *testing.T) {
+	userID := uuid.New()
+	expiresAt := time.Now().Add(-time.Minute)
+
+	repo := &mockDataExportRepo{
+		getByIDFn: func(ctx context.Context, exportID string) (model.DataExport, bool, error) {
+			return model.DataExport{
+				ID:        uuid.MustParse(exportID),
+				UserID:    userID,
+				Status:    constants.ENUM_DATA_EXPORT_STATUS_READY,
+				ExpiresAt: &expiresAt,
+			}, true, nil
+		},
+	}
+
+	ds := NewDataExportService(&mockUserRepo{}, repo, &mockAddressRepo{}, NewUserPreferenceService(&mockUserRepo{}, &mockUserPreferenceRepo{}), &mockCustomFieldRepo{})
+
+	_, err := ds.GetDataExport(context.Background(), dto.GetDataExportRequest{
+		UserID:   userID.String(),
+		ExportID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrDataExportExpired) {
+		t.Fatalf("expected ErrDataExportExpired, got %v", err)
+	}
+}
+
+func TestDataExportService_BuildDataExport_WritesArchive(t *testing.T) {
+	var updated model.DataExport
+
+	repo := &mockDataExportRepo{
+		updateFn: func(ctx context.Context, export model.DataExport) error {
+			updated = export
+			return nil
+		},
+	}
+
+	ds := NewDataExportService(&mockUserRepo{}, repo, &mockAddressRepo{}, NewUserPreferenceService(&mockUserRepo{}, &mockUserPreferenceRepo{}), &mockCustomFieldRepo{})
+	ds.exportDir = t.TempDir()
+
+	user := model.User{
+		ID:       uuid.New(),
+		Name:     "Som User",
+		Email:    "test@mail.com",
+		Password: "hashed-password",
+	}
+
+	ds.buildDataExport(context.Background(), model.DataExport{ID: uuid.New(), UserID: user.ID}, user)
+
+	if updated.Status != constants.ENUM_DATA_EXPORT_STATUS_READY {
+		t.Fatalf("expected status ready, got %q (%s)", updated.Status, updated.Error)
+	}
+
+	if updated.ExpiresAt == nil {
+		t.Fatalf("expected expires_at to be set")
+	}
+
+	archive, err := zip.OpenReader(updated.FilePath)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer archive.Close()
+
+	var profile map[string]any
+	for _, file := range archive.File {
+		if file.Name != "profile.json" {
+			continue
+		}
+
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open profile.json: %v", err)
+		}
+		if err := json.NewDecoder(r).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode profile.json: %v", err)
+		}
+		r.Close()
+	}
+
+	if profile["email"] != user.Email {
+		t.Fatalf("expected profile email %s, got %v", user.Email, profile["email"])
+	}
+
+	if _, ok := profile["password"]; ok {
+		t.Fatalf("expected password not to be exported")
+	}
+}
+
+func TestDataExportService_CleanUpDataExports_RemovesExpiredArchives(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "expired.zip")
+	if err := os.WriteFile(filePath, []byte("zip"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	expired := model.DataExport{ID: uuid.New(), Status: constants.ENUM_DATA_EXPORT_STATUS_READY, FilePath: filePath}
+	var (
+		staleCutoff time.Time
+		deletedIDs  []string
+	)
+
+	repo := &mockDataExportRepo{
+		failStaleFn: func(ctx context.Context, createdBefore time.Time, reason string) (int64, error) {
+			staleCutoff = createdBefore
+			return 1, nil
+		},
+		getExpiredFn: func(ctx context.Context, now time.Time, limit int) ([]model.DataExport, error) {
+			return []model.DataExport{expired}, nil
+		},
+		deleteFn: func(ctx context.Context, exportID string) error {
+			deletedIDs = append(deletedIDs, exportID)
+			return nil
+		},
+	}
+
+	ds := NewDataExportService(&mockUserRepo{}, repo, &mockAddressRepo{}, NewUserPreferenceService(&mockUserRepo{}, &mockUserPreferenceRepo{}), &mockCustomFieldRepo{})
+	ds.pendingTimeout = 30 * time.Minute
+
+	deleted, err := ds.CleanUpDataExports(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if deleted != 1 || len(deletedIDs) != 1 || deletedIDs[0] != expired.ID.String() {
+		t.Fatalf("expected the expired export to be deleted, got %d (%v)", deleted, deletedIDs)
+	}
+
+	if _, err := os.Stat(filePath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the archive to be removed, got %v", err)
+	}
+
+	if age := time.Since(staleCutoff); age < 30*time.Minute || age > 31*time.Minute {
+		t.Fatalf("expected pending exports older than the timeout to be failed, cutoff was %s ago", age)
+	}
+}
+
+func TestDataExportService_CleanUpDataExports_KeepsRowWhenFileRemains(t *testing.T) {
+	// A directory where the archive should be cannot be removed with
+	// os.Remove while it has content.
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "locked.zip")
+	if err := os.MkdirAll(filepath.Join(filePath, "inner"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &mockDataExportRepo{
+		getExpiredFn: func(ctx context.Context, now time.Time, limit int) ([]model.DataExport, error) {
+			return []model.DataExport{{ID: uuid.New(), FilePath: filePath}}, nil
+		},
+		deleteFn: func(ctx context.Context, exportID string) error {
+			t.Fatalf("expected the row to stay while its archive remains")
+			return nil
+		},
+	}
+
+	ds := NewDataExportService(&mockUserRepo{}, repo, &mockAddressRepo{}, NewUserPreferenceService(&mockUserRepo{}, &mockUserPreferenceRepo{}), &mockCustomFieldRepo{})
+
+	deleted, err := ds.CleanUpDataExports(context.Background())
+	if err != nil || deleted != 0 {
+		t.Fatalf("expected nothing deleted, got %d, %v", deleted, err)
+	}
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"github.com/mferdian/golang_boiller_plate/repository"
+	"gorm.io/gorm"
+)
+
+type (
+	IInvitationService interface {
+		CreateInvitation(ctx context.Context, req dto.CreateInvitationRequest) (dto.InvitationResponse, error)
+		GetAllInvitationWithPagination(ctx context.Context, req dto.InvitationPaginationRequest) (dto.InvitationPaginationResponse, error)
+		ResendInvitation(ctx context.Context, req dto.ResendInvitationRequest) (dto.InvitationResponse, error)
+		RevokeInvitation(ctx context.Context, req dto.RevokeInvitationRequest) (dto.InvitationResponse, error)
+		AcceptInvitation(ctx context.Context, req dto.AcceptInvitationRequest) (dto.UserResponse, error)
+	}
+
+	InvitationService struct {
+		userRepo       repository.IUserRepository
+		invitationRepo repository.IInvitationRepository
+		mailService    IMailService
+		ttl            time.Duration
+		acceptURL      string
+	}
+)
+
+func getInvitationTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("INVITATION_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = constants.ENUM_INVITATION_TTL_HOURS
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// getInvitationAcceptURL points at the page where the invitee picks a name
+// and password, which then posts to /api/auth/accept-invite.
+func getInvitationAcceptURL() string {
+	url := os.Getenv("INVITATION_ACCEPT_URL")
+	if url == "" {
+		url = getAppURL() + "/accept-invite"
+	}
+	return url
+}
+
+func NewInvitationService(userRepo repository.IUserRepository, invitationRepo repository.IInvitationRepository, mailService IMailService) *InvitationService {
+	return &InvitationService{
+		userRepo:       userRepo,
+		invitationRepo: invitationRepo,
+		mailService:    mailService,
+		ttl:            getInvitationTTL(),
+		acceptURL:      getInvitationAcceptURL(),
+	}
+}
+
+func invitationStatus(invitation model.Invitation, now time.Time) string {
+	switch {
+	case invitation.AcceptedAt != nil:
+		return constants.ENUM_INVITATION_STATUS_ACCEPTED
+	case invitation.RevokedAt != nil:
+		return constants.ENUM_INVITATION_STATUS_REVOKED
+	case !invitation.ExpiresAt.After(now):
+		return constants.ENUM_INVITATION_STATUS_EXPIRED
+	default:
+		return constants.ENUM_INVITATION_STATUS_PENDING
+	}
+}
+
+func toInvitationResponse(invitation model.Invitation) dto.InvitationResponse {
+	return dto.InvitationResponse{
+		ID:         invitation.ID,
+		Email:      invitation.Email,
+		Role:       invitation.Role,
+		Status:     invitationStatus(invitation, time.Now()),
+		InvitedBy:  invitation.InvitedBy,
+		ExpiresAt:  invitation.ExpiresAt,
+		AcceptedAt: invitation.AcceptedAt,
+		RevokedAt:  invitation.RevokedAt,
+		UserID:     invitation.UserID,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
+
+func (is *InvitationService) sendInvitationMail(invitation model.Invitation, token string) error {
+	link := is.acceptURL + "?token=" + token
+
+	return is.mailService.SendMail(invitation.Email, "You have been invited", fmt.Sprintf(
+		"Hi,\n\nYou have been invited to create an account with the role %s.\n"+
+			"Choose your name and password by opening the link below before %s:\n\n%s\n\n"+
+			"The link can be used once. If you were not expecting this invitation, you can ignore this email.\n",
+		invitation.Role, invitation.ExpiresAt.UTC().Format(time.RFC1123), link,
+	))
+}
+
+func (is *InvitationService) CreateInvitation(ctx context.Context, req dto.CreateInvitationRequest) (dto.InvitationResponse, error) {
+	req.Email = strings.TrimSpace(req.Email)
+	if !helpers.IsValidEmail(req.Email) {
+		logging.Log.Warn(constants.MESSAGE_FAILED_CREATE_INVITATION + ": invalid email")
+		return dto.InvitationResponse{}, constants.ErrInvalidEmail
+	}
+
+	if req.Role == "" {
+		req.Role = constants.ENUM_ROLE_USER
+	}
+
+	if req.Role != constants.ENUM_ROLE_USER && req.Role != constants.ENUM_ROLE_ADMIN {
+		logging.Log.Warn(constants.MESSAGE_FAILED_CREATE_INVITATION + ": invalid role")
+		return dto.InvitationResponse{}, constants.ErrInvalidRole
+	}
+
+	invitedBy, err := uuid.Parse(req.InvitedBy)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CREATE_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrGetIDFromToken
+	}
+
+	_, found, err := is.userRepo.GetUserByEmail(ctx, nil, req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrInternal
+	}
+	if found {
+		return dto.InvitationResponse{}, constants.ErrEmailAlreadyExists
+	}
+
+	now := time.Now()
+
+	_, found, err = is.invitationRepo.GetPendingInvitationByEmail(ctx, nil, req.Email, now)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrInternal
+	}
+	if found {
+		return dto.InvitationResponse{}, constants.ErrInvitationAlreadyPending
+	}
+
+	token, err := helpers.GenerateToken()
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrGenerateToken
+	}
+
+	invitation := model.Invitation{
+		ID:        uuid.New(),
+		Email:     req.Email,
+		Role:      req.Role,
+		TokenHash: helpers.HashToken(token),
+		InvitedBy: invitedBy,
+		ExpiresAt: now.Add(is.ttl),
+		TimeStamp: model.TimeStamp{CreatedAt: now},
+	}
+
+	// The invitation is only kept when its email went out, otherwise nobody
+	// could ever accept it.
+	err = is.invitationRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := is.invitationRepo.CreateInvitation(ctx, tx, invitation); err != nil {
+			return err
+		}
+
+		if err := is.sendInvitationMail(invitation, token); err != nil {
+			logging.Log.WithError(err).WithField("email", invitation.Email).Error(constants.MESSAGE_FAILED_SEND_EMAIL)
+			return constants.ErrSendInvitation
+		}
+
+		return nil
+	})
+	if errors.Is(err, constants.ErrSendInvitation) {
+		return dto.InvitationResponse{}, err
+	}
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrCreateInvitation
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_INVITATION+": %s as %s by %s", invitation.Email, invitation.Role, invitation.InvitedBy)
+
+	return toInvitationResponse(invitation), nil
+}
+
+func (is *InvitationService) GetAllInvitationWithPagination(ctx context.Context, req dto.InvitationPaginationRequest) (dto.InvitationPaginationResponse, error) {
+	switch req.Status {
+	case "", constants.ENUM_INVITATION_STATUS_PENDING, constants.ENUM_INVITATION_STATUS_ACCEPTED,
+		constants.ENUM_INVITATION_STATUS_REVOKED, constants.ENUM_INVITATION_STATUS_EXPIRED:
+	default:
+		return dto.InvitationPaginationResponse{}, constants.ErrInvalidInvitationStatus
+	}
+
+	if err := repository.ValidatePagination(&req.PaginationRequest, repository.InvitationSortColumns); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_INVITATION)
+		return dto.InvitationPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateFilters(req.Filters, repository.InvitationFilterFields); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_INVITATION)
+		return dto.InvitationPaginationResponse{}, err
+	}
+
+	dataWithPaginate, err := is.invitationRepo.GetAllInvitationWithPagination(ctx, nil, req, time.Now())
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_INVITATION)
+		return dto.InvitationPaginationResponse{}, constants.ErrGetAllInvitation
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_INVITATION+": page %d", req.Page)
+
+	datas := make([]dto.InvitationResponse, 0, len(dataWithPaginate.Invitations))
+	for _, invitation := range dataWithPaginate.Invitations {
+		datas = append(datas, toInvitationResponse(invitation))
+	}
+
+	return dto.InvitationPaginationResponse{
+		Data:               datas,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
+	}, nil
+}
+
+// ResendInvitation issues a new token and expiry, so links from earlier
+// emails stop working.
+func (is *InvitationService) ResendInvitation(ctx context.Context, req dto.ResendInvitationRequest) (dto.InvitationResponse, error) {
+	invitation, _, err := is.invitationRepo.GetInvitationByID(ctx, nil, req.InvitationID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.InvitationID).Warn(constants.MESSAGE_FAILED_RESEND_INVITATION)
+		return dto.InvitationResponse{}, notFoundOr(err, constants.ErrInvitationNotFound, constants.ErrResendInvitation)
+	}
+
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return dto.InvitationResponse{}, constants.ErrInvitationNotPending
+	}
+
+	token, err := helpers.GenerateToken()
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_RESEND_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrGenerateToken
+	}
+
+	invitation.TokenHash = helpers.HashToken(token)
+	invitation.ExpiresAt = time.Now().Add(is.ttl)
+
+	err = is.invitationRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		err := is.invitationRepo.UpdateInvitationColumns(ctx, tx, req.InvitationID, map[string]any{
+			"token_hash": invitation.TokenHash,
+			"expires_at": invitation.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := is.sendInvitationMail(invitation, token); err != nil {
+			logging.Log.WithError(err).WithField("email", invitation.Email).Error(constants.MESSAGE_FAILED_SEND_EMAIL)
+			return constants.ErrSendInvitation
+		}
+
+		return nil
+	})
+	if errors.Is(err, constants.ErrSendInvitation) {
+		return dto.InvitationResponse{}, err
+	}
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_RESEND_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrResendInvitation
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_RESEND_INVITATION+": %s", invitation.ID)
+
+	return toInvitationResponse(invitation), nil
+}
+
+func (is *InvitationService) RevokeInvitation(ctx context.Context, req dto.RevokeInvitationRequest) (dto.InvitationResponse, error) {
+	invitation, _, err := is.invitationRepo.GetInvitationByID(ctx, nil, req.InvitationID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.InvitationID).Warn(constants.MESSAGE_FAILED_REVOKE_INVITATION)
+		return dto.InvitationResponse{}, notFoundOr(err, constants.ErrInvitationNotFound, constants.ErrRevokeInvitation)
+	}
+
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return dto.InvitationResponse{}, constants.ErrInvitationNotPending
+	}
+
+	now := time.Now()
+	err = is.invitationRepo.UpdateInvitationColumns(ctx, nil, req.InvitationID, map[string]any{
+		"revoked_at": now,
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_REVOKE_INVITATION)
+		return dto.InvitationResponse{}, constants.ErrRevokeInvitation
+	}
+
+	invitation.RevokedAt = &now
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_REVOKE_INVITATION+": %s", invitation.ID)
+
+	return toInvitationResponse(invitation), nil
+}
+
+func (is *InvitationService) AcceptInvitation(ctx context.Context, req dto.AcceptInvitationRequest) (dto.UserResponse, error) {
+	if req.Token == "" {
+		return dto.UserResponse{}, constants.ErrInvalidInvitationToken
+	}
+
+	invitation, _, err := is.invitationRepo.GetInvitationByTokenHash(ctx, nil, helpers.HashToken(req.Token))
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_ACCEPT_INVITATION + ": unknown token")
+		return dto.UserResponse{}, constants.ErrInvalidInvitationToken
+	}
+
+	switch invitationStatus(invitation, time.Now()) {
+	case constants.ENUM_INVITATION_STATUS_ACCEPTED, constants.ENUM_INVITATION_STATUS_REVOKED:
+		return dto.UserResponse{}, constants.ErrInvitationNotPending
+	case constants.ENUM_INVITATION_STATUS_EXPIRED:
+		return dto.UserResponse{}, constants.ErrInvitationExpired
+	}
+
+	if len(req.Name) < 5 {
+		logging.Log.Warn(constants.MESSAGE_FAILED_ACCEPT_INVITATION + ": name too short")
+		return dto.UserResponse{}, constants.ErrInvalidName
+	}
+
+	if len(req.Password) < 8 {
+		logging.Log.Warn(constants.MESSAGE_FAILED_ACCEPT_INVITATION + ": password too short")
+		return dto.UserResponse{}, constants.ErrInvalidPassword
+	}
+
+	// Someone may have registered with the address after the invite was sent.
+	_, found, err := is.userRepo.GetUserByEmail(ctx, nil, invitation.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_ACCEPT_INVITATION)
+		return dto.UserResponse{}, constants.ErrInternal
+	}
+	if found {
+		return dto.UserResponse{}, constants.ErrEmailAlreadyExists
+	}
+
+	// The token was mailed to the invited address, so owning it proves
+	// the address is theirs.
+	now := time.Now()
+	user := model.User{
+		ID:              uuid.New(),
+		Name:            req.Name,
+		Email:           invitation.Email,
+		Password:        req.Password,
+		Role:            invitation.Role,
+		Status:          constants.ENUM_USER_STATUS_ACTIVE,
+		EmailVerifiedAt: &now,
+	}
+
+	err = is.invitationRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		accepted, err := is.invitationRepo.MarkInvitationAccepted(ctx, tx, invitation.ID.String(), map[string]any{
+			"accepted_at": now,
+			"user_id":     user.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return constants.ErrInvitationNotPending
+		}
+
+		return is.userRepo.CreateUser(ctx, tx, user)
+	})
+	if errors.Is(err, constants.ErrInvitationNotPending) {
+		return dto.UserResponse{}, err
+	}
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_ACCEPT_INVITATION)
+		return dto.UserResponse{}, duplicateOr(err, constants.ErrEmailAlreadyExists, constants.ErrAcceptInvitation)
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_ACCEPT_INVITATION+": %s joined as %s", user.ID, user.Role)
+
+	return toUserResponse(user), nil
+}
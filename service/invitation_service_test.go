@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type mockInvitationRepo struct {
+	createFn         func(ctx context.Context, invitation model.Invitation) error
+	getByIDFn        func(ctx context.Context, invitationID string) (model.Invitation, bool, error)
+	getByTokenHashFn func(ctx context.Context, tokenHash string) (model.Invitation, bool, error)
+	getPendingFn     func(ctx context.Context, email string, now time.Time) (model.Invitation, bool, error)
+	getAllFn         func(ctx context.Context, req dto.InvitationPaginationRequest, now time.Time) (dto.InvitationPaginationRepositoryResponse, error)
+	updateColumnsFn  func(ctx context.Context, invitationID string, columns map[string]any) error
+	markAcceptedFn   func(ctx context.Context, invitationID string, columns map[string]any) (bool, error)
+}
+
+func (m *mockInvitationRepo) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return fn(tx)
+}
+
+func (m *mockInvitationRepo) CreateInvitation(ctx context.Context, _ *gorm.DB, invitation model.Invitation) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, invitation)
+	}
+	return nil
+}
+
+func (m *mockInvitationRepo) GetInvitationByID(ctx context.Context, _ *gorm.DB, invitationID string) (model.Invitation, bool, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, invitationID)
+	}
+	return model.Invitation{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockInvitationRepo) GetInvitationByTokenHash(ctx context.Context, _ *gorm.DB, tokenHash string) (model.Invitation, bool, error) {
+	if m.getByTokenHashFn != nil {
+		return m.getByTokenHashFn(ctx, tokenHash)
+	}
+	return model.Invitation{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockInvitationRepo) GetPendingInvitationByEmail(ctx context.Context, _ *gorm.DB, email string, now time.Time) (model.Invitation, bool, error) {
+	if m.getPendingFn != nil {
+		return m.getPendingFn(ctx, email, now)
+	}
+	return model.Invitation{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockInvitationRepo) GetAllInvitationWithPagination(ctx context.Context, _ *gorm.DB, req dto.InvitationPaginationRequest, now time.Time) (dto.InvitationPaginationRepositoryResponse, error) {
+	if m.getAllFn != nil {
+		return m.getAllFn(ctx, req, now)
+	}
+	return dto.InvitationPaginationRepositoryResponse{}, nil
+}
+
+func (m *mockInvitationRepo) UpdateInvitationColumns(ctx context.Context, _ *gorm.DB, invitationID string, columns map[string]any) error {
+	if m.updateColumnsFn != nil {
+		return m.updateColumnsFn(ctx, invitationID, columns)
+	}
+	return nil
+}
+
+func (m *mockInvitationRepo) MarkInvitationAccepted(ctx context.Context, _ *gorm.DB, invitationID string, columns map[string]any) (bool, error) {
+	if m.markAcceptedFn != nil {
+		return m.markAcceptedFn(ctx, invitationID, columns)
+	}
+	return true, nil
+}
+
+func pendingInvitation(token string) model.Invitation {
+	return model.Invitation{
+		ID:        uuid.New(),
+		Email:     "invitee@mail.com",
+		Role:      constants.ENUM_ROLE_USER,
+		TokenHash: helpers.HashToken(token),
+		InvitedBy: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// Unit Test
+
+// CreateInvitation
+func TestInvitationService_CreateInvitation_SendsMail(t *testing.T) {
+	var stored model.Invitation
+	var sentTo, sentBody string
+
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{
+			createFn: func(ctx context.Context, invitation model.Invitation) error {
+				stored = invitation
+				return nil
+			},
+		},
+		&mockMailService{
+			sendFn: func(to, subject, body string) error {
+				sentTo, sentBody = to, body
+				return nil
+			},
+		},
+	)
+
+	res, err := is.CreateInvitation(context.Background(), dto.CreateInvitationRequest{
+		Email:     "invitee@mail.com",
+		Role:      constants.ENUM_ROLE_ADMIN,
+		InvitedBy: uuid.NewString(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if res.Status != constants.ENUM_INVITATION_STATUS_PENDING || res.Role != constants.ENUM_ROLE_ADMIN {
+		t.Errorf("unexpected response: %+v", res)
+	}
+
+	if sentTo != "invitee@mail.com" {
+		t.Errorf("expected invitation mail to invitee, got %q", sentTo)
+	}
+
+	idx := strings.Index(sentBody, "?token=")
+	if idx < 0 {
+		t.Fatalf("expected accept link in mail body, got %q", sentBody)
+	}
+	token := strings.Fields(sentBody[idx+len("?token="):])[0]
+
+	if stored.TokenHash != helpers.HashToken(token) {
+		t.Errorf("expected only the hash of the mailed token to be stored")
+	}
+}
+
+func TestInvitationService_CreateInvitation_AlreadyPending(t *testing.T) {
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{
+			getPendingFn: func(ctx context.Context, email string, now time.Time) (model.Invitation, bool, error) {
+				return pendingInvitation("token"), true, nil
+			},
+		},
+		&mockMailService{},
+	)
+
+	_, err := is.CreateInvitation(context.Background(), dto.CreateInvitationRequest{
+		Email:     "invitee@mail.com",
+		InvitedBy: uuid.NewString(),
+	})
+	if !errors.Is(err, constants.ErrInvitationAlreadyPending) {
+		t.Errorf("expected ErrInvitationAlreadyPending, got %v", err)
+	}
+}
+
+func TestInvitationService_CreateInvitation_MailFails(t *testing.T) {
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{},
+		&mockMailService{
+			sendFn: func(to, subject, body string) error {
+				return errors.New("smtp down")
+			},
+		},
+	)
+
+	_, err := is.CreateInvitation(context.Background(), dto.CreateInvitationRequest{
+		Email:     "invitee@mail.com",
+		InvitedBy: uuid.NewString(),
+	})
+	if !errors.Is(err, constants.ErrSendInvitation) {
+		t.Errorf("expected ErrSendInvitation, got %v", err)
+	}
+}
+
+// ResendInvitation
+func TestInvitationService_ResendInvitation_RotatesToken(t *testing.T) {
+	invitation := pendingInvitation("old-token")
+	var columns map[string]any
+
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{
+			getByIDFn: func(ctx context.Context, invitationID string) (model.Invitation, bool, error) {
+				return invitation, true, nil
+			},
+			updateColumnsFn: func(ctx context.Context, invitationID string, c map[string]any) error {
+				columns = c
+				return nil
+			},
+		},
+		&mockMailService{},
+	)
+
+	_, err := is.ResendInvitation(context.Background(), dto.ResendInvitationRequest{InvitationID: invitation.ID.String()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if columns["token_hash"] == invitation.TokenHash {
+		t.Errorf("expected a new token hash to be stored")
+	}
+}
+
+func TestInvitationService_ResendInvitation_Revoked(t *testing.T) {
+	invitation := pendingInvitation("token")
+	revokedAt := time.Now()
+	invitation.RevokedAt = &revokedAt
+
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{
+			getByIDFn: func(ctx context.Context, invitationID string) (model.Invitation, bool, error) {
+				return invitation, true, nil
+			},
+		},
+		&mockMailService{},
+	)
+
+	_, err := is.ResendInvitation(context.Background(), dto.ResendInvitationRequest{InvitationID: invitation.ID.String()})
+	if !errors.Is(err, constants.ErrInvitationNotPending) {
+		t.Errorf("expected ErrInvitationNotPending, got %v", err)
+	}
+}
+
+// AcceptInvitation
+func TestInvitationService_AcceptInvitation_Success(t *testing.T) {
+	invitation := pendingInvitation("token")
+	invitation.Role = constants.ENUM_ROLE_ADMIN
+	var created model.User
+
+	is := NewInvitationService(
+		&mockUserRepo{
+			createFn: func(ctx context.Context, user model.User) error {
+				created = user
+				return nil
+			},
+		},
+		&mockInvitationRepo{
+			getByTokenHashFn: func(ctx context.Context, tokenHash string) (model.Invitation, bool, error) {
+				if tokenHash != invitation.TokenHash {
+					return model.Invitation{}, false, gorm.ErrRecordNotFound
+				}
+				return invitation, true, nil
+			},
+		},
+		&mockMailService{},
+	)
+
+	res, err := is.AcceptInvitation(context.Background(), dto.AcceptInvitationRequest{
+		Token:    "token",
+		Name:     "Invited User",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if created.Email != invitation.Email || created.Role != constants.ENUM_ROLE_ADMIN {
+		t.Errorf("expected user created with invitation email and role, got %+v", created)
+	}
+
+	if res.Email != invitation.Email {
+		t.Errorf("expected response email %s, got %s", invitation.Email, res.Email)
+	}
+}
+
+func TestInvitationService_AcceptInvitation_Expired(t *testing.T) {
+	invitation := pendingInvitation("token")
+	invitation.ExpiresAt = time.Now().Add(-time.Minute)
+
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{
+			getByTokenHashFn: func(ctx context.Context, tokenHash string) (model.Invitation, bool, error) {
+				return invitation, true, nil
+			},
+		},
+		&mockMailService{},
+	)
+
+	_, err := is.AcceptInvitation(context.Background(), dto.AcceptInvitationRequest{
+		Token:    "token",
+		Name:     "Invited User",
+		Password: "password123",
+	})
+	if !errors.Is(err, constants.ErrInvitationExpired) {
+		t.Errorf("expected ErrInvitationExpired, got %v", err)
+	}
+}
+
+func TestInvitationService_AcceptInvitation_AlreadyUsed(t *testing.T) {
+	invitation := pendingInvitation("token")
+
+	is := NewInvitationService(
+		&mockUserRepo{},
+		&mockInvitationRepo{
+			getByTokenHashFn: func(ctx context.Context, tokenHash string) (model.Invitation, bool, error) {
+				return invitation, true, nil
+			},
+			// Another request accepted the invitation first.
+			markAcceptedFn: func(ctx context.Context, invitationID string, columns map[string]any) (bool, error) {
+				return false, nil
+			},
+		},
+		&mockMailService{},
+	)
+
+	_, err := is.AcceptInvitation(context.Background(), dto.AcceptInvitationRequest{
+		Token:    "token",
+		Name:     "Invited User",
+		Password: "password123",
+	})
+	if !errors.Is(err, constants.ErrInvitationNotPending) {
+		t.Errorf("expected ErrInvitationNotPending, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+)
+
+type (
+	IMailService interface {
+		SendMail(to, subject, body string) error
+	}
+
+	MailService struct {
+		host         string
+		port         string
+		senderName   string
+		authEmail    string
+		authPassword string
+	}
+)
+
+func NewMailService() *MailService {
+	return &MailService{
+		host:         os.Getenv("SMTP_HOST"),
+		port:         os.Getenv("SMTP_PORT"),
+		senderName:   os.Getenv("SMTP_SENDER_NAME"),
+		authEmail:    os.Getenv("SMTP_AUTH_EMAIL"),
+		authPassword: os.Getenv("SMTP_AUTH_PASSWORD"),
+	}
+}
+
+// SendMail sends a plain text email. Recipients and subjects come from our
+// own code, but line breaks are still rejected to keep headers intact.
+func (ms *MailService) SendMail(to, subject, body string) error {
+	if ms.host == "" || ms.port == "" || ms.authEmail == "" {
+		return constants.ErrMailNotConfigured
+	}
+
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return constants.ErrInvalidEmail
+	}
+
+	from := ms.senderName
+	if from == "" {
+		from = ms.authEmail
+	}
+
+	message := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		from, to, subject, body,
+	)
+
+	auth := smtp.PlainAuth("", ms.authEmail, ms.authPassword, ms.host)
+	return smtp.SendMail(ms.host+":"+ms.port, auth, ms.authEmail, []string{to}, []byte(message))
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/helpers"
+	"github.com/mferdian/golang_boiller_plate/logging"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"github.com/mferdian/golang_boiller_plate/repository"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+)
+
+type (
+	// IUserPreferenceService is also the API other services use to read
+	// what a user chose, see GetEffectivePreferences.
+	IUserPreferenceService interface {
+		GetEffectivePreferences(ctx context.Context, userID string) (dto.UserPreferences, error)
+		GetUserPreferences(ctx context.Context, req dto.GetUserPreferencesRequest) (dto.UserPreferences, error)
+		UpdateUserPreferences(ctx context.Context, req dto.UpdateUserPreferencesRequest) (dto.UserPreferences, error)
+	}
+
+	UserPreferenceService struct {
+		userRepo       repository.IUserRepository
+		preferenceRepo repository.IUserPreferenceRepository
+	}
+)
+
+func NewUserPreferenceService(userRepo repository.IUserRepository, preferenceRepo repository.IUserPreferenceRepository) *UserPreferenceService {
+	return &UserPreferenceService{
+		userRepo:       userRepo,
+		preferenceRepo: preferenceRepo,
+	}
+}
+
+func defaultUserPreferences() dto.UserPreferences {
+	return dto.UserPreferences{
+		Locale:   constants.ENUM_DEFAULT_LOCALE,
+		Timezone: constants.ENUM_DEFAULT_TIMEZONE,
+		Theme:    constants.ENUM_THEME_SYSTEM,
+		Notifications: dto.NotificationPreferences{
+			Email:          true,
+			SecurityAlerts: true,
+			Marketing:      false,
+		},
+	}
+}
+
+// resolvePreferences lays overrides over the defaults. Unknown members and
+// values of the wrong type are rejected, so only documents matching
+// dto.UserPreferences are ever stored.
+func resolvePreferences(overrides map[string]any) (dto.UserPreferences, error) {
+	preferences := defaultUserPreferences()
+
+	raw, err := json.Marshal(overrides)
+	if err != nil {
+		return dto.UserPreferences{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&preferences); err != nil {
+		return dto.UserPreferences{}, fmt.Errorf("%w: %s", constants.ErrInvalidPreferences, err)
+	}
+
+	if _, err := language.Parse(preferences.Locale); err != nil {
+		return dto.UserPreferences{}, constants.ErrInvalidLocale
+	}
+
+	if _, err := time.LoadLocation(preferences.Timezone); err != nil || preferences.Timezone == "" || preferences.Timezone == "Local" {
+		return dto.UserPreferences{}, constants.ErrInvalidTimezone
+	}
+
+	switch preferences.Theme {
+	case constants.ENUM_THEME_LIGHT, constants.ENUM_THEME_DARK, constants.ENUM_THEME_SYSTEM:
+	default:
+		return dto.UserPreferences{}, constants.ErrInvalidTheme
+	}
+
+	return preferences, nil
+}
+
+func (ps *UserPreferenceService) getOverrides(ctx context.Context, userID string) (map[string]any, error) {
+	preference, _, err := ps.preferenceRepo.GetUserPreference(ctx, nil, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := map[string]any{}
+	if err := json.Unmarshal([]byte(preference.Overrides), &overrides); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// GetEffectivePreferences returns the preferences of the user with defaults
+// filled in. Users who never saved any get the defaults.
+func (ps *UserPreferenceService) GetEffectivePreferences(ctx context.Context, userID string) (dto.UserPreferences, error) {
+	overrides, err := ps.getOverrides(ctx, userID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", userID).Error(constants.MESSAGE_FAILED_GET_PREFERENCES)
+		return dto.UserPreferences{}, constants.ErrGetPreferences
+	}
+
+	preferences, err := resolvePreferences(overrides)
+	if err != nil {
+		// A stored document that no longer validates, for example after an
+		// option was removed, must not lock the user out of their settings.
+		logging.Log.WithError(err).WithField("id", userID).Warn(constants.MESSAGE_FAILED_GET_PREFERENCES + ": falling back to defaults")
+		return defaultUserPreferences(), nil
+	}
+
+	return preferences, nil
+}
+
+func (ps *UserPreferenceService) GetUserPreferences(ctx context.Context, req dto.GetUserPreferencesRequest) (dto.UserPreferences, error) {
+	if _, _, err := ps.userRepo.GetUserByID(ctx, nil, req.UserID); err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Warn(constants.MESSAGE_FAILED_GET_PREFERENCES)
+		return dto.UserPreferences{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	preferences, err := ps.GetEffectivePreferences(ctx, req.UserID)
+	if err != nil {
+		return dto.UserPreferences{}, err
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_PREFERENCES+": %s", req.UserID)
+
+	return preferences, nil
+}
+
+func (ps *UserPreferenceService) UpdateUserPreferences(ctx context.Context, req dto.UpdateUserPreferencesRequest) (dto.UserPreferences, error) {
+	user, _, err := ps.userRepo.GetUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Warn(constants.MESSAGE_FAILED_UPDATE_PREFERENCES)
+		return dto.UserPreferences{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	overrides, err := ps.getOverrides(ctx, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Error(constants.MESSAGE_FAILED_UPDATE_PREFERENCES)
+		return dto.UserPreferences{}, constants.ErrUpdatePreferences
+	}
+
+	overrides = helpers.MergePatch(overrides, req.Patch)
+
+	preferences, err := resolvePreferences(overrides)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UPDATE_PREFERENCES)
+		return dto.UserPreferences{}, err
+	}
+
+	raw, err := json.Marshal(overrides)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_PREFERENCES)
+		return dto.UserPreferences{}, constants.ErrUpdatePreferences
+	}
+
+	err = ps.preferenceRepo.SaveUserPreference(ctx, nil, model.UserPreference{
+		UserID:    user.ID,
+		Overrides: string(raw),
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_PREFERENCES)
+		return dto.UserPreferences{}, constants.ErrUpdatePreferences
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_PREFERENCES+": %s", req.UserID)
+
+	return preferences, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mferdian/golang_boiller_plate/constants"
+	"github.com/mferdian/golang_boiller_plate/dto"
+	"github.com/mferdian/golang_boiller_plate/model"
+	"gorm.io/gorm"
+)
+
+type mockUserPreferenceRepo struct {
+	getFn  func(ctx context.Context, userID string) (model.UserPreference, bool, error)
+	saveFn func(ctx context.Context, preference model.UserPreference) error
+}
+
+func (m *mockUserPreferenceRepo) GetUserPreference(ctx context.Context, _ *gorm.DB, userID string) (model.UserPreference, bool, error) {
+	if m.getFn != nil {
+		return m.getFn(ctx, userID)
+	}
+	return model.UserPreference{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockUserPreferenceRepo) SaveUserPreference(ctx context.Context, _ *gorm.DB, preference model.UserPreference) error {
+	if m.saveFn != nil {
+		return m.saveFn(ctx, preference)
+	}
+	return nil
+}
+
+// Unit Test
+
+func TestUserPreferenceService_GetEffectivePreferences_Defaults(t *testing.T) {
+	ps := NewUserPreferenceService(&mockUserRepo{}, &mockUserPreferenceRepo{})
+
+	preferences, err := ps.GetEffectivePreferences(context.Background(), uuid.NewString())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if preferences != defaultUserPreferences() {
+		t.Fatalf("expected defaults, got %+v", preferences)
+	}
+}
+
+func TestUserPreferenceService_UpdateUserPreferences_MergePatch(t *testing.T) {
+	var saved map[string]any
+
+	repo := &mockUserPreferenceRepo{
+		getFn: func(ctx context.Context, userID string) (model.UserPreference, bool, error) {
+			return model.UserPreference{
+				Overrides: `{"theme":"dark","timezone":"Asia/Jakarta","notifications":{"marketing":true}}`,
+			}, true, nil
+		},
+		saveFn: func(ctx context.Context, preference model.UserPreference) error {
+			return json.Unmarshal([]byte(preference.Overrides), &saved)
+		},
+	}
+
+	ps := NewUserPreferenceService(existingUserRepo(), repo)
+
+	preferences, err := ps.UpdateUserPreferences(context.Background(), dto.UpdateUserPreferencesRequest{
+		UserID: uuid.NewString(),
+		Patch: map[string]any{
+			"locale":        "id-ID",
+			"theme":         nil,
+			"notifications": map[string]any{"email": false},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := dto.UserPreferences{
+		Locale:   "id-ID",
+		Timezone: "Asia/Jakarta",
+		Theme:    constants.ENUM_THEME_SYSTEM,
+		Notifications: dto.NotificationPreferences{
+			Email:          false,
+			SecurityAlerts: true,
+			Marketing:      true,
+		},
+	}
+	if preferences != expected {
+		t.Fatalf("expected %+v, got %+v", expected, preferences)
+	}
+
+	if _, ok := saved["theme"]; ok {
+		t.Fatalf("expected null to remove the theme override, got %v", saved)
+	}
+}
+
+func TestUserPreferenceService_UpdateUserPreferences_Invalid(t *testing.T) {
+	ps := NewUserPreferenceService(existingUserRepo(), &mockUserPreferenceRepo{
+		saveFn: func(ctx context.Context, preference model.UserPreference) error {
+			t.Fatalf("expected invalid preferences not to be saved")
+			return nil
+		},
+	})
+
+	cases := []struct {
+		name  string
+		patch map[string]any
+		want  error
+	}{
+		{"unknown member", map[string]any{"font_size": 14}, constants.ErrInvalidPreferences},
+		{"wrong type", map[string]any{"notifications": map[string]any{"email": "yes"}}, constants.ErrInvalidPreferences},
+		{"invalid timezone", map[string]any{"timezone": "Mars/Olympus"}, constants.ErrInvalidTimezone},
+		{"invalid locale", map[string]any{"locale": "not a locale"}, constants.ErrInvalidLocale},
+		{"invalid theme", map[string]any{"theme": "pink"}, constants.ErrInvalidTheme},
+	}
+
+	for _, tc := range cases {
+		_, err := ps.UpdateUserPreferences(context.Background(), dto.UpdateUserPreferencesRequest{
+			UserID: uuid.NewString(),
+			Patch:  tc.patch,
+		})
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
@@ -1,7 +1,18 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mferdian/golang_boiller_plate/constants"
@@ -10,6 +21,7 @@ import (
 	"github.com/mferdian/golang_boiller_plate/logging"
 	"github.com/mferdian/golang_boiller_plate/model"
 	"github.com/mferdian/golang_boiller_plate/repository"
+	"gorm.io/gorm"
 )
 
 type (
@@ -19,22 +31,128 @@ type (
 
 		CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.UserResponse, error)
 		GetuserByID(ctx context.Context, userID string) (dto.UserResponse, error)
+		GetProfile(ctx context.Context, userID string) (dto.ProfileResponse, error)
 		GetAllUser(ctx context.Context, search string) ([]dto.UserResponse, error)
 		GetAllUserWithPagination(ctx context.Context, req dto.UserPaginationRequest) (dto.UserPaginationResponse, error)
 		UpdateUser(ctx context.Context, req dto.UpdateUserRequest) (dto.UserResponse, error)
+		ConfirmEmailChange(ctx context.Context, req dto.ConfirmEmailChangeRequest) (dto.UserResponse, error)
 		DeleteUser(ctx context.Context, req dto.DeleteUserRequest) (dto.UserResponse, error)
+
+		GetAllTrashedUserWithPagination(ctx context.Context, req dto.UserPaginationRequest) (dto.TrashedUserPaginationResponse, error)
+		RestoreUser(ctx context.Context, req dto.RestoreUserRequest) (dto.UserResponse, error)
+		PurgeUser(ctx context.Context, req dto.PurgeUserRequest) (dto.UserResponse, error)
+
+		RequestAccountDeletion(ctx context.Context, req dto.AccountDeletionRequest) (dto.AccountDeletionResponse, error)
+		EraseDueAccounts(ctx context.Context) (int, error)
+
+		ImportUsers(ctx context.Context, req dto.ImportUserRequest) (dto.ImportUserResponse, error)
+		ExportUsers(ctx context.Context, req dto.ExportUserRequest, w io.Writer) (int, error)
+
+		BulkUpdateUsers(ctx context.Context, req dto.BulkUpdateUserRequest) (dto.BulkUserResponse, error)
+		BulkDeleteUsers(ctx context.Context, req dto.BulkDeleteUserRequest) (dto.BulkUserResponse, error)
+
+		UpdateUserStatus(ctx context.Context, req dto.UpdateUserStatusRequest) (dto.UserStatusResponse, error)
+		CheckUserStatus(ctx context.Context, userID string) error
+		ReactivateExpiredSuspensions(ctx context.Context) (int64, error)
+
+		TouchLastSeen(ctx context.Context, userID string)
 	}
 
 	UserService struct {
-		userRepo   repository.IUserRepository
-		jwtService InterfaceJWTService
+		userRepo            repository.IUserRepository
+		customFieldRepo     repository.ICustomFieldRepository
+		jwtService          InterfaceJWTService
+		mailService         IMailService
+		deletionGracePeriod time.Duration
+		emailChangeTTL      time.Duration
+		appURL              string
+		phoneRegion         string
+		phoneUnique         bool
+		lastSeenThrottle    time.Duration
+		lastSeen            *helpers.TTLCache[string, struct{}]
+		userStatuses        *helpers.TTLCache[string, error]
+	}
+
+	// importRow is a checked import row, ready to be inserted.
+	importRow struct {
+		user              model.User
+		customFieldValues []model.UserCustomFieldValue
 	}
 )
 
-func NewUserService(userRepo repository.IUserRepository, jwtService InterfaceJWTService) *UserService {
+func getDeletionGracePeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"))
+	if err != nil || days < 0 {
+		days = constants.ENUM_ACCOUNT_DELETION_GRACE_DAYS
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func getEmailChangeTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("EMAIL_CHANGE_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = constants.ENUM_EMAIL_CHANGE_TTL_HOURS
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func getLastSeenThrottle() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("LAST_SEEN_THROTTLE_MINUTE"))
+	if err != nil || minutes <= 0 {
+		minutes = constants.ENUM_LAST_SEEN_THROTTLE_MINUTE
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func getUserStatusCacheTTL() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("USER_STATUS_CACHE_SECOND"))
+	if err != nil || seconds <= 0 {
+		seconds = constants.ENUM_USER_STATUS_CACHE_SECOND
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func getAppURL() string {
+	url := os.Getenv("APP_URL")
+	if url == "" {
+		url = constants.ENUM_APP_URL
+	}
+	return strings.TrimRight(url, "/")
+}
+
+func toUserResponse(user model.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Address:     user.Address,
+		Status:      user.Status,
+
+		PendingEmail: user.PendingEmail,
+
+		LastLoginAt: user.LastLoginAt,
+		LastLoginIP: user.LastLoginIP,
+		LastSeenAt:  user.LastSeenAt,
+	}
+}
+
+func NewUserService(userRepo repository.IUserRepository, customFieldRepo repository.ICustomFieldRepository, jwtService InterfaceJWTService, mailService IMailService) *UserService {
+	lastSeenThrottle := getLastSeenThrottle()
+
 	return &UserService{
-		userRepo:   userRepo,
-		jwtService: jwtService,
+		userRepo:            userRepo,
+		customFieldRepo:     customFieldRepo,
+		jwtService:          jwtService,
+		mailService:         mailService,
+		deletionGracePeriod: getDeletionGracePeriod(),
+		emailChangeTTL:      getEmailChangeTTL(),
+		appURL:              getAppURL(),
+		phoneRegion:         helpers.GetPhoneDefaultRegion(),
+		phoneUnique:         helpers.GetPhoneNumberUnique(),
+		lastSeenThrottle:    lastSeenThrottle,
+		lastSeen:            helpers.NewTTLCache[string, struct{}](lastSeenThrottle, constants.ENUM_LAST_SEEN_CACHE_SIZE),
+		userStatuses:        helpers.NewTTLCache[string, error](getUserStatusCacheTTL(), constants.ENUM_USER_STATUS_CACHE_SIZE),
 	}
 }
 
@@ -50,7 +168,7 @@ func (us *UserService) Register(ctx context.Context, req dto.RegisterUserRequest
 	}
 
 	_, found, err := us.userRepo.GetUserByEmail(ctx, nil, req.Email)
-	if err != nil {
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		logging.Log.Error("failed check email", err)
 		return dto.RegisterUserResponse{}, constants.ErrInternal
 	}
@@ -75,7 +193,7 @@ func (us *UserService) Register(ctx context.Context, req dto.RegisterUserRequest
 	err = us.userRepo.Register(ctx, nil, user)
 	if err != nil {
 		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_REGISTER)
-		return dto.RegisterUserResponse{}, constants.ErrRegisterUser
+		return dto.RegisterUserResponse{}, duplicateOr(err, constants.ErrEmailAlreadyExists, constants.ErrRegisterUser)
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_REGISTER+": %s", user.Email)
@@ -99,12 +217,31 @@ func (us *UserService) Login(ctx context.Context, req dto.LoginUserRequest) (dto
 		return dto.LoginResponse{}, constants.ErrInvalidLoginCredential
 	}
 
+	if err := us.checkAccountActive(ctx, user); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_LOGIN_USER + ": account not active")
+		return dto.LoginResponse{}, err
+	}
+
+	if user.DeletionScheduledAt != nil {
+		if err := us.userRepo.CancelUserDeletion(ctx, nil, user.ID.String()); err != nil {
+			logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_LOGIN_USER + ": failed cancel account deletion")
+			return dto.LoginResponse{}, constants.ErrCancelAccountDeletion
+		}
+
+		logging.Log.Infof(constants.MESSAGE_SUCCESS_CANCEL_DELETION+": %s", user.ID)
+	}
+
 	accessToken, refreshToken, err := us.jwtService.GenerateToken(user.ID.String(), user.Role)
 	if err != nil {
 		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_LOGIN_USER + ": failed generate token")
 		return dto.LoginResponse{}, constants.ErrGenerateAccessToken
 	}
 
+	// A failure here must not lock the user out, the login itself succeeded.
+	if err := us.userRepo.RecordUserLogin(ctx, nil, user.ID.String(), time.Now(), req.IP); err != nil {
+		logging.Log.WithError(err).WithField("id", user.ID).Warn(constants.MESSAGE_FAILED_RECORD_ACTIVITY)
+	}
+
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_LOGIN_USER+": %s", user.Email)
 
 	return dto.LoginResponse{
@@ -113,30 +250,167 @@ func (us *UserService) Login(ctx context.Context, req dto.LoginUserRequest) (dto
 	}, nil
 }
 
-func (us *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.UserResponse, error) {
-	if len(req.Name) < 5 {
+// validateNewUser holds the rules every admin-created user must pass, both
+// through CreateUser and through ImportUsers.
+func (us *UserService) validateNewUser(ctx context.Context, tx *gorm.DB, name, email, password string) error {
+	if len(name) < 5 {
 		logging.Log.Warn(constants.MESSAGE_FAILED_CREATE_USER + ": name too short")
-		return dto.UserResponse{}, constants.ErrInvalidName
+		return constants.ErrInvalidName
 	}
 
-	if !helpers.IsValidEmail(req.Email) {
+	if !helpers.IsValidEmail(email) {
 		logging.Log.Warn(constants.MESSAGE_FAILED_CREATE_USER + ": invalid email")
-		return dto.UserResponse{}, constants.ErrInvalidEmail
+		return constants.ErrInvalidEmail
 	}
 
-	_, found, err := us.userRepo.GetUserByEmail(ctx, nil, req.Email)
-	if err != nil {
+	_, found, err := us.userRepo.GetUserByEmail(ctx, tx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		logging.Log.Error("failed check email", err)
-		return dto.UserResponse{}, constants.ErrInternal
+		return constants.ErrInternal
 	}
 
 	if found {
-		return dto.UserResponse{}, constants.ErrEmailAlreadyExists
+		return constants.ErrEmailAlreadyExists
 	}
 
-	if len(req.Password) < 8 {
+	if len(password) < 8 {
 		logging.Log.Warn(constants.MESSAGE_FAILED_CREATE_USER + ": password too short")
-		return dto.UserResponse{}, constants.ErrInvalidPassword
+		return constants.ErrInvalidPassword
+	}
+
+	return nil
+}
+
+// normalizePhoneNumber returns phone in E.164 form and, when phone numbers
+// must be unique, checks that no user other than userID already has it.
+func (us *UserService) normalizePhoneNumber(ctx context.Context, tx *gorm.DB, phone string, userID uuid.UUID) (string, error) {
+	normalized, err := helpers.NormalizePhoneNumber(phone, us.phoneRegion)
+	if err != nil {
+		return "", err
+	}
+
+	if normalized == "" || !us.phoneUnique {
+		return normalized, nil
+	}
+
+	existingUser, found, err := us.userRepo.GetUserByPhoneNumber(ctx, tx, normalized)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).Error("failed check phone number")
+		return "", constants.ErrInternal
+	}
+
+	if found && existingUser.ID != userID {
+		return "", constants.ErrPhoneNumberAlreadyExists
+	}
+
+	return normalized, nil
+}
+
+// prepareCustomFieldValues validates the custom field values sent for a
+// user and returns the values to save and the field IDs to clear.
+func (us *UserService) prepareCustomFieldValues(ctx context.Context, userID uuid.UUID, input map[string]any, creating bool) ([]model.UserCustomFieldValue, []string, error) {
+	if len(input) == 0 && !creating {
+		return nil, nil, nil
+	}
+
+	fields, err := us.customFieldRepo.GetAllCustomField(ctx, nil)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD)
+		return nil, nil, constants.ErrGetAllCustomField
+	}
+
+	save, cleared, err := resolveCustomFieldValues(fields, input, creating)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]model.UserCustomFieldValue, 0, len(save))
+	for fieldID, value := range save {
+		values = append(values, model.UserCustomFieldValue{UserID: userID, FieldID: fieldID, Value: value})
+	}
+
+	clearedIDs := make([]string, 0, len(cleared))
+	for _, fieldID := range cleared {
+		clearedIDs = append(clearedIDs, fieldID.String())
+	}
+
+	return values, clearedIDs, nil
+}
+
+// loadCustomFields returns the custom field values of the users, keyed by
+// user, with one query.
+func (us *UserService) loadCustomFields(ctx context.Context, userIDs []string) (map[uuid.UUID]map[string]any, error) {
+	values, err := us.customFieldRepo.GetUserCustomFieldValues(ctx, nil, userIDs)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD)
+		return nil, constants.ErrGetCustomFieldValues
+	}
+
+	return customFieldMaps(values), nil
+}
+
+func (us *UserService) attachCustomFields(ctx context.Context, responses []dto.UserResponse) error {
+	userIDs := make([]string, 0, len(responses))
+	for _, response := range responses {
+		userIDs = append(userIDs, response.ID.String())
+	}
+
+	customFields, err := us.loadCustomFields(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range responses {
+		responses[i].CustomFields = customFields[responses[i].ID]
+	}
+
+	return nil
+}
+
+// resolveCustomFieldFilters turns custom_fields[key]=value filters into the
+// canonical values stored for each field.
+func (us *UserService) resolveCustomFieldFilters(ctx context.Context, filters map[string]string) (map[string]string, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	fields, err := us.customFieldRepo.GetAllCustomField(ctx, nil)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD)
+		return nil, constants.ErrGetAllCustomField
+	}
+
+	byKey := make(map[string]model.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	resolved := make(map[string]string, len(filters))
+	for key, raw := range filters {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", constants.ErrUnknownCustomField, key)
+		}
+
+		value, err := customFieldFilterValue(field, raw)
+		if err != nil {
+			return nil, err
+		}
+		resolved[key] = value
+	}
+
+	return resolved, nil
+}
+
+func (us *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest) (dto.UserResponse, error) {
+	if err := us.validateNewUser(ctx, nil, req.Name, req.Email, req.Password); err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	phoneNumber, err := us.normalizePhoneNumber(ctx, nil, req.PhoneNumber, uuid.Nil)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CREATE_USER + ": phone number")
+		return dto.UserResponse{}, err
 	}
 
 	user := model.User{
@@ -144,26 +418,37 @@ func (us *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest
 		Name:        req.Name,
 		Email:       req.Email,
 		Password:    req.Password,
-		PhoneNumber: req.PhoneNumber,
+		PhoneNumber: phoneNumber,
 		Address:     req.Address,
 		Role:        constants.ENUM_ROLE_ADMIN,
 	}
 
-	err = us.userRepo.CreateUser(ctx, nil, user)
+	customFieldValues, _, err := us.prepareCustomFieldValues(ctx, user.ID, req.CustomFields, true)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CREATE_USER + ": custom fields")
+		return dto.UserResponse{}, err
+	}
+
+	err = us.userRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := us.userRepo.CreateUser(ctx, tx, user); err != nil {
+			return err
+		}
+
+		return us.customFieldRepo.SaveUserCustomFieldValues(ctx, tx, customFieldValues)
+	})
 	if err != nil {
 		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CREATE_USER)
-		return dto.UserResponse{}, constants.ErrCreateUser
+		return dto.UserResponse{}, us.userConflictOr(ctx, nil, err, user, constants.ErrEmailAlreadyExists, constants.ErrCreateUser)
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_CREATE_USER+": %s", user.Email)
 
-	return dto.UserResponse{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
-		Address:     user.Address,
-	}, nil
+	res := []dto.UserResponse{toUserResponse(user)}
+	if err := us.attachCustomFields(ctx, res); err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	return res[0], nil
 }
 
 func (us *UserService) GetAllUser(ctx context.Context, search string) ([]dto.UserResponse, error) {
@@ -176,20 +461,49 @@ func (us *UserService) GetAllUser(ctx context.Context, search string) ([]dto.Use
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_USER)
 	var datas []dto.UserResponse
 	for _, user := range users {
-		data := dto.UserResponse{
-			ID:          user.ID,
-			Name:        user.Name,
-			Email:       user.Email,
-			PhoneNumber: user.PhoneNumber,
-			Address:     user.Address,
-		}
+		datas = append(datas, toUserResponse(user))
+	}
 
-		datas = append(datas, data)
+	if err := us.attachCustomFields(ctx, datas); err != nil {
+		return nil, err
 	}
+
 	return datas, nil
 }
 
 func (us *UserService) GetAllUserWithPagination(ctx context.Context, req dto.UserPaginationRequest) (dto.UserPaginationResponse, error) {
+	if req.InactiveDays < 0 {
+		logging.Log.Warn(constants.MESSAGE_FAILED_GET_LIST_USER + ": invalid inactive_days")
+		return dto.UserPaginationResponse{}, constants.ErrInvalidInactiveDays
+	}
+
+	if err := repository.ValidatePagination(&req.PaginationRequest, repository.UserSortColumns); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_USER)
+		return dto.UserPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateFilters(req.Filters, repository.UserFilterFields); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_USER)
+		return dto.UserPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateFields(req.FieldList(), repository.UserFields); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_USER)
+		return dto.UserPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateExpand(req.ExpandList(), repository.UserExpansions); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_USER)
+		return dto.UserPaginationResponse{}, err
+	}
+
+	customFieldFilters, err := us.resolveCustomFieldFilters(ctx, req.CustomFields)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_USER + ": custom field filter")
+		return dto.UserPaginationResponse{}, err
+	}
+	req.CustomFields = customFieldFilters
+
 	dataWithPaginate, err := us.userRepo.GetAllUserWithPagination(ctx, nil, req)
 	if err != nil {
 		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_USER)
@@ -200,23 +514,21 @@ func (us *UserService) GetAllUserWithPagination(ctx context.Context, req dto.Use
 
 	var datas []dto.UserResponse
 	for _, user := range dataWithPaginate.Users {
-		datas = append(datas, dto.UserResponse{
-			ID:          user.ID,
-			Name:        user.Name,
-			Email:       user.Email,
-			PhoneNumber: user.PhoneNumber,
-			Address:     user.Address,
-		})
+		data := toUserResponse(user)
+		data.Highlights = dataWithPaginate.Highlights[user.ID.String()]
+		data.Addresses = toAddressResponses(user.Addresses)
+		datas = append(datas, data)
+	}
+
+	if fields := req.FieldList(); len(fields) == 0 || slices.Contains(fields, "custom_fields") {
+		if err := us.attachCustomFields(ctx, datas); err != nil {
+			return dto.UserPaginationResponse{}, err
+		}
 	}
 
 	return dto.UserPaginationResponse{
-		Data: datas,
-		PaginationResponse: dto.PaginationResponse{
-			Page:    dataWithPaginate.Page,
-			PerPage: dataWithPaginate.PerPage,
-			MaxPage: dataWithPaginate.MaxPage,
-			Count:   dataWithPaginate.Count,
-		},
+		Data:               datas,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
 	}, nil
 }
 
@@ -229,17 +541,57 @@ func (us *UserService) GetuserByID(ctx context.Context, userID string) (dto.User
 	user, _, err := us.userRepo.GetUserByID(ctx, nil, userID)
 	if err != nil {
 		logging.Log.WithError(err).WithField("id", userID).Error(constants.MESSAGE_FAILED_GET_DETAIL_USER)
-		return dto.UserResponse{}, constants.ErrGetUserByID
+		return dto.UserResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	res := []dto.UserResponse{toUserResponse(user)}
+	if err := us.attachCustomFields(ctx, res); err != nil {
+		return dto.UserResponse{}, err
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_DETAIL_USER+": %s", userID)
 
-	return dto.UserResponse{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		Address:     user.Address,
-		PhoneNumber: user.PhoneNumber,
+	return res[0], nil
+}
+
+// GetProfile returns the detailed view of the user's own account.
+func (us *UserService) GetProfile(ctx context.Context, userID string) (dto.ProfileResponse, error) {
+	user, _, err := us.userRepo.GetUserByID(ctx, nil, userID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", userID).Error(constants.MESSAGE_FAILED_GET_PROFILE)
+		return dto.ProfileResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	customFields, err := us.loadCustomFields(ctx, []string{userID})
+	if err != nil {
+		return dto.ProfileResponse{}, err
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_PROFILE+": %s", userID)
+
+	return dto.ProfileResponse{
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		PendingEmail:    user.PendingEmail,
+		EmailVerified:   user.EmailVerifiedAt != nil,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PhoneNumber:     user.PhoneNumber,
+		Address:         user.Address,
+		Role:            user.Role,
+
+		Status:         user.Status,
+		StatusReason:   user.StatusReason,
+		SuspendedUntil: user.SuspendedUntil,
+
+		DeletionScheduledAt: user.DeletionScheduledAt,
+
+		LastLoginAt: user.LastLoginAt,
+
+		CustomFields: customFields[user.ID],
+
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
 	}, nil
 }
 
@@ -247,7 +599,7 @@ func (us *UserService) UpdateUser(ctx context.Context, req dto.UpdateUserRequest
 	user, _, err := us.userRepo.GetUserByID(ctx, nil, req.ID)
 	if err != nil {
 		logging.Log.WithError(err).WithField("id", req.ID).Error(constants.MESSAGE_FAILED_UPDATE_USER)
-		return dto.UserResponse{}, constants.ErrGetUserByID
+		return dto.UserResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
 	}
 
 	if req.Name != nil && len(*req.Name) < 5 {
@@ -257,7 +609,21 @@ func (us *UserService) UpdateUser(ctx context.Context, req dto.UpdateUserRequest
 		user.Name = *req.Name
 	}
 
-	if req.Email != nil {
+	// A new email is only stored as pending. It replaces the current one
+	// once the link sent to the new address is confirmed.
+	var emailChangeToken string
+	if req.Email != nil && *req.Email == user.Email && user.PendingEmail != "" {
+		err = us.userRepo.UpdateUserColumns(ctx, nil, user.ID.String(), map[string]any{
+			"pending_email":           "",
+			"email_change_token":      "",
+			"email_change_expires_at": nil,
+		})
+		if err != nil {
+			logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_USER + ": cancel email change")
+			return dto.UserResponse{}, constants.ErrUpdateUser
+		}
+		user.PendingEmail = ""
+	} else if req.Email != nil && *req.Email != user.Email {
 		if !helpers.IsValidEmail(*req.Email) {
 			logging.Log.Warn(constants.MESSAGE_FAILED_UPDATE_USER + ": invalid email format")
 			return dto.UserResponse{}, constants.ErrInvalidEmail
@@ -269,7 +635,16 @@ func (us *UserService) UpdateUser(ctx context.Context, req dto.UpdateUserRequest
 			return dto.UserResponse{}, constants.ErrEmailAlreadyExists
 		}
 
-		user.Email = *req.Email
+		emailChangeToken, err = helpers.GenerateToken()
+		if err != nil {
+			logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_USER + ": generate email change token")
+			return dto.UserResponse{}, constants.ErrGenerateToken
+		}
+
+		expiresAt := time.Now().Add(us.emailChangeTTL)
+		user.PendingEmail = *req.Email
+		user.EmailChangeToken = helpers.HashToken(emailChangeToken)
+		user.EmailChangeExpiresAt = &expiresAt
 	}
 
 	if req.Password != nil {
@@ -287,35 +662,136 @@ func (us *UserService) UpdateUser(ctx context.Context, req dto.UpdateUserRequest
 	}
 
 	if req.PhoneNumber != nil {
-		user.PhoneNumber = *req.PhoneNumber
+		phoneNumber, err := us.normalizePhoneNumber(ctx, nil, *req.PhoneNumber, user.ID)
+		if err != nil {
+			logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UPDATE_USER + ": phone number")
+			return dto.UserResponse{}, err
+		}
+		user.PhoneNumber = phoneNumber
 	}
 
 	if req.Address != nil {
 		user.Address = *req.Address
 	}
 
-	err = us.userRepo.UpdateUser(ctx, nil, user)
+	customFieldValues, clearedFieldIDs, err := us.prepareCustomFieldValues(ctx, user.ID, req.CustomFields, false)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_UPDATE_USER + ": custom fields")
+		return dto.UserResponse{}, err
+	}
+
+	err = us.userRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		if err := us.userRepo.UpdateUser(ctx, tx, user); err != nil {
+			return err
+		}
+
+		if err := us.customFieldRepo.SaveUserCustomFieldValues(ctx, tx, customFieldValues); err != nil {
+			return err
+		}
+
+		return us.customFieldRepo.DeleteUserCustomFieldValues(ctx, tx, user.ID.String(), clearedFieldIDs)
+	})
 	if err != nil {
 		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_USER)
-		return dto.UserResponse{}, constants.ErrUpdateUser
+		return dto.UserResponse{}, duplicateOr(err, constants.ErrPhoneNumberAlreadyExists, constants.ErrUpdateUser)
+	}
+
+	if emailChangeToken != "" {
+		if err := us.sendEmailChangeMails(user, emailChangeToken); err != nil {
+			logging.Log.WithError(err).WithField("id", user.ID).Error(constants.MESSAGE_FAILED_SEND_EMAIL)
+			return dto.UserResponse{}, constants.ErrSendEmailConfirmation
+		}
 	}
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_USER+": %s", user.ID)
 
-	return dto.UserResponse{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
-		Address:     user.Address,
-	}, nil
+	res := []dto.UserResponse{toUserResponse(user)}
+	if err := us.attachCustomFields(ctx, res); err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	return res[0], nil
+}
+
+// sendEmailChangeMails sends the confirmation link to the new address and
+// lets the current address know, so a change made from a stolen session
+// does not go unnoticed.
+func (us *UserService) sendEmailChangeMails(user model.User, token string) error {
+	link := us.appURL + "/api/confirm-email?token=" + token
+
+	err := us.mailService.SendMail(user.PendingEmail, "Confirm your new email address", fmt.Sprintf(
+		"Hi %s,\n\nWe received a request to change the email address of your account to this address.\n"+
+			"Confirm the change by opening the link below within %s:\n\n%s\n\n"+
+			"If you did not request this, you can ignore this email.\n",
+		user.Name, us.emailChangeTTL, link,
+	))
+	if err != nil {
+		return err
+	}
+
+	// The change is already waiting for confirmation, so a failed notice is
+	// logged rather than reported to the user.
+	err = us.mailService.SendMail(user.Email, "Your email address is being changed", fmt.Sprintf(
+		"Hi %s,\n\nA request was made to change the email address of your account to %s.\n"+
+			"Your current address stays active until the new one is confirmed.\n\n"+
+			"If you did not request this, change your password and update your email address to cancel it.\n",
+		user.Name, user.PendingEmail,
+	))
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", user.ID).Warn(constants.MESSAGE_FAILED_SEND_EMAIL + ": email change notice")
+	}
+
+	return nil
+}
+
+func (us *UserService) ConfirmEmailChange(ctx context.Context, req dto.ConfirmEmailChangeRequest) (dto.UserResponse, error) {
+	if req.Token == "" {
+		return dto.UserResponse{}, constants.ErrInvalidEmailChangeToken
+	}
+
+	user, _, err := us.userRepo.GetUserByEmailChangeToken(ctx, nil, helpers.HashToken(req.Token))
+	if err != nil || user.PendingEmail == "" {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_CONFIRM_EMAIL + ": unknown token")
+		return dto.UserResponse{}, constants.ErrInvalidEmailChangeToken
+	}
+
+	if user.EmailChangeExpiresAt == nil || time.Now().After(*user.EmailChangeExpiresAt) {
+		logging.Log.Warnf(constants.MESSAGE_FAILED_CONFIRM_EMAIL+": token expired for %s", user.ID)
+		return dto.UserResponse{}, constants.ErrEmailChangeTokenExpired
+	}
+
+	// The address may have been taken since the change was requested.
+	existingUser, found, err := us.userRepo.GetUserByEmail(ctx, nil, user.PendingEmail)
+	if err == nil && found && existingUser.ID != user.ID {
+		logging.Log.Warn(constants.MESSAGE_FAILED_CONFIRM_EMAIL + ": email already used by other user")
+		return dto.UserResponse{}, constants.ErrEmailAlreadyExists
+	}
+
+	err = us.userRepo.UpdateUserColumns(ctx, nil, user.ID.String(), map[string]any{
+		"email":                   user.PendingEmail,
+		"pending_email":           "",
+		"email_change_token":      "",
+		"email_change_expires_at": nil,
+		"email_verified_at":       time.Now(),
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_CONFIRM_EMAIL)
+		return dto.UserResponse{}, duplicateOr(err, constants.ErrEmailAlreadyExists, constants.ErrConfirmEmailChange)
+	}
+
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_CONFIRM_EMAIL+": %s", user.ID)
+
+	return toUserResponse(user), nil
 }
 
 func (us *UserService) DeleteUser(ctx context.Context, req dto.DeleteUserRequest) (dto.UserResponse, error) {
 	user, _, err := us.userRepo.GetUserByID(ctx, nil, req.UserID)
 	if err != nil {
 		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_DELETE_USER)
-		return dto.UserResponse{}, constants.ErrGetUserByID
+		return dto.UserResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
 	}
 
 	err = us.userRepo.DeleteUserByID(ctx, nil, req.UserID)
@@ -326,11 +802,1045 @@ func (us *UserService) DeleteUser(ctx context.Context, req dto.DeleteUserRequest
 
 	logging.Log.Infof(constants.MESSAGE_SUCCESS_DELETE_USER+": %s", req.UserID)
 
-	return dto.UserResponse{
-		ID:          user.ID,
-		Name:        user.Name,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
-		Address:     user.Address,
+	return toUserResponse(user), nil
+}
+
+func (us *UserService) GetAllTrashedUserWithPagination(ctx context.Context, req dto.UserPaginationRequest) (dto.TrashedUserPaginationResponse, error) {
+	if err := repository.ValidatePagination(&req.PaginationRequest, repository.TrashedUserSortColumns); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_TRASH_USER)
+		return dto.TrashedUserPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateFilters(req.Filters, repository.TrashedUserFilterFields); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_TRASH_USER)
+		return dto.TrashedUserPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateFields(req.FieldList(), repository.TrashedUserFields); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_TRASH_USER)
+		return dto.TrashedUserPaginationResponse{}, err
+	}
+
+	if err := repository.ValidateExpand(req.ExpandList(), repository.UserExpansions); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_GET_LIST_TRASH_USER)
+		return dto.TrashedUserPaginationResponse{}, err
+	}
+
+	dataWithPaginate, err := us.userRepo.GetAllTrashedUserWithPagination(ctx, nil, req)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_TRASH_USER)
+		return dto.TrashedUserPaginationResponse{}, constants.ErrGetAllTrashedUser
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_GET_LIST_TRASH_USER+": page %d", req.Page)
+
+	var datas []dto.TrashedUserResponse
+	for _, user := range dataWithPaginate.Users {
+		data := toUserResponse(user)
+		data.Highlights = dataWithPaginate.Highlights[user.ID.String()]
+		data.Addresses = toAddressResponses(user.Addresses)
+		datas = append(datas, dto.TrashedUserResponse{
+			UserResponse: data,
+			DeletedAt:    user.DeletedAt.Time,
+		})
+	}
+
+	return dto.TrashedUserPaginationResponse{
+		Data:               datas,
+		PaginationResponse: dataWithPaginate.PaginationResponse,
 	}, nil
 }
+
+func (us *UserService) RestoreUser(ctx context.Context, req dto.RestoreUserRequest) (dto.UserResponse, error) {
+	user, _, err := us.userRepo.GetTrashedUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Warn(constants.MESSAGE_FAILED_RESTORE_USER)
+		return dto.UserResponse{}, notFoundOr(err, constants.ErrUserNotInTrash, constants.ErrRestoreUser)
+	}
+
+	if user.AnonymizedAt != nil {
+		logging.Log.Warn(constants.MESSAGE_FAILED_RESTORE_USER + ": user already anonymized")
+		return dto.UserResponse{}, constants.ErrRestoreAnonymizedUser
+	}
+
+	// The email index only covers live rows, so someone may have registered
+	// the address again while this account sat in the trash.
+	_, found, err := us.userRepo.GetUserByEmail(ctx, nil, user.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_RESTORE_USER + ": check email")
+		return dto.UserResponse{}, constants.ErrRestoreUser
+	}
+	if found {
+		logging.Log.Warn(constants.MESSAGE_FAILED_RESTORE_USER + ": email already used by active user")
+		return dto.UserResponse{}, constants.ErrRestoreEmailConflict
+	}
+
+	err = us.userRepo.RestoreUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_RESTORE_USER)
+		return dto.UserResponse{}, us.userConflictOr(ctx, nil, err, user, constants.ErrRestoreEmailConflict, constants.ErrRestoreUser)
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_RESTORE_USER+": %s", req.UserID)
+
+	return toUserResponse(user), nil
+}
+
+func (us *UserService) PurgeUser(ctx context.Context, req dto.PurgeUserRequest) (dto.UserResponse, error) {
+	user, _, err := us.userRepo.GetTrashedUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Warn(constants.MESSAGE_FAILED_PURGE_USER)
+		return dto.UserResponse{}, notFoundOr(err, constants.ErrUserNotInTrash, constants.ErrPurgeUser)
+	}
+
+	archives, err := us.userRepo.PurgeUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_PURGE_USER)
+		return dto.UserResponse{}, notFoundOr(err, constants.ErrUserNotInTrash, constants.ErrPurgeUser)
+	}
+	removeDataExportArchives(archives)
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_PURGE_USER+": %s", req.UserID)
+
+	return toUserResponse(user), nil
+}
+
+// removeDataExportArchives deletes archive files whose rows are gone. A file
+// that cannot be removed is logged rather than failing the request, since
+// the data it belongs to has already been deleted.
+func removeDataExportArchives(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logging.Log.WithError(err).WithField("path", path).Error(constants.MESSAGE_FAILED_REMOVE_DATA_EXPORT)
+		}
+	}
+}
+
+func (us *UserService) RequestAccountDeletion(ctx context.Context, req dto.AccountDeletionRequest) (dto.AccountDeletionResponse, error) {
+	user, _, err := us.userRepo.GetUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_REQUEST_DELETION)
+		return dto.AccountDeletionResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	requestedAt := time.Now()
+	scheduledAt := requestedAt.Add(us.deletionGracePeriod)
+
+	err = us.userRepo.ScheduleUserDeletion(ctx, nil, req.UserID, requestedAt, scheduledAt)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_REQUEST_DELETION)
+		return dto.AccountDeletionResponse{}, constants.ErrRequestAccountDeletion
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_REQUEST_DELETION+": %s scheduled at %s", req.UserID, scheduledAt.Format(time.RFC3339))
+
+	return dto.AccountDeletionResponse{
+		ID:                  user.ID,
+		DeletionRequestedAt: requestedAt,
+		DeletionScheduledAt: scheduledAt,
+	}, nil
+}
+
+// EraseDueAccounts anonymizes every account whose deletion grace period has
+// passed and returns how many were erased.
+func (us *UserService) EraseDueAccounts(ctx context.Context) (int, error) {
+	users, err := us.userRepo.GetUsersDueForErasure(ctx, nil, time.Now(), constants.ENUM_ACCOUNT_ERASURE_BATCH_SIZE)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_ERASE_ACCOUNT)
+		return 0, constants.ErrGetUsersDueForErasure
+	}
+
+	erased := 0
+	for _, user := range users {
+		// A random password nobody knows keeps the row loginable by no one.
+		hashed, err := helpers.HashPassword(uuid.NewString())
+		if err != nil {
+			logging.Log.WithError(err).WithField("id", user.ID).Error(constants.MESSAGE_FAILED_ERASE_ACCOUNT)
+			continue
+		}
+
+		now := time.Now()
+		user.Name = constants.ENUM_ANONYMIZED_NAME
+		user.Email = fmt.Sprintf("deleted+%s@%s", user.ID, constants.ENUM_ANONYMIZED_EMAIL_DOMAIN)
+		user.Password = hashed
+		user.PhoneNumber = ""
+		user.Address = ""
+		user.AnonymizedAt = &now
+
+		archives, err := us.userRepo.AnonymizeUser(ctx, nil, user)
+		if err != nil {
+			logging.Log.WithError(err).WithField("id", user.ID).Error(constants.MESSAGE_FAILED_ERASE_ACCOUNT)
+			continue
+		}
+		removeDataExportArchives(archives)
+
+		logging.Log.Infof(constants.MESSAGE_SUCCESS_ERASE_ACCOUNT+": %s", user.ID)
+		erased++
+	}
+
+	return erased, nil
+}
+
+// errRollbackImport aborts the import transaction on dry runs and on failed
+// all-or-nothing imports. It never reaches the caller.
+var errRollbackImport = errors.New("rollback import")
+
+func (us *UserService) ImportUsers(ctx context.Context, req dto.ImportUserRequest) (dto.ImportUserResponse, error) {
+	if req.Mode == "" {
+		req.Mode = constants.ENUM_IMPORT_MODE_ATOMIC
+	}
+
+	if req.Mode != constants.ENUM_IMPORT_MODE_ATOMIC && req.Mode != constants.ENUM_IMPORT_MODE_BEST_EFFORT {
+		logging.Log.Warn(constants.MESSAGE_FAILED_IMPORT_USER + ": invalid mode")
+		return dto.ImportUserResponse{}, constants.ErrInvalidImportMode
+	}
+
+	rows, err := parseUserImport(req.Format, req.Content)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_IMPORT_USER)
+		return dto.ImportUserResponse{}, err
+	}
+
+	if len(rows) == 0 {
+		return dto.ImportUserResponse{}, constants.ErrEmptyImportFile
+	}
+
+	if len(rows) > constants.ENUM_IMPORT_MAX_ROWS {
+		logging.Log.Warnf(constants.MESSAGE_FAILED_IMPORT_USER+": %d rows exceeds limit", len(rows))
+		return dto.ImportUserResponse{}, constants.ErrImportTooManyRows
+	}
+
+	result := dto.ImportUserResponse{
+		Mode:   req.Mode,
+		DryRun: req.DryRun,
+		Total:  len(rows),
+		Rows:   make([]dto.ImportUserRowResult, len(rows)),
+	}
+
+	fields, err := us.customFieldRepo.GetAllCustomField(ctx, nil)
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_GET_LIST_CUSTOM_FIELD)
+		return dto.ImportUserResponse{}, constants.ErrGetAllCustomField
+	}
+
+	// Rows are checked and their passwords hashed before the transaction
+	// opens, so it only stays open for the inserts.
+	seen := make(map[string]bool, len(rows))
+	prepared := make(map[int]importRow, len(rows))
+	for i, row := range rows {
+		if req.Format == constants.ENUM_IMPORT_FORMAT_CSV {
+			row.CustomFields = csvCustomFieldValues(fields, row.CustomFields)
+		}
+
+		user, rowResult := us.prepareImportRow(ctx, row, fields, seen)
+		rowResult.Row = i + 1
+		result.Rows[i] = rowResult
+		if rowResult.Status == "" {
+			prepared[i] = user
+		}
+	}
+
+	countRows := func() {
+		result.Created, result.Skipped, result.Failed = 0, 0, 0
+		for _, rowResult := range result.Rows {
+			switch rowResult.Status {
+			case constants.ENUM_IMPORT_STATUS_CREATED:
+				result.Created++
+			case constants.ENUM_IMPORT_STATUS_SKIPPED:
+				result.Skipped++
+			case constants.ENUM_IMPORT_STATUS_FAILED:
+				result.Failed++
+			}
+		}
+	}
+
+	countRows()
+	if req.Mode == constants.ENUM_IMPORT_MODE_ATOMIC && result.Failed > 0 {
+		// Nothing would be committed, so the valid rows are reported as a
+		// dry run reports them, without opening the transaction.
+		for i := range prepared {
+			result.Rows[i].Status = constants.ENUM_IMPORT_STATUS_CREATED
+		}
+		countRows()
+		err = errRollbackImport
+	} else {
+		err = us.userRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+			for i := range rows {
+				if row, ok := prepared[i]; ok {
+					us.insertImportRow(ctx, tx, row, &result.Rows[i])
+				}
+			}
+
+			countRows()
+			if req.DryRun || (req.Mode == constants.ENUM_IMPORT_MODE_ATOMIC && result.Failed > 0) {
+				return errRollbackImport
+			}
+
+			return nil
+		})
+	}
+	if err != nil && !errors.Is(err, errRollbackImport) {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_IMPORT_USER)
+		return dto.ImportUserResponse{}, constants.ErrImportUser
+	}
+
+	result.Committed = err == nil
+	if !result.Committed {
+		// The inserts were rolled back, so no user has these IDs.
+		for i := range result.Rows {
+			result.Rows[i].ID = nil
+		}
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_IMPORT_USER+": created %d, skipped %d, failed %d, committed %t",
+		result.Created, result.Skipped, result.Failed, result.Committed)
+
+	return result, nil
+}
+
+// prepareImportRow checks a row the way CreateUser checks a request and
+// turns it into the user to insert, with the password hashed. A row that
+// cannot be imported gets a status instead.
+func (us *UserService) prepareImportRow(ctx context.Context, row dto.ImportUserRow, fields []model.CustomField, seen map[string]bool) (importRow, dto.ImportUserRowResult) {
+	rowResult := dto.ImportUserRowResult{Email: row.Email}
+
+	key := strings.ToLower(strings.TrimSpace(row.Email))
+	if seen[key] {
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_SKIPPED
+		rowResult.Error = constants.ErrDuplicateEmailInImport.Error()
+		return importRow{}, rowResult
+	}
+	seen[key] = true
+
+	if err := us.validateNewUser(ctx, nil, row.Name, row.Email, row.Password); err != nil {
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		if errors.Is(err, constants.ErrEmailAlreadyExists) {
+			rowResult.Status = constants.ENUM_IMPORT_STATUS_SKIPPED
+		}
+		rowResult.Error = err.Error()
+		return importRow{}, rowResult
+	}
+
+	if row.Role == "" {
+		row.Role = constants.ENUM_ROLE_USER
+	}
+
+	if row.Role != constants.ENUM_ROLE_USER && row.Role != constants.ENUM_ROLE_ADMIN {
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		rowResult.Error = constants.ErrInvalidRole.Error()
+		return importRow{}, rowResult
+	}
+
+	phoneNumber, err := us.normalizePhoneNumber(ctx, nil, row.PhoneNumber, uuid.Nil)
+	if err != nil {
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		rowResult.Error = err.Error()
+		return importRow{}, rowResult
+	}
+
+	userID := uuid.New()
+	save, _, err := resolveCustomFieldValues(fields, row.CustomFields, true)
+	if err != nil {
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		rowResult.Error = err.Error()
+		return importRow{}, rowResult
+	}
+
+	customFieldValues := make([]model.UserCustomFieldValue, 0, len(save))
+	for fieldID, value := range save {
+		customFieldValues = append(customFieldValues, model.UserCustomFieldValue{UserID: userID, FieldID: fieldID, Value: value})
+	}
+
+	hashed, err := helpers.HashPassword(row.Password)
+	if err != nil {
+		logging.Log.WithError(err).WithField("email", row.Email).Warn(constants.MESSAGE_FAILED_IMPORT_USER)
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		rowResult.Error = constants.ErrCreateUser.Error()
+		return importRow{}, rowResult
+	}
+
+	return importRow{
+		user: model.User{
+			ID:          userID,
+			Name:        row.Name,
+			Email:       row.Email,
+			Password:    hashed,
+			PhoneNumber: phoneNumber,
+			Address:     row.Address,
+			Role:        row.Role,
+		},
+		customFieldValues: customFieldValues,
+	}, rowResult
+}
+
+// csvCustomFieldValues types the text of CSV custom field columns the way a
+// JSON body carries it. Text that does not parse is kept as is, so the
+// custom field checks report it.
+func csvCustomFieldValues(fields []model.CustomField, input map[string]any) map[string]any {
+	types := make(map[string]string, len(fields))
+	for _, field := range fields {
+		types[field.Key] = field.Type
+	}
+
+	values := make(map[string]any, len(input))
+	for key, value := range input {
+		text, _ := value.(string)
+		switch types[key] {
+		case constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER:
+			if number, err := strconv.ParseFloat(text, 64); err == nil {
+				value = number
+			}
+		case constants.ENUM_CUSTOM_FIELD_TYPE_BOOLEAN:
+			if boolean, err := strconv.ParseBool(text); err == nil {
+				value = boolean
+			}
+		}
+		values[key] = value
+	}
+
+	return values
+}
+
+// insertImportRow inserts a prepared user in its own savepoint, so a failing
+// insert does not poison the rest of the import. An email taken since the
+// row was checked skips the row, as it would have been skipped then, and a
+// phone number taken fails it, as it would have failed then.
+func (us *UserService) insertImportRow(ctx context.Context, tx *gorm.DB, row importRow, rowResult *dto.ImportUserRowResult) {
+	user := row.user
+	err := us.userRepo.Transaction(ctx, tx, func(rowTx *gorm.DB) error {
+		if err := us.userRepo.CreateUserWithHashedPassword(ctx, rowTx, user); err != nil {
+			return err
+		}
+
+		return us.customFieldRepo.SaveUserCustomFieldValues(ctx, rowTx, row.customFieldValues)
+	})
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_SKIPPED
+		conflict := us.userConflictOr(ctx, tx, err, user, constants.ErrEmailAlreadyExists, constants.ErrCreateUser)
+		if errors.Is(conflict, constants.ErrPhoneNumberAlreadyExists) {
+			rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		}
+		rowResult.Error = conflict.Error()
+	case err != nil:
+		logging.Log.WithError(err).WithField("email", user.Email).Warn(constants.MESSAGE_FAILED_IMPORT_USER)
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_FAILED
+		rowResult.Error = constants.ErrCreateUser.Error()
+	default:
+		rowResult.Status = constants.ENUM_IMPORT_STATUS_CREATED
+		rowResult.ID = &user.ID
+	}
+}
+
+func parseUserImport(format string, content []byte) ([]dto.ImportUserRow, error) {
+	switch format {
+	case constants.ENUM_IMPORT_FORMAT_JSON:
+		var rows []dto.ImportUserRow
+		if err := json.Unmarshal(content, &rows); err != nil {
+			return nil, fmt.Errorf("%w: %v", constants.ErrParseImportFile, err)
+		}
+		return rows, nil
+	case constants.ENUM_IMPORT_FORMAT_CSV:
+		return parseUserImportCSV(content)
+	default:
+		return nil, constants.ErrInvalidImportFormat
+	}
+}
+
+// parseUserImportCSV reads a CSV whose first line names the columns. Column
+// names match the JSON keys of dto.ImportUserRow and may come in any order.
+func parseUserImportCSV(content []byte) ([]dto.ImportUserRow, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", constants.ErrParseImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch {
+		case name == "name", name == "email", name == "password", name == "phone_number", name == "address", name == "role":
+			columns[name] = i
+		case strings.HasPrefix(name, "custom_fields.") && name != "custom_fields.":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", constants.ErrParseImportFile, name)
+		}
+	}
+
+	for _, required := range []string{"name", "email", "password"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", constants.ErrParseImportFile, required)
+		}
+	}
+
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []dto.ImportUserRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", constants.ErrParseImportFile, err)
+		}
+
+		row := dto.ImportUserRow{
+			Name:        value(record, "name"),
+			Email:       value(record, "email"),
+			Password:    value(record, "password"),
+			PhoneNumber: value(record, "phone_number"),
+			Address:     value(record, "address"),
+			Role:        value(record, "role"),
+		}
+		for column := range columns {
+			if key, ok := strings.CutPrefix(column, "custom_fields."); ok {
+				if row.CustomFields == nil {
+					row.CustomFields = make(map[string]any)
+				}
+				row.CustomFields[key] = value(record, column)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+var userExportColumns = []string{"id", "name", "email", "phone_number", "address", "role", "created_at", "updated_at"}
+
+// ExportUsers streams every user matching req to w in the requested format
+// and returns the number of rows written.
+func (us *UserService) ExportUsers(ctx context.Context, req dto.ExportUserRequest, w io.Writer) (int, error) {
+	if req.InactiveDays < 0 {
+		logging.Log.Warn(constants.MESSAGE_FAILED_EXPORT_USER + ": invalid inactive_days")
+		return 0, constants.ErrInvalidInactiveDays
+	}
+
+	if err := repository.ValidateFilters(req.Filters, repository.UserFilterFields); err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_EXPORT_USER)
+		return 0, err
+	}
+
+	customFieldFilters, err := us.resolveCustomFieldFilters(ctx, req.CustomFields)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_EXPORT_USER + ": custom field filter")
+		return 0, err
+	}
+	req.CustomFields = customFieldFilters
+
+	writer, err := helpers.NewTabularWriter(req.Format, w)
+	if err != nil {
+		logging.Log.WithError(err).Warn(constants.MESSAGE_FAILED_EXPORT_USER)
+		return 0, constants.ErrInvalidExportFormat
+	}
+
+	if err := writer.WriteHeader(userExportColumns); err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_EXPORT_USER)
+		return 0, constants.ErrExportUser
+	}
+
+	total := 0
+	err = us.userRepo.StreamUsers(ctx, nil, req, constants.ENUM_EXPORT_BATCH_SIZE, func(users []model.User) error {
+		for _, user := range users {
+			if err := writer.WriteRow([]string{
+				user.ID.String(),
+				user.Name,
+				user.Email,
+				user.PhoneNumber,
+				user.Address,
+				user.Role,
+				user.CreatedAt.UTC().Format(time.RFC3339),
+				user.UpdatedAt.UTC().Format(time.RFC3339),
+			}); err != nil {
+				return err
+			}
+		}
+
+		total += len(users)
+		return writer.Flush()
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_EXPORT_USER)
+		return total, constants.ErrExportUser
+	}
+
+	if err := writer.Close(); err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_EXPORT_USER)
+		return total, constants.ErrExportUser
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_EXPORT_USER+": %d row(s) as %s", total, req.Format)
+
+	return total, nil
+}
+
+// errRollbackBulk aborts a bulk transaction once any item has failed.
+var errRollbackBulk = errors.New("rollback bulk operation")
+
+func (us *UserService) BulkUpdateUsers(ctx context.Context, req dto.BulkUpdateUserRequest) (dto.BulkUserResponse, error) {
+	columns := map[string]any{}
+
+	if req.Fields.Role != nil {
+		if *req.Fields.Role != constants.ENUM_ROLE_USER && *req.Fields.Role != constants.ENUM_ROLE_ADMIN {
+			logging.Log.Warn(constants.MESSAGE_FAILED_BULK_UPDATE_USER + ": invalid role")
+			return dto.BulkUserResponse{}, constants.ErrInvalidRole
+		}
+		columns["role"] = *req.Fields.Role
+	}
+
+	if req.Fields.PhoneNumber != nil {
+		phoneNumber, err := helpers.NormalizePhoneNumber(*req.Fields.PhoneNumber, us.phoneRegion)
+		if err != nil {
+			logging.Log.Warn(constants.MESSAGE_FAILED_BULK_UPDATE_USER + ": invalid phone number")
+			return dto.BulkUserResponse{}, err
+		}
+		columns["phone_number"] = phoneNumber
+	}
+
+	if req.Fields.Address != nil {
+		columns["address"] = *req.Fields.Address
+	}
+
+	if len(columns) == 0 {
+		logging.Log.Warn(constants.MESSAGE_FAILED_BULK_UPDATE_USER + ": no fields")
+		return dto.BulkUserResponse{}, constants.ErrBulkNoFields
+	}
+
+	result, err := us.runBulk(ctx, req.BulkUserSelector, constants.ENUM_BULK_STATUS_UPDATED, func(tx *gorm.DB, user model.User) error {
+		if phoneNumber, ok := columns["phone_number"].(string); ok {
+			if _, err := us.normalizePhoneNumber(ctx, tx, phoneNumber, user.ID); err != nil {
+				return err
+			}
+		}
+		return us.userRepo.UpdateUserColumns(ctx, tx, user.ID.String(), columns)
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_BULK_UPDATE_USER)
+		return dto.BulkUserResponse{}, bulkError(err, constants.ErrBulkUpdateUser)
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_BULK_UPDATE_USER+": %d updated, committed %t", result.Succeeded, result.Committed)
+
+	return result, nil
+}
+
+func (us *UserService) BulkDeleteUsers(ctx context.Context, req dto.BulkDeleteUserRequest) (dto.BulkUserResponse, error) {
+	result, err := us.runBulk(ctx, req.BulkUserSelector, constants.ENUM_BULK_STATUS_DELETED, func(tx *gorm.DB, user model.User) error {
+		if user.ID.String() == req.RequestedBy {
+			return constants.ErrBulkSelf
+		}
+		return us.userRepo.DeleteUserByID(ctx, tx, user.ID.String())
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_BULK_DELETE_USER)
+		return dto.BulkUserResponse{}, bulkError(err, constants.ErrBulkDeleteUser)
+	}
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_BULK_DELETE_USER+": %d deleted, committed %t", result.Succeeded, result.Committed)
+
+	return result, nil
+}
+
+// notFoundOr reports a failed lookup as notFound when the record does not
+// exist, and as failed when the database could not be asked.
+func notFoundOr(err, notFound, failed error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return failed
+}
+
+// duplicateOr reports a write that broke a unique index as conflict, so a
+// request that raced past the existence check still gets the same answer.
+func duplicateOr(err, conflict, failed error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return conflict
+	}
+	return failed
+}
+
+// userConflictOr is duplicateOr for writes to users, which have a unique
+// index on the email and, when PHONE_NUMBER_UNIQUE is set, one on the phone
+// number. The database does not say which one broke, so the phone number is
+// looked up again.
+func (us *UserService) userConflictOr(ctx context.Context, tx *gorm.DB, err error, user model.User, emailConflict, failed error) error {
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return failed
+	}
+
+	if us.phoneUnique && user.PhoneNumber != "" {
+		existing, found, _ := us.userRepo.GetUserByPhoneNumber(ctx, tx, user.PhoneNumber)
+		if found && existing.ID != user.ID {
+			return constants.ErrPhoneNumberAlreadyExists
+		}
+	}
+
+	return emailConflict
+}
+
+// bulkError keeps the request errors the caller can act on and hides the
+// rest behind fallback.
+func bulkError(err, fallback error) error {
+	for _, known := range []error{constants.ErrBulkInvalidSelection, constants.ErrBulkTooManyItems, constants.ErrInvalidUUID,
+		constants.ErrInvalidInactiveDays, constants.ErrInvalidFilter, constants.ErrUnknownCustomField, constants.ErrInvalidCustomFieldValue} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	return fallback
+}
+
+// runBulk resolves the selector and applies action to every selected user
+// inside one transaction. Each item runs under its own savepoint so the
+// report can say which one failed, and any failure rolls the whole batch
+// back.
+func (us *UserService) runBulk(ctx context.Context, selector dto.BulkUserSelector, successStatus string, action func(tx *gorm.DB, user model.User) error) (dto.BulkUserResponse, error) {
+	hasFilter := selector.Filter != nil && !selector.Filter.IsEmpty()
+	if (len(selector.IDs) == 0) == !hasFilter {
+		return dto.BulkUserResponse{}, constants.ErrBulkInvalidSelection
+	}
+
+	if len(selector.IDs) > constants.ENUM_BULK_MAX_ITEMS {
+		return dto.BulkUserResponse{}, constants.ErrBulkTooManyItems
+	}
+
+	if hasFilter {
+		filter, err := us.resolveBulkFilter(ctx, *selector.Filter)
+		if err != nil {
+			return dto.BulkUserResponse{}, err
+		}
+		selector.Filter = &filter
+	}
+
+	var result dto.BulkUserResponse
+
+	err := us.userRepo.Transaction(ctx, nil, func(tx *gorm.DB) error {
+		userIDs, err := us.resolveBulkSelection(ctx, tx, selector)
+		if err != nil {
+			return err
+		}
+
+		users, err := us.userRepo.GetUsersByIDs(ctx, tx, userIDs)
+		if err != nil {
+			return err
+		}
+
+		usersByID := make(map[string]model.User, len(users))
+		for _, user := range users {
+			usersByID[user.ID.String()] = user
+		}
+
+		result.Total = len(userIDs)
+		result.Items = make([]dto.BulkUserItemResult, 0, len(userIDs))
+
+		for _, userID := range userIDs {
+			item := dto.BulkUserItemResult{ID: userID}
+
+			user, found := usersByID[userID]
+			if !found {
+				item.Status = constants.ENUM_BULK_STATUS_NOT_FOUND
+				result.Skipped++
+				result.Items = append(result.Items, item)
+				continue
+			}
+
+			err := us.userRepo.Transaction(ctx, tx, func(itemTx *gorm.DB) error {
+				return action(itemTx, user)
+			})
+
+			switch {
+			case errors.Is(err, constants.ErrBulkSelf):
+				item.Status = constants.ENUM_BULK_STATUS_SKIPPED
+				item.Error = err.Error()
+				result.Skipped++
+			case err != nil:
+				logging.Log.WithError(err).WithField("id", userID).Warn("bulk item failed")
+				item.Status = constants.ENUM_BULK_STATUS_FAILED
+				item.Error = err.Error()
+				result.Failed++
+			default:
+				item.Status = successStatus
+				result.Succeeded++
+			}
+
+			result.Items = append(result.Items, item)
+		}
+
+		if result.Failed > 0 {
+			return errRollbackBulk
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollbackBulk) {
+		return dto.BulkUserResponse{}, err
+	}
+
+	result.Committed = err == nil
+
+	return result, nil
+}
+
+// resolveBulkFilter checks a bulk filter the way the user list checks its
+// query, and resolves its custom field values for the repository.
+func (us *UserService) resolveBulkFilter(ctx context.Context, filter dto.BulkUserFilter) (dto.BulkUserFilter, error) {
+	if filter.InactiveDays < 0 {
+		return dto.BulkUserFilter{}, constants.ErrInvalidInactiveDays
+	}
+
+	filters := make([]dto.FilterCondition, 0, len(filter.Filters))
+	for _, condition := range filter.Filters {
+		if condition.Operator == "" {
+			condition.Operator = constants.ENUM_FILTER_OPERATOR_EQ
+		}
+		filters = append(filters, condition)
+	}
+	if err := repository.ValidateFilters(filters, repository.UserFilterFields); err != nil {
+		return dto.BulkUserFilter{}, err
+	}
+	filter.Filters = filters
+
+	customFields, err := us.resolveCustomFieldFilters(ctx, filter.CustomFields)
+	if err != nil {
+		return dto.BulkUserFilter{}, err
+	}
+	filter.CustomFields = customFields
+
+	return filter, nil
+}
+
+func (us *UserService) resolveBulkSelection(ctx context.Context, tx *gorm.DB, selector dto.BulkUserSelector) ([]string, error) {
+	if len(selector.IDs) == 0 {
+		// Ask for one more than allowed so an oversized filter is rejected
+		// instead of silently truncated.
+		userIDs, err := us.userRepo.GetUserIDsByFilter(ctx, tx, *selector.Filter, constants.ENUM_BULK_MAX_ITEMS+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(userIDs) > constants.ENUM_BULK_MAX_ITEMS {
+			return nil, constants.ErrBulkTooManyItems
+		}
+
+		return userIDs, nil
+	}
+
+	seen := make(map[string]bool, len(selector.IDs))
+	userIDs := make([]string, 0, len(selector.IDs))
+	for _, id := range selector.IDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, constants.ErrInvalidUUID
+		}
+
+		normalized := parsed.String()
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		userIDs = append(userIDs, normalized)
+	}
+
+	return userIDs, nil
+}
+
+// userStatusTransitions lists, for each status, the statuses an admin may
+// move a user to. Suspended to suspended changes the end of the suspension.
+var userStatusTransitions = map[string][]string{
+	constants.ENUM_USER_STATUS_ACTIVE:    {constants.ENUM_USER_STATUS_SUSPENDED, constants.ENUM_USER_STATUS_BANNED},
+	constants.ENUM_USER_STATUS_SUSPENDED: {constants.ENUM_USER_STATUS_ACTIVE, constants.ENUM_USER_STATUS_SUSPENDED, constants.ENUM_USER_STATUS_BANNED},
+	constants.ENUM_USER_STATUS_BANNED:    {constants.ENUM_USER_STATUS_ACTIVE},
+}
+
+func userStatus(user model.User) string {
+	if user.Status == "" {
+		return constants.ENUM_USER_STATUS_ACTIVE
+	}
+	return user.Status
+}
+
+func (us *UserService) UpdateUserStatus(ctx context.Context, req dto.UpdateUserStatusRequest) (dto.UserStatusResponse, error) {
+	if req.UserID == req.ChangedBy {
+		logging.Log.Warn(constants.MESSAGE_FAILED_UPDATE_USER_STATUS + ": own account")
+		return dto.UserStatusResponse{}, constants.ErrChangeOwnStatus
+	}
+
+	if _, ok := userStatusTransitions[req.Status]; !ok {
+		logging.Log.Warn(constants.MESSAGE_FAILED_UPDATE_USER_STATUS + ": invalid status")
+		return dto.UserStatusResponse{}, constants.ErrInvalidUserStatus
+	}
+
+	user, _, err := us.userRepo.GetUserByID(ctx, nil, req.UserID)
+	if err != nil {
+		logging.Log.WithError(err).WithField("id", req.UserID).Error(constants.MESSAGE_FAILED_UPDATE_USER_STATUS)
+		return dto.UserStatusResponse{}, notFoundOr(err, constants.ErrUserNotFound, constants.ErrGetUserByID)
+	}
+
+	current := userStatus(user)
+	allowed := false
+	for _, next := range userStatusTransitions[current] {
+		if next == req.Status {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		logging.Log.Warnf(constants.MESSAGE_FAILED_UPDATE_USER_STATUS+": %s to %s", current, req.Status)
+		return dto.UserStatusResponse{}, constants.ErrInvalidStatusTransition
+	}
+
+	now := time.Now()
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	switch req.Status {
+	case constants.ENUM_USER_STATUS_SUSPENDED:
+		if req.SuspendedUntil == nil || !req.SuspendedUntil.After(now) {
+			return dto.UserStatusResponse{}, constants.ErrInvalidSuspendedUntil
+		}
+		fallthrough
+	case constants.ENUM_USER_STATUS_BANNED:
+		if req.Reason == "" {
+			return dto.UserStatusResponse{}, constants.ErrStatusReasonRequired
+		}
+	}
+
+	if req.Status != constants.ENUM_USER_STATUS_SUSPENDED {
+		req.SuspendedUntil = nil
+	}
+
+	err = us.userRepo.UpdateUserColumns(ctx, nil, req.UserID, map[string]any{
+		"status":            req.Status,
+		"status_reason":     req.Reason,
+		"suspended_until":   req.SuspendedUntil,
+		"status_changed_at": now,
+	})
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.MESSAGE_FAILED_UPDATE_USER_STATUS)
+		return dto.UserStatusResponse{}, constants.ErrUpdateUserStatus
+	}
+
+	us.userStatuses.Delete(req.UserID)
+
+	logging.Log.Infof(constants.MESSAGE_SUCCESS_UPDATE_USER_STATUS+": %s %s to %s by %s", req.UserID, current, req.Status, req.ChangedBy)
+
+	return dto.UserStatusResponse{
+		ID:              user.ID,
+		Status:          req.Status,
+		StatusReason:    req.Reason,
+		SuspendedUntil:  req.SuspendedUntil,
+		StatusChangedAt: &now,
+	}, nil
+}
+
+// CheckUserStatus is called on every authenticated request so that
+// suspending or banning a user takes effect before their token expires. A
+// token whose user no longer exists is no longer valid. The outcome is
+// cached for a short while, which is how long a status change made on
+// another instance can take to apply.
+func (us *UserService) CheckUserStatus(ctx context.Context, userID string) error {
+	if err, ok := us.userStatuses.Get(userID); ok {
+		return err
+	}
+
+	user, _, err := us.userRepo.GetUserByID(ctx, nil, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logging.Log.WithError(err).WithField("id", userID).Warn(constants.MESSAGE_FAILED_ACCOUNT_INACTIVE)
+		return constants.ErrGetUserByID
+	}
+
+	if err != nil {
+		err = constants.ErrTokenInvalid
+	} else {
+		err = accountStatusError(user, time.Now())
+	}
+
+	us.userStatuses.Set(userID, err)
+	return err
+}
+
+// accountStatusError rejects users who may not use the API. A suspension
+// that has run out no longer counts, even before the user is reactivated.
+func accountStatusError(user model.User, now time.Time) error {
+	switch userStatus(user) {
+	case constants.ENUM_USER_STATUS_ACTIVE:
+		return nil
+	case constants.ENUM_USER_STATUS_BANNED:
+		return constants.ErrAccountBanned
+	case constants.ENUM_USER_STATUS_SUSPENDED:
+		if user.SuspendedUntil == nil || now.Before(*user.SuspendedUntil) {
+			return constants.ErrAccountSuspended
+		}
+		return nil
+	default:
+		return constants.ErrInvalidUserStatus
+	}
+}
+
+// checkAccountActive rejects users who may not use the API and reactivates
+// those whose suspension has run out.
+func (us *UserService) checkAccountActive(ctx context.Context, user model.User) error {
+	now := time.Now()
+	if err := accountStatusError(user, now); err != nil {
+		return err
+	}
+
+	if userStatus(user) == constants.ENUM_USER_STATUS_SUSPENDED {
+		err := us.userRepo.UpdateUserColumns(ctx, nil, user.ID.String(), map[string]any{
+			"status":            constants.ENUM_USER_STATUS_ACTIVE,
+			"status_reason":     "",
+			"suspended_until":   nil,
+			"status_changed_at": now,
+		})
+		if err != nil {
+			logging.Log.WithError(err).WithField("id", user.ID).Error(constants.ErrReactivateUser.Error())
+			return constants.ErrReactivateUser
+		}
+
+		logging.Log.Infof(constants.MESSAGE_SUCCESS_REACTIVATE_USER+": %s", user.ID)
+	}
+
+	return nil
+}
+
+func (us *UserService) ReactivateExpiredSuspensions(ctx context.Context) (int64, error) {
+	count, err := us.userRepo.ReactivateExpiredSuspensions(ctx, nil, time.Now())
+	if err != nil {
+		logging.Log.WithError(err).Error(constants.ErrReactivateUser.Error())
+		return 0, constants.ErrReactivateUser
+	}
+
+	if count > 0 {
+		logging.Log.Infof(constants.MESSAGE_SUCCESS_REACTIVATE_USER+": %d user(s)", count)
+	}
+
+	return count, nil
+}
+
+// TouchLastSeen records that the user made an authenticated request. Users
+// touched within the throttle interval are skipped without a query, and the
+// update only matches a last_seen_at older than the interval, so the row is
+// written at most once per interval across all instances.
+func (us *UserService) TouchLastSeen(ctx context.Context, userID string) {
+	if _, ok := us.lastSeen.Get(userID); ok {
+		return
+	}
+	us.lastSeen.Set(userID, struct{}{})
+
+	now := time.Now()
+	if err := us.userRepo.TouchUserLastSeen(ctx, nil, userID, now, now.Add(-us.lastSeenThrottle)); err != nil {
+		us.lastSeen.Delete(userID)
+		logging.Log.WithError(err).WithField("id", userID).Warn(constants.MESSAGE_FAILED_RECORD_ACTIVITY)
+	}
+}
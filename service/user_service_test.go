@@ -1,9 +1,19 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
 	"context"
 	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -16,14 +26,39 @@ import (
 )
 
 type mockUserRepo struct {
+	transactionFn          func(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error
 	registerFn             func(ctx context.Context, user model.User) error
 	getByIDFn              func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error)
 	getByEmailFn           func(ctx context.Context, email string) (model.User, bool, error)
 	getAllFn               func(ctx context.Context, search string) ([]model.User, error)
 	getAllWithPaginationFn func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error)
+	streamUsersFn          func(ctx context.Context, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error
 	createFn               func(ctx context.Context, user model.User) error
 	updateFn               func(ctx context.Context, user model.User) error
+	updateColumnsFn        func(ctx context.Context, userID string, columns map[string]any) error
+	getByIDsFn             func(ctx context.Context, userIDs []string) ([]model.User, error)
+	getIDsByFilterFn       func(ctx context.Context, filter dto.BulkUserFilter, limit int) ([]string, error)
 	deleteByIDFn           func(ctx context.Context, userID string) error
+	getAllTrashedFn        func(ctx context.Context, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error)
+	getTrashedByIDFn       func(ctx context.Context, userID string) (model.User, bool, error)
+	restoreByIDFn          func(ctx context.Context, userID string) error
+	purgeByIDFn            func(ctx context.Context, userID string) ([]string, error)
+	scheduleDeletionFn     func(ctx context.Context, userID string, requestedAt, scheduledAt time.Time) error
+	cancelDeletionFn       func(ctx context.Context, userID string) error
+	getDueForErasureFn     func(ctx context.Context, now time.Time, limit int) ([]model.User, error)
+	anonymizeFn            func(ctx context.Context, user model.User) ([]string, error)
+	reactivateExpiredFn    func(ctx context.Context, now time.Time) (int64, error)
+	getByEmailChangeFn     func(ctx context.Context, tokenHash string) (model.User, bool, error)
+	getByPhoneNumberFn     func(ctx context.Context, phoneNumber string) (model.User, bool, error)
+	recordLoginFn          func(ctx context.Context, userID string, loginAt time.Time, ip string) error
+	touchLastSeenFn        func(ctx context.Context, userID string, seenAt, staleBefore time.Time) error
+}
+
+func (m *mockUserRepo) Transaction(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if m.transactionFn != nil {
+		return m.transactionFn(ctx, tx, fn)
+	}
+	return fn(tx)
 }
 
 func (m *mockUserRepo) Register(ctx context.Context, _ *gorm.DB, user model.User) error {
@@ -47,6 +82,20 @@ func (m *mockUserRepo) GetUserByEmail(ctx context.Context, _ *gorm.DB, email str
 	return model.User{}, false, nil
 }
 
+func (m *mockUserRepo) GetUserByPhoneNumber(ctx context.Context, _ *gorm.DB, phoneNumber string) (model.User, bool, error) {
+	if m.getByPhoneNumberFn != nil {
+		return m.getByPhoneNumberFn(ctx, phoneNumber)
+	}
+	return model.User{}, false, gorm.ErrRecordNotFound
+}
+
+func (m *mockUserRepo) GetUserByEmailChangeToken(ctx context.Context, _ *gorm.DB, tokenHash string) (model.User, bool, error) {
+	if m.getByEmailChangeFn != nil {
+		return m.getByEmailChangeFn(ctx, tokenHash)
+	}
+	return model.User{}, false, gorm.ErrRecordNotFound
+}
+
 func (m *mockUserRepo) GetAllUser(ctx context.Context, _ *gorm.DB, search string) ([]model.User, error) {
 	if m.getAllFn != nil {
 		return m.getAllFn(ctx, search)
@@ -61,6 +110,13 @@ func (m *mockUserRepo) GetAllUserWithPagination(ctx context.Context, tx *gorm.DB
 	return dto.UserPaginationRepositoryResponse{}, nil
 }
 
+func (m *mockUserRepo) StreamUsers(ctx context.Context, _ *gorm.DB, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error {
+	if m.streamUsersFn != nil {
+		return m.streamUsersFn(ctx, req, batchSize, fn)
+	}
+	return nil
+}
+
 func (m *mockUserRepo) CreateUser(ctx context.Context, _ *gorm.DB, user model.User) error {
 	if m.createFn != nil {
 		return m.createFn(ctx, user)
@@ -68,6 +124,13 @@ func (m *mockUserRepo) CreateUser(ctx context.Context, _ *gorm.DB, user model.Us
 	return nil
 }
 
+func (m *mockUserRepo) CreateUserWithHashedPassword(ctx context.Context, _ *gorm.DB, user model.User) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, user)
+	}
+	return nil
+}
+
 func (m *mockUserRepo) UpdateUser(ctx context.Context, _ *gorm.DB, user model.User) error {
 	if m.updateFn != nil {
 		return m.updateFn(ctx, user)
@@ -75,6 +138,27 @@ func (m *mockUserRepo) UpdateUser(ctx context.Context, _ *gorm.DB, user model.Us
 	return nil
 }
 
+func (m *mockUserRepo) UpdateUserColumns(ctx context.Context, _ *gorm.DB, userID string, columns map[string]any) error {
+	if m.updateColumnsFn != nil {
+		return m.updateColumnsFn(ctx, userID, columns)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) GetUsersByIDs(ctx context.Context, _ *gorm.DB, userIDs []string) ([]model.User, error) {
+	if m.getByIDsFn != nil {
+		return m.getByIDsFn(ctx, userIDs)
+	}
+	return []model.User{}, nil
+}
+
+func (m *mockUserRepo) GetUserIDsByFilter(ctx context.Context, _ *gorm.DB, filter dto.BulkUserFilter, limit int) ([]string, error) {
+	if m.getIDsByFilterFn != nil {
+		return m.getIDsByFilterFn(ctx, filter, limit)
+	}
+	return []string{}, nil
+}
+
 func (m *mockUserRepo) DeleteUserByID(ctx context.Context, _ *gorm.DB, userID string) error {
 	if m.deleteByIDFn != nil {
 		return m.deleteByIDFn(ctx, userID)
@@ -82,6 +166,83 @@ func (m *mockUserRepo) DeleteUserByID(ctx context.Context, _ *gorm.DB, userID st
 	return nil
 }
 
+func (m *mockUserRepo) GetAllTrashedUserWithPagination(ctx context.Context, _ *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+	if m.getAllTrashedFn != nil {
+		return m.getAllTrashedFn(ctx, req)
+	}
+	return dto.UserPaginationRepositoryResponse{}, nil
+}
+
+func (m *mockUserRepo) GetTrashedUserByID(ctx context.Context, _ *gorm.DB, userID string) (model.User, bool, error) {
+	if m.getTrashedByIDFn != nil {
+		return m.getTrashedByIDFn(ctx, userID)
+	}
+	return model.User{}, false, nil
+}
+
+func (m *mockUserRepo) RestoreUserByID(ctx context.Context, _ *gorm.DB, userID string) error {
+	if m.restoreByIDFn != nil {
+		return m.restoreByIDFn(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) PurgeUserByID(ctx context.Context, _ *gorm.DB, userID string) ([]string, error) {
+	if m.purgeByIDFn != nil {
+		return m.purgeByIDFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepo) ScheduleUserDeletion(ctx context.Context, _ *gorm.DB, userID string, requestedAt, scheduledAt time.Time) error {
+	if m.scheduleDeletionFn != nil {
+		return m.scheduleDeletionFn(ctx, userID, requestedAt, scheduledAt)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) CancelUserDeletion(ctx context.Context, _ *gorm.DB, userID string) error {
+	if m.cancelDeletionFn != nil {
+		return m.cancelDeletionFn(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) GetUsersDueForErasure(ctx context.Context, _ *gorm.DB, now time.Time, limit int) ([]model.User, error) {
+	if m.getDueForErasureFn != nil {
+		return m.getDueForErasureFn(ctx, now, limit)
+	}
+	return []model.User{}, nil
+}
+
+func (m *mockUserRepo) AnonymizeUser(ctx context.Context, _ *gorm.DB, user model.User) ([]string, error) {
+	if m.anonymizeFn != nil {
+		return m.anonymizeFn(ctx, user)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepo) ReactivateExpiredSuspensions(ctx context.Context, _ *gorm.DB, now time.Time) (int64, error) {
+	if m.reactivateExpiredFn != nil {
+		return m.reactivateExpiredFn(ctx, now)
+	}
+	return 0, nil
+}
+
+func (m *mockUserRepo) RecordUserLogin(ctx context.Context, _ *gorm.DB, userID string, loginAt time.Time, ip string) error {
+	if m.recordLoginFn != nil {
+		return m.recordLoginFn(ctx, userID, loginAt, ip)
+	}
+	return nil
+}
+
+func (m *mockUserRepo) TouchUserLastSeen(ctx context.Context, _ *gorm.DB, userID string, seenAt, staleBefore time.Time) error {
+	if m.touchLastSeenFn != nil {
+		return m.touchLastSeenFn(ctx, userID, seenAt, staleBefore)
+	}
+	return nil
+}
+
 type mockJWTService struct {
 	generateFn      func(userID, role string) (string, string, error)
 	validateTokenFn func(token string) (*jwt.Token, *jwtCustomClaims, error)
@@ -101,13 +262,26 @@ func (m *mockJWTService) ValidateToken(token string) (*jwt.Token, *jwtCustomClai
 	return nil, nil, nil
 }
 
+type mockMailService struct {
+	sendFn func(to, subject, body string) error
+}
+
+func (m *mockMailService) SendMail(to, subject, body string) error {
+	if m.sendFn != nil {
+		return m.sendFn(to, subject, body)
+	}
+	return nil
+}
+
 // Unit Test
 
 // Register
 func TestUserService_Register_InvalidName(t *testing.T) {
 	us := NewUserService(
 		&mockUserRepo{},
+		&mockCustomFieldRepo{},
 		&mockJWTService{},
+		&mockMailService{},
 	)
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
@@ -123,7 +297,9 @@ func TestUserService_Register_InvalidName(t *testing.T) {
 func TestUserService_Register_InvalidEmail(t *testing.T) {
 	us := NewUserService(
 		&mockUserRepo{},
+		&mockCustomFieldRepo{},
 		&mockJWTService{},
+		&mockMailService{},
 	)
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
@@ -143,7 +319,7 @@ func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
 		Name:     "Som User",
@@ -158,7 +334,9 @@ func TestUserService_Register_EmailAlreadyExists(t *testing.T) {
 func TestUserService_Register_PasswordToShort(t *testing.T) {
 	us := NewUserService(
 		&mockUserRepo{},
+		&mockCustomFieldRepo{},
 		&mockJWTService{},
+		&mockMailService{},
 	)
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
@@ -181,7 +359,7 @@ func TestUserService_Register_Success(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	resp, err := us.Register(context.Background(), dto.RegisterUserRequest{
 		Name:     "Som User",
@@ -204,7 +382,7 @@ func TestUserService_Register_GetUserByEmailError(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
 		Name:     "Som User",
@@ -226,7 +404,7 @@ func TestUserService_Register_Failed(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
 		Name:     "Som User",
@@ -238,6 +416,44 @@ func TestUserService_Register_Failed(t *testing.T) {
 		t.Fatalf("expected ErrRegisterUser got %v", err)
 	}
 }
+func TestUserService_Register_EmailLookupNotFound(t *testing.T) {
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
+		Name:     "Som User",
+		Email:    "test@mail.com",
+		Password: "password123",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+func TestUserService_Register_DuplicatedKey(t *testing.T) {
+	repo := &mockUserRepo{
+		registerFn: func(ctx context.Context, user model.User) error {
+			return gorm.ErrDuplicatedKey
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
+		Name:     "Som User",
+		Email:    "test@mail.com",
+		Password: "password123",
+	})
+
+	if err != constants.ErrEmailAlreadyExists {
+		t.Fatalf("expected ErrEmailAlreadyExists, got %v", err)
+	}
+}
 
 // Login
 func TestUserService_Login_Success(t *testing.T) {
@@ -268,7 +484,7 @@ func TestUserService_Login_Success(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, jwt)
+	us := NewUserService(repo, &mockCustomFieldRepo{}, jwt, &mockMailService{})
 
 	resp, err := us.Login(context.Background(), dto.LoginUserRequest{
 		Email:    "test@mail.com",
@@ -312,7 +528,7 @@ func TestUserService_Login_PasswordMismatch(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, jwt)
+	us := NewUserService(repo, &mockCustomFieldRepo{}, jwt, &mockMailService{})
 
 	_, err = us.Login(context.Background(), dto.LoginUserRequest{
 		Email:    "test@mail.com",
@@ -341,7 +557,7 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, jwt)
+	us := NewUserService(repo, &mockCustomFieldRepo{}, jwt, &mockMailService{})
 
 	_, err := us.Login(context.Background(), dto.LoginUserRequest{
 		Email:    "notfound@mail.com",
@@ -384,7 +600,7 @@ func TestUserService_Login_GenerateTokenError(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, jwt)
+	us := NewUserService(repo, &mockCustomFieldRepo{}, jwt, &mockMailService{})
 
 	_, err = us.Login(context.Background(), dto.LoginUserRequest{
 		Email:    "test@mail.com",
@@ -411,7 +627,7 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	resp, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
 		Name:        "Som User",
@@ -432,7 +648,9 @@ func TestUserService_CreateUser_Success(t *testing.T) {
 func TestUserService_CreateUser_InvalidName(t *testing.T) {
 	us := NewUserService(
 		&mockUserRepo{},
+		&mockCustomFieldRepo{},
 		&mockJWTService{},
+		&mockMailService{},
 	)
 
 	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
@@ -450,7 +668,9 @@ func TestUserService_CreateUser_InvalidName(t *testing.T) {
 func TestUserService_CreateUser_InvalidEmail(t *testing.T) {
 	us := NewUserService(
 		&mockUserRepo{},
+		&mockCustomFieldRepo{},
 		&mockJWTService{},
+		&mockMailService{},
 	)
 
 	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
@@ -472,7 +692,7 @@ func TestUserService_CreateUser_EmailAlreadyExists(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
 		Name:        "Som User",
@@ -489,7 +709,9 @@ func TestUserService_CreateUser_EmailAlreadyExists(t *testing.T) {
 func TestUserService_CreateUser_PasswordToShort(t *testing.T) {
 	us := NewUserService(
 		&mockUserRepo{},
+		&mockCustomFieldRepo{},
 		&mockJWTService{},
+		&mockMailService{},
 	)
 
 	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
@@ -511,7 +733,7 @@ func TestUserService_CreateUser_GetUserByEmailError(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
 		Name:        "Som User",
@@ -535,7 +757,7 @@ func TestUserService_CreateUser_Failed(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.Register(context.Background(), dto.RegisterUserRequest{
 		Name:     "Som User",
@@ -556,7 +778,7 @@ func TestUserService_GetAllUser_Error(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	resp, err := us.GetAllUser(context.Background(), "som")
 
@@ -577,7 +799,7 @@ func TestUserService_GetAllUserWithPagination_Error(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	resp, err := us.GetAllUserWithPagination(
 		context.Background(),
@@ -598,189 +820,580 @@ func TestUserService_GetAllUserWithPagination_Error(t *testing.T) {
 	}
 }
 
-// Get User By ID
-func TestUserService_GetUserByID_RepoError(t *testing.T) {
-	userID := uuid.NewString()
-
+func TestUserService_GetAllUserWithPagination_Sort(t *testing.T) {
+	var sort string
 	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{}, false, errors.New("db error")
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			sort = req.Sort
+			return dto.UserPaginationRepositoryResponse{}, nil
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	resp, err := us.GetuserByID(context.Background(), userID)
-
-	if !errors.Is(err, constants.ErrGetUserByID) {
-		t.Fatalf("expected ErrGetUserByID, got %v", err)
+	_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Sort: "name,-created_at"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if resp.ID != uuid.Nil {
-		t.Fatalf("expected empty response, got %+v", resp)
+	if sort != "name,-created_at" {
+		t.Fatalf("expected sort to reach the repository, got %q", sort)
 	}
 }
-func TestUserService_GetUserByID_InvalidUUID(t *testing.T) {
-	repo := &mockUserRepo{}
 
-	us := NewUserService(repo, &mockJWTService{})
+func TestUserService_GetAllUserWithPagination_InvalidSort(t *testing.T) {
+	cases := []string{"password", "name,-name", "name,"}
 
-	resp, err := us.GetuserByID(context.Background(), "invalid-uuid")
+	for _, value := range cases {
+		t.Run(value, func(t *testing.T) {
+			repo := &mockUserRepo{
+				getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+					t.Fatal("repository must not be called with an invalid sort")
+					return dto.UserPaginationRepositoryResponse{}, nil
+				},
+			}
 
-	if !errors.Is(err, constants.ErrInvalidUUID) {
-		t.Fatalf("expected ErrInvalidUUID, got %v", err)
-	}
+			us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	if resp.ID != uuid.Nil {
-		t.Fatalf("expected empty response, got %+v", resp)
+			_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+				PaginationRequest: dto.PaginationRequest{Sort: value},
+			})
+			if !errors.Is(err, constants.ErrInvalidSortField) {
+				t.Fatalf("expected ErrInvalidSortField, got %v", err)
+			}
+		})
 	}
 }
 
-// Update User
-func TestUserService_UpdateUser_GetUserByIDError(t *testing.T) {
+func TestUserService_GetAllUserWithPagination_Filter(t *testing.T) {
+	query, _ := url.ParseQuery("filter[role]=admin&filter[created_at][gte]=2026-01-01&search=x")
+	filters, err := dto.ParseFilterQuery(query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(filters) != 2 || filters[0] != (dto.FilterCondition{Field: "created_at", Operator: "gte", Value: "2026-01-01"}) ||
+		filters[1] != (dto.FilterCondition{Field: "role", Operator: "eq", Value: "admin"}) {
+		t.Fatalf("unexpected filters: %v", filters)
+	}
+
+	var got []dto.FilterCondition
 	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{}, false, errors.New("db error")
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			got = req.Filters
+			return dto.UserPaginationRepositoryResponse{}, nil
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
-		ID: uuid.New().String(),
+	_, err = us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Filters: filters},
 	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	if !errors.Is(err, constants.ErrGetUserByID) {
-		t.Fatalf("expected ErrGetUserByID, got %v", err)
+	if len(got) != 2 {
+		t.Fatalf("expected filters to reach the repository, got %v", got)
 	}
 }
 
-func TestUserService_UpdateUser_InvalidName(t *testing.T) {
-	userID := uuid.NewString()
-	invalidName := "abc"
+func TestUserService_GetAllUserWithPagination_InvalidFilter(t *testing.T) {
+	cases := map[string]dto.FilterCondition{
+		"unknown field":     {Field: "password", Operator: "eq", Value: "x"},
+		"unknown operator":  {Field: "role", Operator: "regex", Value: "x"},
+		"invalid time":      {Field: "created_at", Operator: "gte", Value: "yesterday"},
+		"contains on time":  {Field: "created_at", Operator: "contains", Value: "2026"},
+		"null on not null":  {Field: "email", Operator: "null", Value: "true"},
+		"invalid null flag": {Field: "last_login_at", Operator: "null", Value: "maybe"},
+	}
+
+	for name, condition := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockUserRepo{
+				getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+					t.Fatal("repository must not be called with an invalid filter")
+					return dto.UserPaginationRepositoryResponse{}, nil
+				},
+			}
 
-	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{
-				ID: uuid.MustParse(id),
-			}, true, nil
-		},
+			us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+			_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+				PaginationRequest: dto.PaginationRequest{Filters: []dto.FilterCondition{condition}},
+			})
+			if !errors.Is(err, constants.ErrInvalidFilter) {
+				t.Fatalf("expected ErrInvalidFilter, got %v", err)
+			}
+		})
 	}
+}
 
-	us := NewUserService(repo, &mockJWTService{})
+func TestUserService_GetAllUserWithPagination_InvalidCursor(t *testing.T) {
+	valid, err := helpers.EncodeCursor(helpers.Cursor{Sort: "name", Values: []any{"alice", uuid.NewString()}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
-		ID:   userID,
-		Name: &invalidName,
-	})
+	_, signature, _ := strings.Cut(valid, ".")
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"-name","v":["zed","x"]}`)) + "." + signature
 
-	if !errors.Is(err, constants.ErrInvalidName) {
-		t.Fatalf("expected ErrInvalidName, got %v", err)
+	cases := map[string]dto.PaginationRequest{
+		"garbage":    {Cursor: "not-a-cursor"},
+		"tampered":   {Cursor: tampered},
+		"other sort": {Cursor: valid, Sort: "-name"},
 	}
-}
 
-func TestUserService_UpdateUser_InvalidEmail(t *testing.T) {
-	userID := uuid.NewString()
-	invalidEmail := "not-an-email"
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockUserRepo{
+				getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+					t.Fatal("repository must not be called with an invalid cursor")
+					return dto.UserPaginationRepositoryResponse{}, nil
+				},
+			}
 
-	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{
-				ID: uuid.MustParse(id),
-			}, true, nil
-		},
-	}
+			us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	us := NewUserService(repo, &mockJWTService{})
+			_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{PaginationRequest: req})
+			if !errors.Is(err, constants.ErrInvalidCursor) {
+				t.Fatalf("expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
 
-	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
-		ID:    userID,
-		Email: &invalidEmail,
-	})
+	us := NewUserService(&mockUserRepo{
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			return dto.UserPaginationRepositoryResponse{}, nil
+		},
+	}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	if !errors.Is(err, constants.ErrInvalidEmail) {
-		t.Fatalf("expected ErrInvalidEmail, got %v", err)
+	if _, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Cursor: valid, Limit: 5},
+	}); err != nil {
+		t.Fatalf("expected a cursor issued for the list to be accepted, got %v", err)
 	}
 }
 
-func TestUserService_UpdateUser_EmailAlreadyExists(t *testing.T) {
-	userID := uuid.NewString()
-	email := "existing@mail.com"
-
+func TestUserService_GetAllUserWithPagination_CursorMeta(t *testing.T) {
 	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{
-				ID: uuid.MustParse(id),
-			}, true, nil
-		},
-		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
-			return model.User{
-				ID: uuid.New(), 
-			}, true, nil
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			return dto.UserPaginationRepositoryResponse{
+				PaginationResponse: dto.PaginationResponse{PerPage: 5, Count: 12, NextCursor: "next", PrevCursor: "prev"},
+			}, nil
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
-		ID:    userID,
-		Email: &email,
+	res, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Limit: 5},
 	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	if !errors.Is(err, constants.ErrEmailAlreadyExists) {
-		t.Fatalf("expected ErrEmailAlreadyExists, got %v", err)
+	if res.NextCursor != "next" || res.PrevCursor != "prev" {
+		t.Fatalf("expected cursors in the meta, got %+v", res.PaginationResponse)
 	}
 }
 
-func TestUserService_UpdateUser_PasswordSame(t *testing.T) {
-	userID := uuid.NewString()
-	newPassword := "password"
-
-	hashed, _ := helpers.HashPassword(newPassword)
+func TestUserService_GetAllUserWithPagination_Highlights(t *testing.T) {
+	matched := model.User{ID: uuid.New(), Name: "Jane Doe", Email: "jane@example.com"}
+	other := model.User{ID: uuid.New(), Name: "John Roe", Email: "jane.fan@example.com"}
 
 	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{
-				ID:       uuid.MustParse(id),
-				Password: hashed,
-			}, true, nil
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			return dto.UserPaginationRepositoryResponse{
+				Users: []model.User{matched, other},
+				Highlights: map[string]map[string]string{
+					matched.ID.String(): {"name": "<mark>Jane</mark> Doe", "email": "<mark>jane</mark>@example.com"},
+				},
+			}, nil
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
-		ID:       userID,
-		Password: &newPassword,
+	res, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Search: "jane", Page: 1, PerPage: 10},
 	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	if !errors.Is(err, constants.ErrPasswordSame) {
-		t.Fatalf("expected ErrPasswordSame, got %v", err)
+	if res.Data[0].Highlights["name"] != "<mark>Jane</mark> Doe" {
+		t.Fatalf("expected the name highlight, got %+v", res.Data[0].Highlights)
+	}
+	if res.Data[1].Highlights != nil {
+		t.Fatalf("expected no highlights for an unmarked user, got %+v", res.Data[1].Highlights)
 	}
 }
 
-func TestUserService_UpdateUser_UpdateError(t *testing.T) {
-	userID := uuid.NewString()
+func TestUserService_GetAllUserWithPagination_PerPageCap(t *testing.T) {
+	t.Setenv("PAGINATION_MAX_PER_PAGE", "50")
 
+	var got dto.UserPaginationRequest
 	repo := &mockUserRepo{
-		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
-			return model.User{
-				ID: uuid.MustParse(id),
-			}, true, nil
-		},
-		updateFn: func(ctx context.Context, user model.User) error {
-			return errors.New("update error")
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			got = req
+			return dto.UserPaginationRepositoryResponse{}, nil
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
-	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
-		ID: userID,
+	_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{
+		PaginationRequest: dto.PaginationRequest{Page: 1, PerPage: 1000},
 	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	if !errors.Is(err, constants.ErrUpdateUser) {
-		t.Fatalf("expected ErrUpdateUser, got %v", err)
+	if got.PerPage != 50 {
+		t.Fatalf("expected per_page capped to 50, got %d", got.PerPage)
+	}
+}
+
+func TestUserService_GetAllUserWithPagination_NegativePage(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	for _, req := range []dto.PaginationRequest{{Page: -1}, {PerPage: -10}, {Limit: -1}} {
+		_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{PaginationRequest: req})
+		if !errors.Is(err, constants.ErrInvalidPagination) {
+			t.Fatalf("expected ErrInvalidPagination for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestUserService_GetAllUserWithPagination_InvalidFieldset(t *testing.T) {
+	called := false
+	repo := &mockUserRepo{
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			called = true
+			return dto.UserPaginationRepositoryResponse{}, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	cases := []struct {
+		name string
+		req  dto.UserPaginationRequest
+		want error
+	}{
+		{"unknown field", dto.UserPaginationRequest{Fields: "id,password"}, constants.ErrInvalidField},
+		{"unknown expand", dto.UserPaginationRequest{Expand: "organization"}, constants.ErrInvalidExpand},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := us.GetAllUserWithPagination(context.Background(), tc.req)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if called {
+		t.Fatal("repository should not be called for an invalid fieldset")
+	}
+}
+
+func TestUserService_GetAllUserWithPagination_Fieldset(t *testing.T) {
+	user := model.User{
+		ID:        uuid.New(),
+		Name:      "Jane Doe",
+		Addresses: []model.Address{{ID: uuid.New(), Type: constants.ENUM_ADDRESS_TYPE_HOME, Street: "Jl. Merdeka 1", City: "Jakarta", Country: "ID"}},
+	}
+
+	var got dto.UserPaginationRequest
+	repo := &mockUserRepo{
+		getAllWithPaginationFn: func(ctx context.Context, tx *gorm.DB, req dto.UserPaginationRequest) (dto.UserPaginationRepositoryResponse, error) {
+			got = req
+			return dto.UserPaginationRepositoryResponse{Users: []model.User{user}}, nil
+		},
+	}
+
+	loadedCustomFields := false
+	customFieldRepo := &mockCustomFieldRepo{
+		getValuesFn: func(ctx context.Context, userIDs []string) ([]model.UserCustomFieldValue, error) {
+			loadedCustomFields = true
+			return nil, nil
+		},
+	}
+
+	us := NewUserService(repo, customFieldRepo, &mockJWTService{}, &mockMailService{})
+
+	req := dto.UserPaginationRequest{Fields: "id, name", Expand: "addresses"}
+	res, err := us.GetAllUserWithPagination(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Fields != "id, name" || got.Expand != "addresses" {
+		t.Fatalf("expected the fieldset to reach the repository, got %+v", got)
+	}
+	if len(res.Data[0].Addresses) != 1 || res.Data[0].Addresses[0].City != "Jakarta" {
+		t.Fatalf("expected the expanded addresses, got %+v", res.Data[0].Addresses)
+	}
+	if loadedCustomFields {
+		t.Fatal("custom fields should not be loaded when fields= leaves them out")
+	}
+	if fields := req.ResponseFields(); !slices.Equal(fields, []string{"id", "name", "addresses"}) {
+		t.Fatalf("expected id, name and addresses in the response, got %v", fields)
+	}
+}
+
+// Get User By ID
+func TestUserService_GetUserByID_RepoError(t *testing.T) {
+	userID := uuid.NewString()
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{}, false, errors.New("db error")
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.GetuserByID(context.Background(), userID)
+
+	if !errors.Is(err, constants.ErrGetUserByID) {
+		t.Fatalf("expected ErrGetUserByID, got %v", err)
+	}
+
+	if resp.ID != uuid.Nil {
+		t.Fatalf("expected empty response, got %+v", resp)
+	}
+}
+func TestUserService_GetUserByID_InvalidUUID(t *testing.T) {
+	repo := &mockUserRepo{}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.GetuserByID(context.Background(), "invalid-uuid")
+
+	if !errors.Is(err, constants.ErrInvalidUUID) {
+		t.Fatalf("expected ErrInvalidUUID, got %v", err)
+	}
+
+	if resp.ID != uuid.Nil {
+		t.Fatalf("expected empty response, got %+v", resp)
+	}
+}
+
+// Profile
+func TestUserService_GetProfile_Success(t *testing.T) {
+	userID := uuid.New()
+	verifiedAt := time.Now()
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID:              userID,
+				Name:            "Current User",
+				Email:           "me@mail.com",
+				Role:            constants.ENUM_ROLE_ADMIN,
+				Status:          constants.ENUM_USER_STATUS_ACTIVE,
+				EmailVerifiedAt: &verifiedAt,
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.GetProfile(context.Background(), userID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.ID != userID || resp.Role != constants.ENUM_ROLE_ADMIN || !resp.EmailVerified {
+		t.Fatalf("unexpected profile: %+v", resp)
+	}
+}
+
+func TestUserService_GetProfile_NotFound(t *testing.T) {
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.GetProfile(context.Background(), uuid.NewString())
+	if !errors.Is(err, constants.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestUserService_GetProfile_DBError(t *testing.T) {
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{}, false, errors.New("connection refused")
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.GetProfile(context.Background(), uuid.NewString())
+	if !errors.Is(err, constants.ErrGetUserByID) {
+		t.Fatalf("expected ErrGetUserByID, got %v", err)
+	}
+	if kind := constants.ErrorOf(err).Kind; kind != constants.ENUM_ERROR_KIND_INTERNAL {
+		t.Fatalf("expected an internal error, got %s", kind)
+	}
+}
+
+// Update User
+func TestUserService_UpdateUser_GetUserByIDError(t *testing.T) {
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{}, false, errors.New("db error")
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID: uuid.New().String(),
+	})
+
+	if !errors.Is(err, constants.ErrGetUserByID) {
+		t.Fatalf("expected ErrGetUserByID, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUser_InvalidName(t *testing.T) {
+	userID := uuid.NewString()
+	invalidName := "abc"
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID: uuid.MustParse(id),
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:   userID,
+		Name: &invalidName,
+	})
+
+	if !errors.Is(err, constants.ErrInvalidName) {
+		t.Fatalf("expected ErrInvalidName, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUser_InvalidEmail(t *testing.T) {
+	userID := uuid.NewString()
+	invalidEmail := "not-an-email"
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID: uuid.MustParse(id),
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:    userID,
+		Email: &invalidEmail,
+	})
+
+	if !errors.Is(err, constants.ErrInvalidEmail) {
+		t.Fatalf("expected ErrInvalidEmail, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUser_EmailAlreadyExists(t *testing.T) {
+	userID := uuid.NewString()
+	email := "existing@mail.com"
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID: uuid.MustParse(id),
+			}, true, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{
+				ID: uuid.New(), 
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:    userID,
+		Email: &email,
+	})
+
+	if !errors.Is(err, constants.ErrEmailAlreadyExists) {
+		t.Fatalf("expected ErrEmailAlreadyExists, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUser_PasswordSame(t *testing.T) {
+	userID := uuid.NewString()
+	newPassword := "password"
+
+	hashed, _ := helpers.HashPassword(newPassword)
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID:       uuid.MustParse(id),
+				Password: hashed,
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:       userID,
+		Password: &newPassword,
+	})
+
+	if !errors.Is(err, constants.ErrPasswordSame) {
+		t.Fatalf("expected ErrPasswordSame, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUser_UpdateError(t *testing.T) {
+	userID := uuid.NewString()
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID: uuid.MustParse(id),
+			}, true, nil
+		},
+		updateFn: func(ctx context.Context, user model.User) error {
+			return errors.New("update error")
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID: userID,
+	})
+
+	if !errors.Is(err, constants.ErrUpdateUser) {
+		t.Fatalf("expected ErrUpdateUser, got %v", err)
 	}
 }
 
@@ -803,7 +1416,7 @@ func TestUserService_UpdateUser_Success(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	resp, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
 		ID:   userID,
@@ -833,7 +1446,7 @@ func TestUserService_DeleteUser_GetUserByIDError(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.DeleteUser(context.Background(), dto.DeleteUserRequest{
 		UserID: userID,
@@ -858,7 +1471,7 @@ func TestUserService_DeleteUser_DeleteError(t *testing.T) {
 		},
 	}
 
-	us := NewUserService(repo, &mockJWTService{})
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
 
 	_, err := us.DeleteUser(context.Background(), dto.DeleteUserRequest{
 		UserID: userID,
@@ -869,3 +1482,1501 @@ func TestUserService_DeleteUser_DeleteError(t *testing.T) {
 	}
 }
 
+// Trash
+func TestUserService_RestoreUser_NotInTrash(t *testing.T) {
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.RestoreUser(context.Background(), dto.RestoreUserRequest{
+		UserID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrUserNotInTrash) {
+		t.Fatalf("expected ErrUserNotInTrash, got %v", err)
+	}
+}
+
+func TestUserService_RestoreUser_EmailConflict(t *testing.T) {
+	restored := false
+
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(userID), Email: "test@mail.com"}, true, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{ID: uuid.New(), Email: email}, true, nil
+		},
+		restoreByIDFn: func(ctx context.Context, userID string) error {
+			restored = true
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.RestoreUser(context.Background(), dto.RestoreUserRequest{
+		UserID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrRestoreEmailConflict) {
+		t.Fatalf("expected ErrRestoreEmailConflict, got %v", err)
+	}
+
+	if restored {
+		t.Fatalf("expected restore not to be called on email conflict")
+	}
+}
+
+func TestUserService_RestoreUser_EmailLookupError(t *testing.T) {
+	restored := false
+
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(userID), Email: "test@mail.com"}, true, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, errors.New("db error")
+		},
+		restoreByIDFn: func(ctx context.Context, userID string) error {
+			restored = true
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.RestoreUser(context.Background(), dto.RestoreUserRequest{
+		UserID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrRestoreUser) {
+		t.Fatalf("expected ErrRestoreUser, got %v", err)
+	}
+
+	if restored {
+		t.Fatalf("expected restore not to be called when the email check fails")
+	}
+}
+
+func TestUserService_RestoreUser_Success(t *testing.T) {
+	userID := uuid.NewString()
+
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id), Email: "test@mail.com"}, true, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.RestoreUser(context.Background(), dto.RestoreUserRequest{
+		UserID: userID,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.ID.String() != userID {
+		t.Fatalf("expected ID %s, got %s", userID, resp.ID)
+	}
+}
+
+func TestUserService_PurgeUser_NotInTrash(t *testing.T) {
+	purged := false
+
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+		purgeByIDFn: func(ctx context.Context, userID string) ([]string, error) {
+			purged = true
+			return nil, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.PurgeUser(context.Background(), dto.PurgeUserRequest{
+		UserID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrUserNotInTrash) {
+		t.Fatalf("expected ErrUserNotInTrash, got %v", err)
+	}
+
+	if purged {
+		t.Fatalf("expected purge not to be called for a live user")
+	}
+}
+
+func TestUserService_PurgeUser_Error(t *testing.T) {
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(userID)}, true, nil
+		},
+		purgeByIDFn: func(ctx context.Context, userID string) ([]string, error) {
+			return nil, errors.New("purge error")
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.PurgeUser(context.Background(), dto.PurgeUserRequest{
+		UserID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrPurgeUser) {
+		t.Fatalf("expected ErrPurgeUser, got %v", err)
+	}
+}
+
+func TestUserService_PurgeUser_RemovesDataExportArchives(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "export.zip")
+	if err := os.WriteFile(archive, []byte("zip"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(userID)}, true, nil
+		},
+		purgeByIDFn: func(ctx context.Context, userID string) ([]string, error) {
+			return []string{archive}, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	if _, err := us.PurgeUser(context.Background(), dto.PurgeUserRequest{UserID: uuid.NewString()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(archive); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the archive to be removed, got %v", err)
+	}
+}
+
+// Account Deletion
+func TestUserService_RequestAccountDeletion_Success(t *testing.T) {
+	userID := uuid.NewString()
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id)}, true, nil
+		},
+		scheduleDeletionFn: func(ctx context.Context, id string, requestedAt, scheduledAt time.Time) error {
+			if !scheduledAt.After(requestedAt) {
+				t.Fatalf("expected deletion to be scheduled after the request")
+			}
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.RequestAccountDeletion(context.Background(), dto.AccountDeletionRequest{
+		UserID: userID,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	grace := resp.DeletionScheduledAt.Sub(resp.DeletionRequestedAt)
+	if grace != constants.ENUM_ACCOUNT_DELETION_GRACE_DAYS*24*time.Hour {
+		t.Fatalf("expected default grace period, got %s", grace)
+	}
+}
+
+func TestUserService_Login_CancelsPendingDeletion(t *testing.T) {
+	hashed, _ := helpers.HashPassword("password123")
+	scheduledAt := time.Now().Add(time.Hour)
+	cancelled := false
+
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{
+				ID:                  uuid.New(),
+				Email:               email,
+				Password:            hashed,
+				DeletionScheduledAt: &scheduledAt,
+			}, true, nil
+		},
+		cancelDeletionFn: func(ctx context.Context, userID string) error {
+			cancelled = true
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.Login(context.Background(), dto.LoginUserRequest{
+		Email:    "test@mail.com",
+		Password: "password123",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !cancelled {
+		t.Fatalf("expected pending deletion to be cancelled on login")
+	}
+}
+
+func TestUserService_EraseDueAccounts_AnonymizesPII(t *testing.T) {
+	userID := uuid.New()
+	archive := filepath.Join(t.TempDir(), "export.zip")
+	if err := os.WriteFile(archive, []byte("zip"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &mockUserRepo{
+		getDueForErasureFn: func(ctx context.Context, now time.Time, limit int) ([]model.User, error) {
+			return []model.User{{
+				ID:          userID,
+				Name:        "Som User",
+				Email:       "test@mail.com",
+				PhoneNumber: "0862323213331",
+				Address:     "San Diego, Mexico Selatan",
+			}}, nil
+		},
+		anonymizeFn: func(ctx context.Context, user model.User) ([]string, error) {
+			if user.ID != userID {
+				t.Fatalf("expected ID to be kept, got %s", user.ID)
+			}
+			if user.Name != constants.ENUM_ANONYMIZED_NAME || user.PhoneNumber != "" || user.Address != "" {
+				t.Fatalf("expected PII to be cleared, got %+v", user)
+			}
+			if !strings.HasSuffix(user.Email, "@"+constants.ENUM_ANONYMIZED_EMAIL_DOMAIN) {
+				t.Fatalf("expected anonymized email, got %s", user.Email)
+			}
+			if user.AnonymizedAt == nil {
+				t.Fatalf("expected anonymized_at to be set")
+			}
+			return []string{archive}, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	erased, err := us.EraseDueAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if erased != 1 {
+		t.Fatalf("expected 1 erased account, got %d", erased)
+	}
+
+	if _, err := os.Stat(archive); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the data export archive to be removed, got %v", err)
+	}
+}
+
+func TestUserService_RestoreUser_Anonymized(t *testing.T) {
+	anonymizedAt := time.Now()
+
+	repo := &mockUserRepo{
+		getTrashedByIDFn: func(ctx context.Context, userID string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(userID), AnonymizedAt: &anonymizedAt}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.RestoreUser(context.Background(), dto.RestoreUserRequest{
+		UserID: uuid.NewString(),
+	})
+
+	if !errors.Is(err, constants.ErrRestoreAnonymizedUser) {
+		t.Fatalf("expected ErrRestoreAnonymizedUser, got %v", err)
+	}
+}
+
+// Import Users
+func TestUserService_ImportUsers_BestEffortCSV(t *testing.T) {
+	var created []string
+
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			if email == "exists@mail.com" {
+				return model.User{ID: uuid.New(), Email: email}, true, nil
+			}
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+		createFn: func(ctx context.Context, user model.User) error {
+			if user.Role != constants.ENUM_ROLE_USER {
+				t.Fatalf("expected default role user, got %q", user.Role)
+			}
+			created = append(created, user.Email)
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	csvContent := "email,name,password\n" +
+		"first@mail.com,First User,password123\n" +
+		"exists@mail.com,Existing User,password123\n" +
+		"bad@mail.com,abc,password123\n" +
+		"FIRST@mail.com,Duplicate User,password123\n"
+
+	resp, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_CSV,
+		Mode:    constants.ENUM_IMPORT_MODE_BEST_EFFORT,
+		Content: []byte(csvContent),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !resp.Committed || resp.Created != 1 || resp.Skipped != 2 || resp.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+
+	if resp.Rows[2].Row != 3 || resp.Rows[2].Error != constants.ErrInvalidName.Error() {
+		t.Fatalf("expected row 3 to fail on name, got %+v", resp.Rows[2])
+	}
+
+	if len(created) != 1 || created[0] != "first@mail.com" {
+		t.Fatalf("expected only first@mail.com to be created, got %v", created)
+	}
+}
+
+func TestUserService_ImportUsers_AtomicSkipsTransactionOnInvalidRow(t *testing.T) {
+	repo := &mockUserRepo{
+		transactionFn: func(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+			t.Fatalf("expected no transaction when a row is invalid")
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_JSON,
+		Content: []byte(`[{"name":"Valid User","email":"valid@mail.com","password":"password123"},{"name":"Bad","email":"bad@mail.com","password":"password123"}]`),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Committed || resp.Mode != constants.ENUM_IMPORT_MODE_ATOMIC || resp.Created != 1 || resp.Failed != 1 {
+		t.Fatalf("expected all_or_nothing import not to commit, got %+v", resp)
+	}
+
+	if resp.Rows[0].ID != nil {
+		t.Fatalf("expected no ID for a row that was not inserted, got %v", resp.Rows[0].ID)
+	}
+}
+
+func TestUserService_ImportUsers_AtomicRollsBackOnFailedInsert(t *testing.T) {
+	var outerErr error
+
+	repo := &mockUserRepo{
+		transactionFn: func(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+			err := fn(tx)
+			if tx == nil {
+				outerErr = err
+			}
+			return err
+		},
+		createFn: func(ctx context.Context, user model.User) error {
+			if user.Email == "broken@mail.com" {
+				return errors.New("db error")
+			}
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_JSON,
+		Content: []byte(`[{"name":"Valid User","email":"valid@mail.com","password":"password123"},{"name":"Broken User","email":"broken@mail.com","password":"password123"}]`),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Committed || resp.Created != 1 || resp.Failed != 1 {
+		t.Fatalf("expected all_or_nothing import not to commit, got %+v", resp)
+	}
+
+	if outerErr == nil {
+		t.Fatalf("expected outer transaction to be rolled back")
+	}
+}
+
+func TestUserService_ImportUsers_HashesBeforeTransaction(t *testing.T) {
+	inTransaction := false
+	var created model.User
+
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			if inTransaction {
+				t.Fatalf("expected rows to be checked before the transaction")
+			}
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+		transactionFn: func(ctx context.Context, tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+			inTransaction = true
+			return fn(tx)
+		},
+		createFn: func(ctx context.Context, user model.User) error {
+			created = user
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	if _, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_JSON,
+		Content: []byte(`[{"name":"Valid User","email":"valid@mail.com","password":"password123"}]`),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ok, _ := helpers.CheckPassword(created.Password, []byte("password123")); !ok {
+		t.Fatalf("expected the password to be hashed before the insert, got %q", created.Password)
+	}
+}
+
+func TestUserService_ImportUsers_SkipsEmailTakenDuringImport(t *testing.T) {
+	repo := &mockUserRepo{
+		createFn: func(ctx context.Context, user model.User) error {
+			return gorm.ErrDuplicatedKey
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_JSON,
+		Mode:    constants.ENUM_IMPORT_MODE_BEST_EFFORT,
+		Content: []byte(`[{"name":"Valid User","email":"valid@mail.com","password":"password123"}]`),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Skipped != 1 || resp.Rows[0].Error != constants.ErrEmailAlreadyExists.Error() {
+		t.Fatalf("expected the row to be skipped, got %+v", resp)
+	}
+}
+
+func TestUserService_ImportUsers_DryRun(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_JSON,
+		DryRun:  true,
+		Content: []byte(`[{"name":"Valid User","email":"valid@mail.com","password":"password123"}]`),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Committed || resp.Created != 1 {
+		t.Fatalf("expected dry run to report without committing, got %+v", resp)
+	}
+
+	if resp.Rows[0].ID != nil {
+		t.Fatalf("expected no ID for a row that was rolled back, got %v", resp.Rows[0].ID)
+	}
+}
+
+func TestUserService_ImportUsers_ValidatesCustomFields(t *testing.T) {
+	employeeID := model.CustomField{ID: uuid.New(), Key: "employee_id", Type: constants.ENUM_CUSTOM_FIELD_TYPE_STRING, Required: true}
+	seniority := model.CustomField{ID: uuid.New(), Key: "seniority", Type: constants.ENUM_CUSTOM_FIELD_TYPE_NUMBER}
+
+	var saved []model.UserCustomFieldValue
+	customFieldRepo := &mockCustomFieldRepo{
+		getAllFn: func(ctx context.Context) ([]model.CustomField, error) {
+			return []model.CustomField{employeeID, seniority}, nil
+		},
+		saveValuesFn: func(ctx context.Context, values []model.UserCustomFieldValue) error {
+			saved = append(saved, values...)
+			return nil
+		},
+	}
+
+	us := NewUserService(&mockUserRepo{}, customFieldRepo, &mockJWTService{}, &mockMailService{})
+
+	csvContent := "name,email,password,custom_fields.employee_id,custom_fields.seniority\n" +
+		"First User,first@mail.com,password123,E-1,3\n" +
+		"Second User,second@mail.com,password123,,2\n" +
+		"Third User,third@mail.com,password123,E-3,senior\n"
+
+	resp, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_CSV,
+		Mode:    constants.ENUM_IMPORT_MODE_BEST_EFFORT,
+		Content: []byte(csvContent),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Created != 1 || resp.Failed != 2 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+
+	if !strings.HasPrefix(resp.Rows[1].Error, constants.ErrCustomFieldRequired.Error()) {
+		t.Fatalf("expected row 2 to fail on the required field, got %+v", resp.Rows[1])
+	}
+
+	if !strings.HasPrefix(resp.Rows[2].Error, constants.ErrInvalidCustomFieldValue.Error()) {
+		t.Fatalf("expected row 3 to fail on the number field, got %+v", resp.Rows[2])
+	}
+
+	values := map[uuid.UUID]string{}
+	for _, value := range saved {
+		if resp.Rows[0].ID == nil || value.UserID != *resp.Rows[0].ID {
+			t.Fatalf("expected values saved for the created user only, got %+v", value)
+		}
+		values[value.FieldID] = value.Value
+	}
+	if values[employeeID.ID] != "E-1" || values[seniority.ID] != "3" {
+		t.Fatalf("unexpected custom field values: %v", values)
+	}
+}
+
+func TestUserService_ImportUsers_InvalidFile(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  constants.ENUM_IMPORT_FORMAT_CSV,
+		Content: []byte("name,email\nSom User,test@mail.com\n"),
+	})
+
+	if !errors.Is(err, constants.ErrParseImportFile) {
+		t.Fatalf("expected ErrParseImportFile, got %v", err)
+	}
+
+	_, err = us.ImportUsers(context.Background(), dto.ImportUserRequest{
+		Format:  "xml",
+		Content: []byte("<users/>"),
+	})
+
+	if !errors.Is(err, constants.ErrInvalidImportFormat) {
+		t.Fatalf("expected ErrInvalidImportFormat, got %v", err)
+	}
+}
+
+// Export Users
+func exportBatches(batches ...[]model.User) func(ctx context.Context, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error {
+	return func(ctx context.Context, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error {
+		for _, batch := range batches {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestUserService_ExportUsers_CSV(t *testing.T) {
+	repo := &mockUserRepo{
+		streamUsersFn: exportBatches(
+			[]model.User{{ID: uuid.New(), Name: "First User", Email: "first@mail.com"}},
+			[]model.User{{ID: uuid.New(), Name: "=cmd()", Email: "second@mail.com"}},
+		),
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	var buf bytes.Buffer
+	total, err := us.ExportUsers(context.Background(), dto.ExportUserRequest{
+		Format: constants.ENUM_EXPORT_FORMAT_CSV,
+	}, &buf)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if total != 2 {
+		t.Fatalf("expected 2 rows, got %d", total)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "id,name,email") {
+		t.Fatalf("unexpected csv output: %q", buf.String())
+	}
+
+	if !strings.Contains(lines[2], ",'=cmd(),") {
+		t.Fatalf("expected formula to be neutralized, got %q", lines[2])
+	}
+}
+
+func TestUserService_ExportUsers_XLSX(t *testing.T) {
+	repo := &mockUserRepo{
+		streamUsersFn: exportBatches(
+			[]model.User{{ID: uuid.New(), Name: "Tom & Jerry", Email: "first@mail.com"}},
+		),
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	var buf bytes.Buffer
+	if _, err := us.ExportUsers(context.Background(), dto.ExportUserRequest{
+		Format: constants.ENUM_EXPORT_FORMAT_XLSX,
+	}, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("expected a valid zip archive: %v", err)
+	}
+
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, _ := file.Open()
+		content, _ := io.ReadAll(r)
+		r.Close()
+		sheet = string(content)
+	}
+
+	if !strings.Contains(sheet, "Tom &amp; Jerry") || !strings.HasSuffix(sheet, "</worksheet>") {
+		t.Fatalf("unexpected sheet content: %q", sheet)
+	}
+}
+
+func TestUserService_ExportUsers_InvalidFormat(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	var buf bytes.Buffer
+	_, err := us.ExportUsers(context.Background(), dto.ExportUserRequest{Format: "pdf"}, &buf)
+
+	if !errors.Is(err, constants.ErrInvalidExportFormat) {
+		t.Fatalf("expected ErrInvalidExportFormat, got %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written for invalid format")
+	}
+}
+
+func TestUserService_ExportUsers_InvalidFilter(t *testing.T) {
+	repo := &mockUserRepo{
+		streamUsersFn: func(ctx context.Context, req dto.ExportUserRequest, batchSize int, fn func(users []model.User) error) error {
+			t.Fatalf("expected no query for an invalid filter")
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	var buf bytes.Buffer
+	_, err := us.ExportUsers(context.Background(), dto.ExportUserRequest{
+		Format:  constants.ENUM_EXPORT_FORMAT_CSV,
+		Filters: []dto.FilterCondition{{Field: "password", Operator: "eq", Value: "secret"}},
+	}, &buf)
+
+	if !errors.Is(err, constants.ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written for an invalid filter")
+	}
+}
+
+// Bulk Operations
+func usersFromIDs(ctx context.Context, userIDs []string) ([]model.User, error) {
+	users := make([]model.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, model.User{ID: uuid.MustParse(id)})
+	}
+	return users, nil
+}
+
+func TestUserService_BulkUpdateUsers_Success(t *testing.T) {
+	existingID := uuid.NewString()
+	missingID := uuid.NewString()
+	address := ""
+
+	repo := &mockUserRepo{
+		getByIDsFn: func(ctx context.Context, userIDs []string) ([]model.User, error) {
+			return []model.User{{ID: uuid.MustParse(existingID)}}, nil
+		},
+		updateColumnsFn: func(ctx context.Context, userID string, columns map[string]any) error {
+			if value, ok := columns["address"]; !ok || value != "" {
+				t.Fatalf("expected address to be cleared, got %v", columns)
+			}
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.BulkUpdateUsers(context.Background(), dto.BulkUpdateUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{IDs: []string{existingID, missingID, existingID}},
+		Fields:           dto.BulkUpdateUserFields{Address: &address},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !resp.Committed || resp.Total != 2 || resp.Succeeded != 1 || resp.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+
+	if resp.Items[1].Status != constants.ENUM_BULK_STATUS_NOT_FOUND {
+		t.Fatalf("expected missing ID to be not_found, got %+v", resp.Items[1])
+	}
+}
+
+func TestUserService_BulkUpdateUsers_RollsBackOnFailure(t *testing.T) {
+	failingID := uuid.NewString()
+	role := constants.ENUM_ROLE_ADMIN
+
+	repo := &mockUserRepo{
+		getByIDsFn: usersFromIDs,
+		updateColumnsFn: func(ctx context.Context, userID string, columns map[string]any) error {
+			if userID == failingID {
+				return errors.New("update error")
+			}
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.BulkUpdateUsers(context.Background(), dto.BulkUpdateUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{IDs: []string{uuid.NewString(), failingID}},
+		Fields:           dto.BulkUpdateUserFields{Role: &role},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Committed || resp.Failed != 1 || resp.Items[1].Status != constants.ENUM_BULK_STATUS_FAILED {
+		t.Fatalf("expected batch to be rolled back with one failure, got %+v", resp)
+	}
+}
+
+func TestUserService_BulkUpdateUsers_InvalidRequest(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	role := "superuser"
+	address := "Somewhere"
+
+	_, err := us.BulkUpdateUsers(context.Background(), dto.BulkUpdateUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{IDs: []string{uuid.NewString()}},
+		Fields:           dto.BulkUpdateUserFields{Role: &role},
+	})
+	if !errors.Is(err, constants.ErrInvalidRole) {
+		t.Fatalf("expected ErrInvalidRole, got %v", err)
+	}
+
+	_, err = us.BulkUpdateUsers(context.Background(), dto.BulkUpdateUserRequest{
+		Fields: dto.BulkUpdateUserFields{Address: &address},
+	})
+	if !errors.Is(err, constants.ErrBulkInvalidSelection) {
+		t.Fatalf("expected ErrBulkInvalidSelection, got %v", err)
+	}
+
+	_, err = us.BulkUpdateUsers(context.Background(), dto.BulkUpdateUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{IDs: []string{"not-a-uuid"}},
+		Fields:           dto.BulkUpdateUserFields{Address: &address},
+	})
+	if !errors.Is(err, constants.ErrInvalidUUID) {
+		t.Fatalf("expected ErrInvalidUUID, got %v", err)
+	}
+}
+
+func TestUserService_BulkDeleteUsers_FilterTooLarge(t *testing.T) {
+	repo := &mockUserRepo{
+		getIDsByFilterFn: func(ctx context.Context, filter dto.BulkUserFilter, limit int) ([]string, error) {
+			userIDs := make([]string, limit)
+			for i := range userIDs {
+				userIDs[i] = uuid.NewString()
+			}
+			return userIDs, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.BulkDeleteUsers(context.Background(), dto.BulkDeleteUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{Filter: &dto.BulkUserFilter{Role: constants.ENUM_ROLE_USER}},
+	})
+
+	if !errors.Is(err, constants.ErrBulkTooManyItems) {
+		t.Fatalf("expected ErrBulkTooManyItems, got %v", err)
+	}
+}
+
+func TestUserService_BulkDeleteUsers_FilterUsesListFilters(t *testing.T) {
+	var selected dto.BulkUserFilter
+
+	repo := &mockUserRepo{
+		getIDsByFilterFn: func(ctx context.Context, filter dto.BulkUserFilter, limit int) ([]string, error) {
+			selected = filter
+			return nil, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.BulkDeleteUsers(context.Background(), dto.BulkDeleteUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{Filter: &dto.BulkUserFilter{
+			InactiveDays: 30,
+			Filters:      []dto.FilterCondition{{Field: "status", Value: constants.ENUM_USER_STATUS_SUSPENDED}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if selected.InactiveDays != 30 || len(selected.Filters) != 1 || selected.Filters[0].Operator != constants.ENUM_FILTER_OPERATOR_EQ {
+		t.Fatalf("expected the list filters to reach the repository with eq as default operator, got %+v", selected)
+	}
+
+	_, err = us.BulkDeleteUsers(context.Background(), dto.BulkDeleteUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{Filter: &dto.BulkUserFilter{
+			Filters: []dto.FilterCondition{{Field: "password", Operator: "eq", Value: "x"}},
+		}},
+	})
+	if !errors.Is(err, constants.ErrInvalidFilter) {
+		t.Fatalf("expected ErrInvalidFilter, got %v", err)
+	}
+}
+
+func TestUserService_BulkDeleteUsers_SkipsSelf(t *testing.T) {
+	adminID := uuid.NewString()
+	var deleted []string
+
+	repo := &mockUserRepo{
+		getByIDsFn: usersFromIDs,
+		deleteByIDFn: func(ctx context.Context, userID string) error {
+			deleted = append(deleted, userID)
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.BulkDeleteUsers(context.Background(), dto.BulkDeleteUserRequest{
+		BulkUserSelector: dto.BulkUserSelector{IDs: []string{adminID, uuid.NewString()}},
+		RequestedBy:      adminID,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !resp.Committed || resp.Succeeded != 1 || resp.Items[0].Status != constants.ENUM_BULK_STATUS_SKIPPED {
+		t.Fatalf("expected own account to be skipped, got %+v", resp)
+	}
+
+	if len(deleted) != 1 || deleted[0] == adminID {
+		t.Fatalf("expected only the other user to be deleted, got %v", deleted)
+	}
+}
+
+// Account Status
+func TestUserService_UpdateUserStatus_Suspend(t *testing.T) {
+	until := time.Now().Add(24 * time.Hour)
+	var columns map[string]any
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id), Status: constants.ENUM_USER_STATUS_ACTIVE}, true, nil
+		},
+		updateColumnsFn: func(ctx context.Context, userID string, cols map[string]any) error {
+			columns = cols
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.UpdateUserStatus(context.Background(), dto.UpdateUserStatusRequest{
+		UserID:         uuid.NewString(),
+		ChangedBy:      uuid.NewString(),
+		Status:         constants.ENUM_USER_STATUS_SUSPENDED,
+		Reason:         "spam",
+		SuspendedUntil: &until,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Status != constants.ENUM_USER_STATUS_SUSPENDED || columns["status_reason"] != "spam" {
+		t.Fatalf("unexpected status change: %+v %v", resp, columns)
+	}
+}
+
+func TestUserService_UpdateUserStatus_InvalidRequests(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id), Status: constants.ENUM_USER_STATUS_BANNED}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	cases := []struct {
+		name string
+		req  dto.UpdateUserStatusRequest
+		want error
+	}{
+		{"unknown status", dto.UpdateUserStatusRequest{Status: "frozen"}, constants.ErrInvalidUserStatus},
+		{"banned to suspended", dto.UpdateUserStatusRequest{Status: constants.ENUM_USER_STATUS_SUSPENDED, Reason: "x", SuspendedUntil: &past}, constants.ErrInvalidStatusTransition},
+	}
+
+	for _, tc := range cases {
+		tc.req.UserID = uuid.NewString()
+		tc.req.ChangedBy = uuid.NewString()
+		if _, err := us.UpdateUserStatus(context.Background(), tc.req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	adminID := uuid.NewString()
+	if _, err := us.UpdateUserStatus(context.Background(), dto.UpdateUserStatusRequest{
+		UserID:    adminID,
+		ChangedBy: adminID,
+		Status:    constants.ENUM_USER_STATUS_BANNED,
+	}); !errors.Is(err, constants.ErrChangeOwnStatus) {
+		t.Fatalf("expected ErrChangeOwnStatus, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUserStatus_RequiresReasonAndFutureDate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id)}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.UpdateUserStatus(context.Background(), dto.UpdateUserStatusRequest{
+		UserID:    uuid.NewString(),
+		ChangedBy: uuid.NewString(),
+		Status:    constants.ENUM_USER_STATUS_BANNED,
+	})
+	if !errors.Is(err, constants.ErrStatusReasonRequired) {
+		t.Fatalf("expected ErrStatusReasonRequired, got %v", err)
+	}
+
+	_, err = us.UpdateUserStatus(context.Background(), dto.UpdateUserStatusRequest{
+		UserID:         uuid.NewString(),
+		ChangedBy:      uuid.NewString(),
+		Status:         constants.ENUM_USER_STATUS_SUSPENDED,
+		Reason:         "spam",
+		SuspendedUntil: &past,
+	})
+	if !errors.Is(err, constants.ErrInvalidSuspendedUntil) {
+		t.Fatalf("expected ErrInvalidSuspendedUntil, got %v", err)
+	}
+}
+
+func TestUserService_Login_Suspended(t *testing.T) {
+	hashed, _ := helpers.HashPassword("password123")
+	until := time.Now().Add(time.Hour)
+
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{
+				ID:             uuid.New(),
+				Password:       hashed,
+				Status:         constants.ENUM_USER_STATUS_SUSPENDED,
+				SuspendedUntil: &until,
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.Login(context.Background(), dto.LoginUserRequest{
+		Email:    "test@mail.com",
+		Password: "password123",
+	})
+
+	if !errors.Is(err, constants.ErrAccountSuspended) {
+		t.Fatalf("expected ErrAccountSuspended, got %v", err)
+	}
+}
+
+func TestUserService_CheckUserStatus_AllowsExpiredSuspension(t *testing.T) {
+	until := time.Now().Add(-time.Minute)
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{
+				ID:             uuid.MustParse(id),
+				Status:         constants.ENUM_USER_STATUS_SUSPENDED,
+				SuspendedUntil: &until,
+			}, true, nil
+		},
+		updateColumnsFn: func(ctx context.Context, userID string, columns map[string]any) error {
+			t.Fatalf("expected the suspension expiry job, not the request, to reactivate the user")
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	if err := us.CheckUserStatus(context.Background(), uuid.NewString()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUserService_CheckUserStatus_CachesStatus(t *testing.T) {
+	reads := 0
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			reads++
+			return model.User{ID: uuid.MustParse(id)}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	userID := uuid.NewString()
+
+	for range 3 {
+		if err := us.CheckUserStatus(context.Background(), userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if reads != 1 {
+		t.Fatalf("expected a single read while the status is cached, got %d", reads)
+	}
+
+	_, err := us.UpdateUserStatus(context.Background(), dto.UpdateUserStatusRequest{
+		UserID:    userID,
+		ChangedBy: uuid.NewString(),
+		Status:    constants.ENUM_USER_STATUS_BANNED,
+		Reason:    "spam",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	us.CheckUserStatus(context.Background(), userID)
+	if reads != 3 {
+		t.Fatalf("expected a status change to clear the cached status, got %d reads", reads)
+	}
+}
+
+func TestUserService_CheckUserStatus_Banned(t *testing.T) {
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id), Status: constants.ENUM_USER_STATUS_BANNED}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	if err := us.CheckUserStatus(context.Background(), uuid.NewString()); !errors.Is(err, constants.ErrAccountBanned) {
+		t.Fatalf("expected ErrAccountBanned, got %v", err)
+	}
+}
+
+// Email Change
+func TestUserService_UpdateUser_EmailChangePending(t *testing.T) {
+	newEmail := "new@mail.com"
+	var updated model.User
+	sent := map[string]string{}
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id), Name: "Valid Name", Email: "old@mail.com"}, true, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+		updateFn: func(ctx context.Context, user model.User) error {
+			updated = user
+			return nil
+		},
+	}
+
+	mail := &mockMailService{
+		sendFn: func(to, subject, body string) error {
+			sent[to] = body
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, mail)
+
+	resp, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:    uuid.NewString(),
+		Email: &newEmail,
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Email != "old@mail.com" || resp.PendingEmail != newEmail {
+		t.Fatalf("expected email change to be pending, got %+v", resp)
+	}
+
+	if updated.Email != "old@mail.com" || updated.EmailChangeToken == "" || updated.EmailChangeExpiresAt == nil {
+		t.Fatalf("expected pending change to be stored, got %+v", updated)
+	}
+
+	if !strings.Contains(sent[newEmail], "/api/confirm-email?token=") {
+		t.Fatalf("expected confirmation link sent to new address")
+	}
+
+	if _, ok := sent["old@mail.com"]; !ok {
+		t.Fatalf("expected notice sent to old address")
+	}
+
+	if strings.Contains(sent[newEmail], updated.EmailChangeToken) {
+		t.Fatalf("expected only the token hash to be stored")
+	}
+}
+
+func TestUserService_UpdateUser_EmailChangeSendError(t *testing.T) {
+	newEmail := "new@mail.com"
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id), Email: "old@mail.com"}, true, nil
+		},
+	}
+
+	mail := &mockMailService{
+		sendFn: func(to, subject, body string) error {
+			return constants.ErrMailNotConfigured
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, mail)
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:    uuid.NewString(),
+		Email: &newEmail,
+	})
+
+	if !errors.Is(err, constants.ErrSendEmailConfirmation) {
+		t.Fatalf("expected ErrSendEmailConfirmation, got %v", err)
+	}
+}
+
+func TestUserService_ConfirmEmailChange_Success(t *testing.T) {
+	token := "confirm-token"
+	expiresAt := time.Now().Add(time.Hour)
+	var columns map[string]any
+
+	repo := &mockUserRepo{
+		getByEmailChangeFn: func(ctx context.Context, tokenHash string) (model.User, bool, error) {
+			if tokenHash != helpers.HashToken(token) {
+				return model.User{}, false, gorm.ErrRecordNotFound
+			}
+			return model.User{
+				ID:                   uuid.New(),
+				Email:                "old@mail.com",
+				PendingEmail:         "new@mail.com",
+				EmailChangeToken:     tokenHash,
+				EmailChangeExpiresAt: &expiresAt,
+			}, true, nil
+		},
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+		updateColumnsFn: func(ctx context.Context, userID string, cols map[string]any) error {
+			columns = cols
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	resp, err := us.ConfirmEmailChange(context.Background(), dto.ConfirmEmailChangeRequest{Token: token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Email != "new@mail.com" || columns["email"] != "new@mail.com" || columns["email_change_token"] != "" {
+		t.Fatalf("expected email to be switched, got %+v %v", resp, columns)
+	}
+
+	if columns["email_verified_at"] == nil {
+		t.Fatalf("expected confirmed email to be marked verified, got %v", columns)
+	}
+}
+
+func TestUserService_ConfirmEmailChange_InvalidToken(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.ConfirmEmailChange(context.Background(), dto.ConfirmEmailChangeRequest{Token: "unknown"})
+	if !errors.Is(err, constants.ErrInvalidEmailChangeToken) {
+		t.Fatalf("expected ErrInvalidEmailChangeToken, got %v", err)
+	}
+}
+
+func TestUserService_ConfirmEmailChange_Expired(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Minute)
+
+	repo := &mockUserRepo{
+		getByEmailChangeFn: func(ctx context.Context, tokenHash string) (model.User, bool, error) {
+			return model.User{
+				ID:                   uuid.New(),
+				PendingEmail:         "new@mail.com",
+				EmailChangeExpiresAt: &expiresAt,
+			}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.ConfirmEmailChange(context.Background(), dto.ConfirmEmailChangeRequest{Token: "token"})
+	if !errors.Is(err, constants.ErrEmailChangeTokenExpired) {
+		t.Fatalf("expected ErrEmailChangeTokenExpired, got %v", err)
+	}
+}
+
+// Phone Number
+func TestUserService_CreateUser_NormalizesPhoneNumber(t *testing.T) {
+	var created model.User
+
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+		createFn: func(ctx context.Context, user model.User) error {
+			created = user
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	us.phoneRegion = "ID"
+
+	resp, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
+		Name:        "Valid Name",
+		Email:       "valid@mail.com",
+		Password:    "password123",
+		PhoneNumber: "0812-3456-7890",
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if created.PhoneNumber != "+6281234567890" || resp.PhoneNumber != "+6281234567890" {
+		t.Fatalf("expected E.164 phone number, got %q", created.PhoneNumber)
+	}
+}
+
+func TestUserService_CreateUser_InvalidPhoneNumber(t *testing.T) {
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{}, false, gorm.ErrRecordNotFound
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
+		Name:        "Valid Name",
+		Email:       "valid@mail.com",
+		Password:    "password123",
+		PhoneNumber: "12ab",
+	})
+
+	if !errors.Is(err, constants.ErrInvalidPhoneNumber) {
+		t.Fatalf("expected ErrInvalidPhoneNumber, got %v", err)
+	}
+}
+
+func TestUserService_UpdateUser_PhoneNumberAlreadyExists(t *testing.T) {
+	phone := "+62 812 3456 7890"
+
+	repo := &mockUserRepo{
+		getByIDFn: func(ctx context.Context, tx *gorm.DB, id string) (model.User, bool, error) {
+			return model.User{ID: uuid.MustParse(id)}, true, nil
+		},
+		getByPhoneNumberFn: func(ctx context.Context, phoneNumber string) (model.User, bool, error) {
+			if phoneNumber != "+6281234567890" {
+				t.Fatalf("expected lookup by normalized number, got %q", phoneNumber)
+			}
+			return model.User{ID: uuid.New()}, true, nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	us.phoneUnique = true
+
+	_, err := us.UpdateUser(context.Background(), dto.UpdateUserRequest{
+		ID:          uuid.NewString(),
+		PhoneNumber: &phone,
+	})
+
+	if !errors.Is(err, constants.ErrPhoneNumberAlreadyExists) {
+		t.Fatalf("expected ErrPhoneNumberAlreadyExists, got %v", err)
+	}
+}
+
+func TestUserService_CreateUser_PhoneNumberTakenConcurrently(t *testing.T) {
+	inserted := false
+
+	repo := &mockUserRepo{
+		getByPhoneNumberFn: func(ctx context.Context, phoneNumber string) (model.User, bool, error) {
+			if !inserted {
+				return model.User{}, false, gorm.ErrRecordNotFound
+			}
+			return model.User{ID: uuid.New(), PhoneNumber: phoneNumber}, true, nil
+		},
+		createFn: func(ctx context.Context, user model.User) error {
+			// Another request stored the same number between the check and
+			// the insert, and the unique index caught it.
+			inserted = true
+			return gorm.ErrDuplicatedKey
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	us.phoneUnique = true
+
+	_, err := us.CreateUser(context.Background(), dto.CreateUserRequest{
+		Name:        "Som User",
+		Email:       "test@mail.com",
+		Password:    "password123",
+		PhoneNumber: "+62 812 3456 7890",
+	})
+
+	if !errors.Is(err, constants.ErrPhoneNumberAlreadyExists) {
+		t.Fatalf("expected ErrPhoneNumberAlreadyExists, got %v", err)
+	}
+}
+
+// Activity
+func TestUserService_Login_RecordsLogin(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var recordedIP string
+	repo := &mockUserRepo{
+		getByEmailFn: func(ctx context.Context, email string) (model.User, bool, error) {
+			return model.User{ID: uuid.New(), Email: email, Password: string(hashedPassword), Role: constants.ENUM_ROLE_USER}, true, nil
+		},
+		recordLoginFn: func(ctx context.Context, userID string, loginAt time.Time, ip string) error {
+			recordedIP = ip
+			return nil
+		},
+	}
+
+	jwt := &mockJWTService{
+		generateFn: func(userID, role string) (string, string, error) {
+			return "access", "refresh", nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, jwt, &mockMailService{})
+
+	_, err = us.Login(context.Background(), dto.LoginUserRequest{
+		Email:    "test@mail.com",
+		Password: "password123",
+		IP:       "203.0.113.7",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if recordedIP != "203.0.113.7" {
+		t.Fatalf("expected login ip to be recorded, got %q", recordedIP)
+	}
+}
+
+func TestUserService_TouchLastSeen_OnlyUpdatesStaleValue(t *testing.T) {
+	var gotSeenAt, gotStaleBefore time.Time
+	repo := &mockUserRepo{
+		touchLastSeenFn: func(ctx context.Context, userID string, seenAt, staleBefore time.Time) error {
+			gotSeenAt, gotStaleBefore = seenAt, staleBefore
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	us.TouchLastSeen(context.Background(), uuid.NewString())
+
+	if gotSeenAt.Sub(gotStaleBefore) != getLastSeenThrottle() {
+		t.Fatalf("expected values older than the throttle interval to be replaced, got %v", gotSeenAt.Sub(gotStaleBefore))
+	}
+}
+
+func TestUserService_TouchLastSeen_ThrottlesWithinInterval(t *testing.T) {
+	calls := 0
+	repo := &mockUserRepo{
+		touchLastSeenFn: func(ctx context.Context, userID string, seenAt, staleBefore time.Time) error {
+			calls++
+			return nil
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	userID := uuid.NewString()
+	us.TouchLastSeen(context.Background(), userID)
+	us.TouchLastSeen(context.Background(), userID)
+
+	if calls != 1 {
+		t.Fatalf("expected 1 repository call within the throttle interval, got %d", calls)
+	}
+}
+
+func TestUserService_TouchLastSeen_RetriesAfterFailure(t *testing.T) {
+	calls := 0
+	repo := &mockUserRepo{
+		touchLastSeenFn: func(ctx context.Context, userID string, seenAt, staleBefore time.Time) error {
+			calls++
+			return errors.New("db down")
+		},
+	}
+
+	us := NewUserService(repo, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+	userID := uuid.NewString()
+	us.TouchLastSeen(context.Background(), userID)
+	us.TouchLastSeen(context.Background(), userID)
+
+	if calls != 2 {
+		t.Fatalf("expected a failed touch to be retried, got %d calls", calls)
+	}
+}
+
+func TestUserService_GetAllUserWithPagination_NegativeInactiveDays(t *testing.T) {
+	us := NewUserService(&mockUserRepo{}, &mockCustomFieldRepo{}, &mockJWTService{}, &mockMailService{})
+
+	_, err := us.GetAllUserWithPagination(context.Background(), dto.UserPaginationRequest{InactiveDays: -1})
+	if !errors.Is(err, constants.ErrInvalidInactiveDays) {
+		t.Fatalf("expected ErrInvalidInactiveDays, got %v", err)
+	}
+}
@@ -1,6 +1,11 @@
 package utils
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"github.com/mferdian/golang_boiller_plate/dto"
+)
 
 type Response struct {
 	Status    bool      `json:"status"`
@@ -9,6 +14,12 @@ type Response struct {
 	Data      any       `json:"data,omitempty"`
 	Error     any       `json:"error,omitempty"`
 	Meta      any       `json:"meta,omitempty"`
+
+	// Code names the failure for clients to branch on, such as user_not_found
+	Code string `json:"code,omitempty"`
+
+	// Errors lists every field that failed validation
+	Errors []dto.FieldError `json:"errors,omitempty"`
 }
 
 func BuildResponseSuccess(message string, data any) Response {
@@ -33,3 +44,23 @@ func BuildResponseFailed(message string, err string, data any) Response {
 
 	return res
 }
+
+// BuildResponseValidationFailed reports the fields of a request that failed
+// validation. error still carries all of them in one string for clients that
+// only read that.
+func BuildResponseValidationFailed(message string, fields []dto.FieldError) Response {
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field.Message)
+	}
+
+	res := Response{
+		Status:    false,
+		Messsage:  message,
+		Error:     strings.Join(messages, "; "),
+		Errors:    fields,
+		Timestamp: time.Now().UTC(),
+	}
+
+	return res
+}